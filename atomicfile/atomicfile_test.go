@@ -0,0 +1,41 @@
+package atomicfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/atomicfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_ReplacesFile(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o644))
+
+	// Act
+	err := atomicfile.Write(path, []byte("new"), 0o600)
+
+	// Assert - the file is replaced and no temporary file is left behind
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestWrite_MissingDirectory(t *testing.T) {
+	// Act
+	err := atomicfile.Write(filepath.Join(t.TempDir(), "missing", "state.json"), []byte("new"), 0o600)
+
+	// Assert
+	assert.Error(t, err)
+}
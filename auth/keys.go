@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Token signing algorithms accepted for JWTs
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+// ErrKeyNotFound is returned when no key can verify a token
+var ErrKeyNotFound = errors.New("verification key not found")
+
+// KeySet resolves the key that verifies a token signed with alg.
+// kid is the token's key ID and may be empty.
+type KeySet interface {
+	VerificationKey(kid, alg string) (interface{}, error)
+}
+
+// VerificationKey is a key that verifies signatures made with one algorithm.
+// Key is a []byte secret for HS256, *rsa.PublicKey for RS256 and
+// *ecdsa.PublicKey for ES256.
+type VerificationKey struct {
+	ID        string
+	Algorithm string
+	Key       interface{}
+}
+
+// StaticKeySet is a fixed set of verification keys
+type StaticKeySet []VerificationKey
+
+// VerificationKey returns the first key for alg whose ID matches kid.
+// A token without kid, or a key without ID, matches any key for the algorithm.
+func (s StaticKeySet) VerificationKey(kid, alg string) (interface{}, error) {
+	for _, key := range s {
+		if key.Algorithm == alg && (kid == "" || key.ID == "" || key.ID == kid) {
+			return key.Key, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// HMACKey returns an HS256 key for secret
+func HMACKey(id string, secret []byte) VerificationKey {
+	return VerificationKey{ID: id, Algorithm: AlgHS256, Key: secret}
+}
+
+// PublicKeyFromPEM parses a PEM-encoded RSA or P-256 public key or certificate
+func PublicKeyFromPEM(id string, data []byte) (VerificationKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return VerificationKey{}, errors.New("no PEM data found")
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return VerificationKey{}, err
+	}
+
+	return publicVerificationKey(id, key)
+}
+
+// publicVerificationKey picks the algorithm for a parsed public key
+func publicVerificationKey(id string, key interface{}) (VerificationKey, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return VerificationKey{ID: id, Algorithm: AlgRS256, Key: k}, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return VerificationKey{}, errors.New("only P-256 EC keys are supported")
+		}
+		return VerificationKey{ID: id, Algorithm: AlgES256, Key: k}, nil
+	default:
+		return VerificationKey{}, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// jwk is a single JSON Web Key (RFC 7517); only the fields used for
+// signature verification are read
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// Symmetric
+	K string `json:"k"`
+}
+
+// LoadJWKSFile reads a JWKS document from path
+func LoadJWKSFile(path string) (StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWKS file %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// ParseJWKS parses a JWKS document. Keys meant for encryption are skipped.
+func ParseJWKS(data []byte) (StaticKeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	keys := make(StaticKeySet, 0, len(doc.Keys))
+	for i, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %d (%q): %w", i, k.Kid, err)
+		}
+		if k.Alg != "" && k.Alg != key.Algorithm {
+			return nil, fmt.Errorf("key %d (%q): unsupported algorithm %q", i, k.Kid, k.Alg)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (k jwk) verificationKey() (VerificationKey, error) {
+	switch k.Kty {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return VerificationKey{}, err
+		}
+		return HMACKey(k.Kid, secret), nil
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return VerificationKey{}, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return VerificationKey{}, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return VerificationKey{}, errors.New("RSA exponent too large")
+		}
+		return publicVerificationKey(k.Kid, &rsa.PublicKey{N: n, E: int(e.Int64())})
+	case "EC":
+		if k.Crv != "P-256" {
+			return VerificationKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return VerificationKey{}, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return VerificationKey{}, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		// ECDH conversion rejects points that are not on the curve
+		if _, err := key.ECDH(); err != nil {
+			return VerificationKey{}, err
+		}
+		return publicVerificationKey(k.Kid, key)
+	default:
+		return VerificationKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBigInt decodes a base64url-encoded big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty integer")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
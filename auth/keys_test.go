@@ -0,0 +1,132 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestParseJWKS(t *testing.T) {
+	// Arrange
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	doc, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "alg": "RS256", "use": "sig",
+				"n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256",
+				"x": b64(ecKey.X.Bytes()), "y": b64(ecKey.Y.Bytes())},
+			{"kty": "oct", "kid": "hmac-1", "k": b64([]byte("shared-secret"))},
+			{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		},
+	})
+	require.NoError(t, err)
+
+	// Act
+	keys, err := auth.ParseJWKS(doc)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	key, err := keys.VerificationKey("rsa-1", auth.AlgRS256)
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	key, err = keys.VerificationKey("ec-1", auth.AlgES256)
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+
+	key, err = keys.VerificationKey("hmac-1", auth.AlgHS256)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("shared-secret"), key)
+
+	// A key is only returned for its own algorithm
+	_, err = keys.VerificationKey("rsa-1", auth.AlgHS256)
+	assert.ErrorIs(t, err, auth.ErrKeyNotFound)
+	_, err = keys.VerificationKey("enc-1", auth.AlgRS256)
+	assert.ErrorIs(t, err, auth.ErrKeyNotFound)
+}
+
+func TestParseJWKS_RejectsInvalidKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  map[string]string
+	}{
+		{"unknown key type", map[string]string{"kty": "OKP", "crv": "Ed25519", "x": "AQAB"}},
+		{"unsupported curve", map[string]string{"kty": "EC", "crv": "P-384", "x": "AQAB", "y": "AQAB"}},
+		{"point off the curve", map[string]string{"kty": "EC", "crv": "P-256", "x": "AQAB", "y": "AQAB"}},
+		{"algorithm mismatch", map[string]string{"kty": "oct", "alg": "HS512", "k": "c2VjcmV0"}},
+		{"bad encoding", map[string]string{"kty": "RSA", "n": "not base64!", "e": "AQAB"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			doc, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{tt.key}})
+			require.NoError(t, err)
+
+			// Act
+			_, err = auth.ParseJWKS(doc)
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPublicKeyFromPEM(t *testing.T) {
+	// Arrange
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	encode := func(pub interface{}) []byte {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+
+	// Act
+	rsaVerification, rsaErr := auth.PublicKeyFromPEM("rsa", encode(&rsaKey.PublicKey))
+	ecVerification, ecErr := auth.PublicKeyFromPEM("ec", encode(&ecKey.PublicKey))
+	_, garbageErr := auth.PublicKeyFromPEM("bad", []byte("not a key"))
+
+	// Assert
+	require.NoError(t, rsaErr)
+	assert.Equal(t, auth.AlgRS256, rsaVerification.Algorithm)
+	require.NoError(t, ecErr)
+	assert.Equal(t, auth.AlgES256, ecVerification.Algorithm)
+	assert.Error(t, garbageErr)
+}
+
+func TestStaticKeySet_KeyWithoutIDMatchesAnyKid(t *testing.T) {
+	// Arrange
+	keys := auth.StaticKeySet{auth.HMACKey("", []byte("secret"))}
+
+	// Act
+	key, err := keys.VerificationKey("rotated-kid", auth.AlgHS256)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), key)
+}
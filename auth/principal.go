@@ -0,0 +1,111 @@
+// Package auth describes the authenticated caller of a request and carries it
+// through the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidCredentials is returned when a credential is unknown, expired or revoked
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Scopes grant access to groups of endpoints
+const (
+	ScopeBookingsRead  = "bookings:read"
+	ScopeBookingsWrite = "bookings:write"
+	ScopeAdmin         = "admin"
+)
+
+// Roles decide which bookings a caller may see and change
+const (
+	// RoleCustomer may only access bookings of its own user
+	RoleCustomer = "customer"
+	// RoleOperator may see and cancel every user's pending bookings
+	RoleOperator = "operator"
+	// RoleAdmin may additionally force-cancel confirmed bookings
+	RoleAdmin = "admin"
+)
+
+// ValidRole reports whether role is a known role
+func ValidRole(role string) bool {
+	switch role {
+	case RoleCustomer, RoleOperator, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Authentication methods recorded on a Principal
+const (
+	MethodAPIKey = "apikey"
+	MethodJWT    = "jwt"
+)
+
+// ValidScope reports whether scope is a known scope
+func ValidScope(scope string) bool {
+	switch scope {
+	case ScopeBookingsRead, ScopeBookingsWrite, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScopesForRoles returns the scopes of a user session holding roles. Every
+// user may read and write bookings; the admin role adds the admin scope.
+func ScopesForRoles(roles []string) []string {
+	scopes := []string{ScopeBookingsRead, ScopeBookingsWrite}
+	for _, role := range roles {
+		if role == RoleAdmin {
+			return append(scopes, ScopeAdmin)
+		}
+	}
+	return scopes
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// ID identifies the credential, e.g. the API key ID or the token subject
+	ID string
+	// UserID is the user the caller acts for; 0 for service credentials
+	UserID int64
+	Roles  []string
+	Scopes []string
+	// Method is the authentication method that produced the principal
+	Method string
+}
+
+// HasScope reports whether the principal was granted scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the principal holds role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
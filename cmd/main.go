@@ -1,20 +1,50 @@
 package main
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/swagger"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/config"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/credit"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/handler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/health"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/idempotency"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/metrics"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/ratelimit"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/rediscache"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/router"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/scheduler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	// Import swagger generated docs
 	_ "github.com/hydr0g3nz/spd-fiber-booking-system/docs"
+
+	// Embedded SQLite driver for the SQL repository
+	_ "modernc.org/sqlite"
 )
 
 // @title Fiber Booking System API
@@ -31,24 +61,55 @@ import (
 // @in header
 // @name X-API-Key
 // @description API key authentication
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token, sent as "Bearer <token>"
 func main() {
-	// Initialize Fiber app
-	app := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			// Default error handling
-			code := fiber.StatusInternalServerError
+	// run returns instead of exiting so its deferred cleanup always happens
+	if err := run(); err != nil {
+		slog.Error("Server stopped", "error", err)
+		os.Exit(1)
+	}
+}
 
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
+// run loads the configuration and serves the API until a shutdown signal
+// arrives or the server fails, then drains in-flight work
+func run() error {
+	loaded, err := config.Load(os.Args[0], os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load configuration: %w", err)
+	}
+	cfg := loaded.Config
+	if loaded.PrintConfig {
+		return config.Print(os.Stdout, cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
 
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
+	slog.SetDefault(newLogger(cfg.Log))
+	if loaded.File != "" {
+		slog.Info("Loaded configuration", "file", loaded.File)
+	}
+
+	tracerProvider, closeTraces, err := newTracerProvider(cfg.Tracing, cfg.Server.ServiceName)
+	if err != nil {
+		return fmt.Errorf("create trace exporter: %w", err)
+	}
+	defer closeTraces()
+	tracing.Install(tracerProvider)
+
+	// Initialize Fiber app; errors are answered as application/problem+json
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
 	})
 
 	// Add global middleware
+	app.Use(middleware.RequestID())
 	app.Use(cors.New())
 	app.Use(recover.New())
 
@@ -60,16 +121,447 @@ func main() {
 	}))
 
 	// Initialize dependencies
-	cache := utils.NewInMemoryCache()
-	bookingRepo := repository.NewBookingRepositoryMock()
-	bookingUseCase := usecase.NewBookingUseCase(bookingRepo, cache)
+	appMetrics := metrics.New()
+	bookingCache, cacheCheck, closeCache := newBookingCache(cfg.Cache, cfg.Server.ServiceName)
+	defer closeCache()
+	bookingCache = utils.NewInstrumentedCache(bookingCache, appMetrics)
+	bookingRepo, apiKeyRepo, closeRepo, err := newRepositories(context.Background(), cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("initialize repositories: %w", err)
+	}
+	defer closeRepo()
+	jobStore, err := newJobStore(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("initialize job store: %w", err)
+	}
+	jobQueue := jobs.NewQueue(jobStore, jobs.Options{
+		Concurrency:     cfg.Jobs.Concurrency,
+		MaxAttempts:     cfg.Jobs.MaxAttempts,
+		FailedRetention: cfg.Jobs.FailedRetention,
+	})
+	idempotencyStore, err := newIdempotencyStore(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("initialize idempotency store: %w", err)
+	}
+	bookingUseCase := usecase.NewTracedBookingUseCase(usecase.NewBookingUseCase(
+		repository.NewTracedBookingRepository(bookingRepo), bookingCache, newCreditChecker(cfg.Credit), jobQueue,
+		usecase.WithHighValueThreshold(cfg.Bookings.HighValueThreshold),
+		usecase.WithNotFoundTTL(cfg.Cache.NotFoundTTL),
+		usecase.WithExpiryPolicy(newExpiryPolicy(cfg.Bookings)),
+		usecase.WithCreditCheckPolicy(newCreditCheckPolicy(cfg.Credit)),
+		usecase.WithMetrics(appMetrics)))
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo)
+	if err := bootstrapAdminKey(context.Background(), apiKeyUseCase, cfg.Auth.AdminAPIKey); err != nil {
+		return fmt.Errorf("bootstrap admin API key: %w", err)
+	}
 	bookingHandler := handler.NewBookingHandler(bookingUseCase)
+	jobHandler := handler.NewJobHandler(jobQueue)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUseCase)
+
+	// Load the JWT keys before any background work starts
+	authMode := middleware.AuthMode(cfg.Auth.Mode)
+	slog.Info("Using authentication", "mode", authMode)
+	jwtConfig, err := newJWTConfig(authMode, cfg.Auth.JWT)
+	if err != nil {
+		return fmt.Errorf("load JWT keys: %w", err)
+	}
+
+	// Start background job workers once all handlers are registered
+	if err := jobQueue.Start(context.Background()); err != nil {
+		return fmt.Errorf("start job queue: %w", err)
+	}
+
+	// Start the expiry scheduler
+	expiryInterval := cfg.Bookings.ExpiryInterval
+	expiryScheduler := scheduler.NewExpiryScheduler(bookingUseCase, expiryInterval)
+	// The scheduler acts as the service itself, not on behalf of a user
+	if err := expiryScheduler.Start(usecase.WithInternalCaller(context.Background())); err != nil {
+		jobQueue.Stop()
+		return fmt.Errorf("start expiry scheduler: %w", err)
+	}
+
+	// Report the goroutines running background work
+	appMetrics.TrackGoroutines("job_worker", jobQueue.Workers)
+	appMetrics.TrackGoroutines("expiry_scheduler", func() int {
+		if expiryScheduler.Running() {
+			return 1
+		}
+		return 0
+	})
+
+	// Health checks; expiry that has not completed a run for a few intervals is considered wedged
+	healthChecker := health.NewChecker(cfg.Health.CheckTimeout)
+	healthChecker.AddLivenessCheck("expiry_scheduler", health.HeartbeatCheck(expiryScheduler, 3*expiryInterval))
+	healthChecker.AddReadinessCheck("repository", health.PingCheck(bookingRepo))
+	healthChecker.AddReadinessCheck("cache", cacheCheck)
+	healthChecker.AddReadinessCheck("job_queue", health.BacklogCheck(jobQueue, cfg.Health.MaxPendingJobs))
 
 	// Setup routes
-	router.SetupRoutes(app, bookingHandler)
+	router.SetupRoutes(app, router.Dependencies{
+		BookingHandler: bookingHandler,
+		JobHandler:     jobHandler,
+		APIKeyHandler:  apiKeyHandler,
+		HealthHandler:  handler.NewHealthHandler(healthChecker),
+		AuthMode:       authMode,
+		Authenticator:  apiKeyUseCase,
+		JWT:            jwtConfig,
+		Idempotency: middleware.IdempotencyConfig{
+			Store:     idempotencyStore,
+			Retention: cfg.Idempotency.Retention,
+		},
+		RateLimits: newRateLimits(cfg.RateLimits),
+		Metrics:    appMetrics,
+	})
 
 	// Start server
-	log.Println("Starting server on :3000")
-	log.Println("API documentation available at http://localhost:3000/swagger/")
-	log.Fatal(app.Listen("127.0.0.1:3000"))
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting server", "addr", cfg.Server.Address, "docs", "http://"+cfg.Server.Address+"/swagger/")
+		serverErr <- app.Listen(cfg.Server.Address)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var listenErr error
+	select {
+	case sig := <-signals:
+		slog.Info("Shutting down", "signal", sig.String())
+	case listenErr = <-serverErr:
+		slog.Error("Server failed, shutting down", "error", listenErr)
+	}
+
+	shutdownErr := shutdown(cfg.Shutdown, shutdownTargets{
+		app:             app,
+		healthChecker:   healthChecker,
+		expiryScheduler: expiryScheduler,
+		jobQueue:        jobQueue,
+		jobStore:        jobStore,
+	})
+	return errors.Join(listenErr, shutdownErr)
+}
+
+// shutdownTargets holds what the shutdown sequence stops
+type shutdownTargets struct {
+	app             *fiber.App
+	healthChecker   *health.Checker
+	expiryScheduler *scheduler.ExpiryScheduler
+	jobQueue        *jobs.Queue
+	jobStore        jobs.Store
+}
+
+// shutdown fails readiness, stops accepting requests, waits for in-flight
+// requests, stops the expiry scheduler and waits for running jobs such as
+// credit checks, all within cfg.Timeout. Jobs that could not finish are
+// kept by a persistent job store and logged otherwise.
+func shutdown(cfg config.Shutdown, targets shutdownTargets) error {
+	targets.healthChecker.Drain()
+	if cfg.DrainDelay > 0 {
+		slog.Info("Draining before shutdown", "delay", cfg.DrainDelay)
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	var errs []error
+	if err := targets.app.ShutdownWithTimeout(time.Until(deadline)); err != nil {
+		errs = append(errs, fmt.Errorf("shut down HTTP server: %w", err))
+	}
+	slog.Info("HTTP server stopped")
+
+	targets.expiryScheduler.Stop()
+	slog.Info("Expiry scheduler stopped")
+
+	if err := targets.jobQueue.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("wait for running jobs: %w", err))
+	}
+	reportUnfinishedJobs(targets.jobStore)
+
+	if len(errs) == 0 {
+		slog.Info("Shutdown complete")
+	}
+	return errors.Join(errs...)
+}
+
+// reportUnfinishedJobs logs the jobs left in store. A persistent store runs
+// them after the next start; jobs in the in-memory store are lost, so each is
+// logged in full for manual recovery.
+func reportUnfinishedJobs(store jobs.Store) {
+	unfinished, err := store.List(context.Background())
+	if err != nil {
+		slog.Error("Error listing unfinished jobs", "error", err)
+		return
+	}
+
+	pending := make([]*jobs.Job, 0, len(unfinished))
+	for _, job := range unfinished {
+		if job.Status != jobs.StatusFailed {
+			pending = append(pending, job)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	if _, inMemory := store.(*jobs.MemoryStore); !inMemory {
+		slog.Info("Unfinished jobs will run after restart", "count", len(pending))
+		return
+	}
+	for _, job := range pending {
+		slog.Warn("Unfinished job lost at shutdown",
+			"job_id", job.ID, "job_type", job.Type, "payload", string(job.Payload), logging.RequestIDKey, job.RequestID)
+	}
+}
+
+// newLogger builds the structured logger writing to stderr
+func newLogger(cfg config.Log) *slog.Logger {
+	format, _ := logging.ParseFormat(cfg.Format)
+	return logging.New(os.Stderr, logging.Config{Level: cfg.Level, Format: format})
+}
+
+// newTracerProvider builds the tracer provider exporting spans to stdout or
+// appending them to a file, or dropping them with the none exporter. The
+// returned function flushes the pending spans.
+func newTracerProvider(cfg config.Tracing, serviceName string) (*sdktrace.TracerProvider, func(), error) {
+	exporter, closeExporter, err := tracing.NewExporter(tracing.Config{
+		Exporter:    cfg.Exporter,
+		File:        cfg.File,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	provider := tracing.NewProvider(exporter, serviceName)
+
+	return provider, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			slog.Error("Error flushing traces", "error", err)
+		}
+		if err := closeExporter(); err != nil {
+			slog.Error("Error closing trace file", "error", err)
+		}
+	}, nil
+}
+
+// newRepositories selects the repository implementations.
+// The sql driver stores bookings and API keys in the database at cfg.DSN,
+// the file driver keeps bookings in memory backed by a write-ahead log in
+// cfg.DataDir and API keys in DataDir/api_keys.json;
+// the memory driver uses the in-memory repositories.
+func newRepositories(ctx context.Context, cfg config.Storage) (repository.BookingRepository, repository.APIKeyRepository, func(), error) {
+	switch cfg.Driver {
+	case config.DriverSQL:
+		db, err := sql.Open("sqlite", cfg.DSN)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// SQLite allows a single writer; serialize access through one connection
+		db.SetMaxOpenConns(1)
+
+		if err := repository.Migrate(ctx, db); err != nil {
+			db.Close()
+			return nil, nil, nil, err
+		}
+
+		slog.Info("Using SQL booking repository")
+		return repository.NewBookingRepositorySQL(db), repository.NewAPIKeyRepositorySQL(db), func() { db.Close() }, nil
+	case config.DriverFile:
+		repo, err := repository.OpenBookingRepositoryFile(cfg.DataDir, repository.FileRepositoryOptions{})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		apiKeys, err := repository.OpenAPIKeyRepositoryFile(filepath.Join(cfg.DataDir, "api_keys.json"))
+		if err != nil {
+			repo.Close()
+			return nil, nil, nil, err
+		}
+
+		slog.Info("Using file booking repository", "dir", cfg.DataDir)
+		return repo, apiKeys, func() { repo.Close() }, nil
+	default:
+		slog.Info("Using in-memory booking repository")
+		return repository.NewBookingRepositoryMock(), repository.NewAPIKeyRepositoryMemory(), func() {}, nil
+	}
+}
+
+// newBookingCache selects the booking cache and its readiness check.
+// The redis driver shares bookings between instances through the server at
+// cfg.Redis.Address, under keys prefixed with the service name; the memory
+// driver keeps a cache per instance.
+func newBookingCache(cfg config.Cache, serviceName string) (usecase.BookingCache, health.Check, func()) {
+	if cfg.Driver == config.CacheRedis {
+		client := rediscache.NewClient(rediscache.Options{
+			Address:     cfg.Redis.Address,
+			Password:    cfg.Redis.Password,
+			DB:          cfg.Redis.DB,
+			PoolSize:    cfg.Redis.PoolSize,
+			DialTimeout: cfg.Redis.DialTimeout,
+			Timeout:     cfg.Redis.Timeout,
+		})
+		cache := rediscache.NewCache[int64, *models.Booking](client, rediscache.JSONCodec[*models.Booking]{}, rediscache.CacheOptions{
+			Prefix:         serviceName + ":" + usecase.BookingCacheNamespace + ":",
+			TTL:            cfg.TTL,
+			NearTTL:        cfg.Redis.NearTTL,
+			NearMaxEntries: cfg.MaxEntries,
+		})
+
+		slog.Info("Using redis booking cache", "address", cfg.Redis.Address)
+		return cache, health.CacheCheck(cache), func() {
+			cache.Close()
+			client.Close()
+		}
+	}
+
+	store := utils.NewInMemoryCache[utils.CacheKey, any](
+		utils.WithCacheTTL(cfg.TTL),
+		utils.WithMaxEntries(cfg.MaxEntries),
+		utils.WithCleanupInterval(cfg.CleanupInterval))
+	slog.Info("Using in-memory booking cache")
+	return usecase.NewBookingCache(store), health.CacheCheck(store), store.Close
+}
+
+// bootstrapAdminKey makes sure an admin can reach the key management endpoints.
+// adminKey, when configured, is registered as an admin key; without it, an
+// empty key store gets a freshly issued admin key whose secret is logged once.
+func bootstrapAdminKey(ctx context.Context, apiKeys usecase.APIKeyUseCase, adminKey string) error {
+	if adminKey != "" {
+		return apiKeys.EnsureAdminKey(ctx, adminKey)
+	}
+
+	keys, err := apiKeys.ListKeys(ctx)
+	if err != nil || len(keys) > 0 {
+		return err
+	}
+
+	issued, err := apiKeys.IssueKey(ctx, &dto.IssueAPIKeyRequest{
+		Name:   "bootstrap admin",
+		Roles:  []string{auth.RoleAdmin},
+		Scopes: []string{auth.ScopeAdmin, auth.ScopeBookingsRead, auth.ScopeBookingsWrite},
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Warn("No API keys found; issued an admin key (store it now, it will not be shown again)", "secret", issued.Secret)
+	return nil
+}
+
+// newJobStore selects where background jobs are kept. With a persistent booking
+// repository jobs are stored in DataDir/jobs.json so they survive a restart;
+// the in-memory repository keeps its jobs in memory too.
+func newJobStore(cfg config.Storage) (jobs.Store, error) {
+	if cfg.Persistent() {
+		return jobs.OpenFileStore(filepath.Join(cfg.DataDir, "jobs.json"))
+	}
+	return jobs.NewMemoryStore(), nil
+}
+
+// newJWTConfig builds the JWT verification settings. Keys are loaded from a
+// JWKS file, a PEM RSA or P-256 public key file and an HS256 secret; the
+// issuer and audience restrict the accepted tokens.
+func newJWTConfig(mode middleware.AuthMode, cfg config.JWT) (middleware.JWTConfig, error) {
+	jwtConfig := middleware.JWTConfig{
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+	}
+	if mode == middleware.AuthModeAPIKey {
+		return jwtConfig, nil
+	}
+
+	var keys auth.StaticKeySet
+	if cfg.JWKSFile != "" {
+		jwks, err := auth.LoadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return jwtConfig, fmt.Errorf("load JWKS file: %w", err)
+		}
+		keys = append(keys, jwks...)
+	}
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return jwtConfig, fmt.Errorf("read public key file: %w", err)
+		}
+		key, err := auth.PublicKeyFromPEM("", data)
+		if err != nil {
+			return jwtConfig, fmt.Errorf("parse public key file: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if cfg.HS256Secret != "" {
+		keys = append(keys, auth.HMACKey("", []byte(cfg.HS256Secret)))
+	}
+	jwtConfig.Keys = keys
+
+	return jwtConfig, nil
+}
+
+// newIdempotencyStore selects where Idempotency-Key records are kept. Like jobs,
+// they are stored in DataDir/idempotency.json with a persistent booking repository
+// so a retry after a restart does not create a second booking.
+func newIdempotencyStore(cfg config.Storage) (idempotency.Store, error) {
+	if cfg.Persistent() {
+		return idempotency.OpenFileStore(filepath.Join(cfg.DataDir, "idempotency.json"))
+	}
+	return idempotency.NewMemoryStore(), nil
+}
+
+// newRateLimits builds the per route group limits; a disabled limit ("off")
+// lets every request through. Buckets are kept in memory, so each instance
+// enforces its own limits.
+func newRateLimits(cfg config.RateLimits) router.RateLimits {
+	return router.RateLimits{
+		Store:         ratelimit.NewMemoryStore(),
+		PerIP:         cfg.IP,
+		BookingReads:  cfg.Read,
+		BookingWrites: cfg.Write,
+		Admin:         cfg.Admin,
+	}
+}
+
+// newCreditChecker selects the credit checker implementation: the http checker
+// calls the service at cfg.URL, the rules checker approves prices up to
+// cfg.Limit or the user's own limit unless the user or service is blacklisted,
+// and the random checker is a demo that answers after cfg.DemoDelay.
+func newCreditChecker(cfg config.Credit) usecase.CreditChecker {
+	switch cfg.Checker {
+	case config.CheckerHTTP:
+		slog.Info("Using HTTP credit checker", "url", cfg.URL)
+		// The attempt's context already carries cfg.Timeout; the client's
+		// own timeout also bounds reading a response body that stalls
+		return credit.NewHTTPChecker(cfg.URL, &http.Client{Timeout: cfg.Timeout})
+	case config.CheckerRules:
+		slog.Info("Using rules credit checker", "limit", cfg.Limit, "user_limits", len(cfg.UserLimits),
+			"blacklisted_users", len(cfg.BlacklistedUsers), "blacklisted_services", len(cfg.BlacklistedServices))
+		return credit.NewRulesChecker(credit.RulesConfig{
+			DefaultLimit:        cfg.Limit,
+			UserLimits:          cfg.UserLimits,
+			BlacklistedUsers:    cfg.BlacklistedUsers,
+			BlacklistedServices: cfg.BlacklistedServices,
+		})
+	default:
+		slog.Info("Using random demo credit checker", "delay", cfg.DemoDelay, "rejection_rate", cfg.DemoRejectionRate)
+		return credit.NewRandomChecker(cfg.DemoDelay, cfg.DemoRejectionRate)
+	}
+}
+
+// newCreditCheckPolicy bounds each credit check call and its retries
+func newCreditCheckPolicy(cfg config.Credit) usecase.CreditCheckPolicy {
+	return usecase.CreditCheckPolicy{
+		Timeout:      cfg.Timeout,
+		MaxAttempts:  cfg.MaxAttempts,
+		RetryBackoff: cfg.RetryBackoff,
+	}
+}
+
+// newExpiryPolicy builds the booking TTLs: the default TTL with per service overrides
+func newExpiryPolicy(cfg config.Bookings) usecase.ExpiryPolicy {
+	return usecase.ExpiryPolicy{
+		DefaultTTL:  cfg.DefaultTTL,
+		ServiceTTLs: cfg.ServiceTTLs,
+	}
 }
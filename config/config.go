@@ -0,0 +1,516 @@
+// Package config holds the typed configuration of the booking service. It is
+// built from the defaults, an optional YAML or JSON file, environment
+// variables and command-line flags, each overriding the previous, and is
+// validated once at startup.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/health"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/ratelimit"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/rediscache"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/scheduler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+)
+
+// Storage drivers
+const (
+	DriverMemory = "memory"
+	DriverSQL    = "sql"
+	DriverFile   = "file"
+)
+
+// Cache drivers
+const (
+	CacheMemory = "memory"
+	CacheRedis  = "redis"
+)
+
+// Credit checkers
+const (
+	CheckerRandom = "random"
+	CheckerRules  = "rules"
+	CheckerHTTP   = "http"
+)
+
+// Config is the complete service configuration. The env tag names the
+// environment variable overriding a field; the flag overriding it is named
+// after its path in the file, e.g. --server.address.
+type Config struct {
+	Server      Server      `yaml:"server"`
+	Log         Log         `yaml:"log"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Storage     Storage     `yaml:"storage"`
+	Cache       Cache       `yaml:"cache"`
+	Bookings    Bookings    `yaml:"bookings"`
+	Credit      Credit      `yaml:"credit"`
+	Jobs        Jobs        `yaml:"jobs"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	Auth        Auth        `yaml:"auth"`
+	RateLimits  RateLimits  `yaml:"rate_limits"`
+	Health      Health      `yaml:"health"`
+	Shutdown    Shutdown    `yaml:"shutdown"`
+}
+
+// Server configures the HTTP server
+type Server struct {
+	// Address is the host:port the server listens on
+	Address     string `yaml:"address" env:"LISTEN_ADDR"`
+	ServiceName string `yaml:"service_name" env:"SERVICE_NAME"`
+}
+
+// Log configures structured logging
+type Log struct {
+	Level  slog.Level `yaml:"level" env:"LOG_LEVEL"`
+	Format string     `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// Tracing configures where spans are exported
+type Tracing struct {
+	Exporter string `yaml:"exporter" env:"TRACE_EXPORTER"`
+	File     string `yaml:"file" env:"TRACE_FILE"`
+}
+
+// Storage selects the repositories and where their data lives
+type Storage struct {
+	Driver string `yaml:"driver" env:"REPOSITORY_DRIVER"`
+	// DSN is the database opened by the sql driver
+	DSN string `yaml:"dsn" env:"DATABASE_DSN"`
+	// DataDir holds the file driver's data, and jobs and idempotency records of persistent drivers
+	DataDir string `yaml:"data_dir" env:"DATA_DIR"`
+}
+
+// Persistent reports whether the driver keeps data across restarts
+func (s Storage) Persistent() bool {
+	return s.Driver == DriverSQL || s.Driver == DriverFile
+}
+
+// Cache configures the booking cache
+type Cache struct {
+	// Driver is memory for a cache per instance or redis for one shared by every instance
+	Driver string `yaml:"driver" env:"CACHE_DRIVER"`
+	// TTL is how long a booking stays cached; zero keeps it until evicted
+	TTL time.Duration `yaml:"ttl" env:"CACHE_TTL"`
+	// MaxEntries bounds the cache, evicting the least recently used booking; zero means unbounded
+	MaxEntries int `yaml:"max_entries" env:"CACHE_MAX_ENTRIES"`
+	// CleanupInterval is how often expired bookings are dropped; zero only drops them on lookup
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"CACHE_CLEANUP_INTERVAL"`
+	// NotFoundTTL is how long a booking ID that was not found is remembered; zero looks it up every time
+	NotFoundTTL time.Duration `yaml:"not_found_ttl" env:"CACHE_NOT_FOUND_TTL"`
+	Redis       Redis         `yaml:"redis"`
+}
+
+// Redis configures the server of the redis cache driver. MaxEntries and
+// CleanupInterval do not apply to it; the server evicts by its own policy.
+type Redis struct {
+	Address     string        `yaml:"address" env:"REDIS_ADDR"`
+	Password    string        `yaml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB          int           `yaml:"db" env:"REDIS_DB"`
+	PoolSize    int           `yaml:"pool_size" env:"REDIS_POOL_SIZE"`
+	DialTimeout time.Duration `yaml:"dial_timeout" env:"REDIS_DIAL_TIMEOUT"`
+	Timeout     time.Duration `yaml:"timeout" env:"REDIS_TIMEOUT"`
+	// NearTTL keeps a local copy of bookings read from the server for this
+	// long, dropped when another instance changes them; zero disables it
+	NearTTL time.Duration `yaml:"near_ttl" env:"REDIS_NEAR_TTL"`
+}
+
+// Bookings configures booking rules
+type Bookings struct {
+	// HighValueThreshold is the price above which bookings need a credit check
+	HighValueThreshold float64 `yaml:"high_value_threshold" env:"HIGH_VALUE_THRESHOLD"`
+	// DefaultTTL is how long a booking may stay pending
+	DefaultTTL     time.Duration `yaml:"default_ttl" env:"BOOKING_TTL"`
+	ServiceTTLs    ServiceTTLs   `yaml:"service_ttls" env:"SERVICE_TTLS"`
+	ExpiryInterval time.Duration `yaml:"expiry_interval" env:"EXPIRY_INTERVAL"`
+}
+
+// Credit configures credit checks
+type Credit struct {
+	Checker string `yaml:"checker" env:"CREDIT_CHECKER"`
+	// URL is called by the http checker
+	URL string `yaml:"url" env:"CREDIT_CHECK_URL"`
+	// Limit is the highest price the rules checker approves
+	Limit float64 `yaml:"limit" env:"CREDIT_LIMIT"`
+	// UserLimits overrides Limit per user ID for the rules checker
+	UserLimits UserLimits `yaml:"user_limits" env:"CREDIT_USER_LIMITS"`
+	// BlacklistedUsers and BlacklistedServices are always rejected by the rules checker
+	BlacklistedUsers    IDList `yaml:"blacklisted_users" env:"CREDIT_BLACKLISTED_USERS"`
+	BlacklistedServices IDList `yaml:"blacklisted_services" env:"CREDIT_BLACKLISTED_SERVICES"`
+	// DemoDelay and DemoRejectionRate shape the random demo checker
+	DemoDelay         time.Duration `yaml:"demo_delay" env:"CREDIT_DEMO_DELAY"`
+	DemoRejectionRate float64       `yaml:"demo_rejection_rate" env:"CREDIT_DEMO_REJECTION_RATE"`
+	Timeout           time.Duration `yaml:"timeout" env:"CREDIT_CHECK_TIMEOUT"`
+	MaxAttempts       int           `yaml:"max_attempts" env:"CREDIT_CHECK_MAX_ATTEMPTS"`
+	RetryBackoff      time.Duration `yaml:"retry_backoff" env:"CREDIT_CHECK_RETRY_BACKOFF"`
+}
+
+// Jobs configures the background job queue
+type Jobs struct {
+	Concurrency int `yaml:"concurrency" env:"JOB_CONCURRENCY"`
+	MaxAttempts int `yaml:"max_attempts" env:"JOB_MAX_ATTEMPTS"`
+	// FailedRetention is how long dead-lettered jobs are kept before they are deleted
+	FailedRetention time.Duration `yaml:"failed_retention" env:"JOB_FAILED_RETENTION"`
+}
+
+// Idempotency configures Idempotency-Key handling
+type Idempotency struct {
+	Retention time.Duration `yaml:"retention" env:"IDEMPOTENCY_RETENTION"`
+}
+
+// Auth configures authentication
+type Auth struct {
+	Mode string `yaml:"mode" env:"AUTH_MODE"`
+	// AdminAPIKey is registered as an admin key at startup
+	AdminAPIKey string `yaml:"admin_api_key" env:"ADMIN_API_KEY" secret:"true"`
+	// MinAPIKeyLength is the shortest AdminAPIKey accepted
+	MinAPIKeyLength int `yaml:"min_api_key_length" env:"API_KEY_MIN_LENGTH"`
+	JWT             JWT `yaml:"jwt"`
+}
+
+// JWT configures bearer token verification
+type JWT struct {
+	JWKSFile      string `yaml:"jwks_file" env:"JWT_JWKS_FILE"`
+	PublicKeyFile string `yaml:"public_key_file" env:"JWT_PUBLIC_KEY_FILE"`
+	HS256Secret   string `yaml:"hs256_secret" env:"JWT_HS256_SECRET" secret:"true"`
+	Issuer        string `yaml:"issuer" env:"JWT_ISSUER"`
+	Audience      string `yaml:"audience" env:"JWT_AUDIENCE"`
+}
+
+// RateLimits holds the limit of each route group, e.g. "30/1m" or "10/1s,burst=20"
+type RateLimits struct {
+	IP    ratelimit.Limit `yaml:"ip" env:"RATE_LIMIT_IP"`
+	Read  ratelimit.Limit `yaml:"read" env:"RATE_LIMIT_READ"`
+	Write ratelimit.Limit `yaml:"write" env:"RATE_LIMIT_WRITE"`
+	Admin ratelimit.Limit `yaml:"admin" env:"RATE_LIMIT_ADMIN"`
+}
+
+// Health configures the health checks
+type Health struct {
+	CheckTimeout time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+	// MaxPendingJobs fails readiness above this backlog; zero disables the limit
+	MaxPendingJobs int `yaml:"max_pending_jobs" env:"HEALTH_MAX_PENDING_JOBS"`
+}
+
+// Shutdown bounds the shutdown sequence
+type Shutdown struct {
+	// DrainDelay is how long readiness fails before the server stops accepting connections
+	DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	// Timeout bounds the time in-flight requests and jobs get to finish
+	Timeout time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// Default returns the configuration used for anything not configured
+func Default() *Config {
+	creditPolicy := usecase.DefaultCreditCheckPolicy()
+	jobOptions := jobs.DefaultOptions()
+	redisOptions := rediscache.DefaultOptions()
+	minute := func(requests int) ratelimit.Limit {
+		return ratelimit.Limit{Requests: requests, Per: time.Minute}
+	}
+
+	return &Config{
+		Server: Server{
+			Address:     "127.0.0.1:3000",
+			ServiceName: "booking-system",
+		},
+		Log: Log{
+			Level:  slog.LevelInfo,
+			Format: logging.FormatJSON,
+		},
+		Tracing: Tracing{
+			Exporter: tracing.ExporterNone,
+			File:     "traces.json",
+		},
+		Storage: Storage{
+			Driver:  DriverMemory,
+			DSN:     "file:bookings.db?_pragma=busy_timeout(5000)",
+			DataDir: "data",
+		},
+		Cache: Cache{
+			Driver:          CacheMemory,
+			TTL:             10 * time.Minute,
+			MaxEntries:      10000,
+			CleanupInterval: time.Minute,
+			NotFoundTTL:     usecase.DefaultNotFoundTTL,
+			Redis: Redis{
+				Address:     "localhost:6379",
+				PoolSize:    redisOptions.PoolSize,
+				DialTimeout: redisOptions.DialTimeout,
+				Timeout:     redisOptions.Timeout,
+				NearTTL:     30 * time.Second,
+			},
+		},
+		Bookings: Bookings{
+			HighValueThreshold: usecase.DefaultHighValueThreshold,
+			DefaultTTL:         usecase.DefaultExpiryPolicy().DefaultTTL,
+			ExpiryInterval:     scheduler.DefaultExpiryInterval,
+		},
+		Credit: Credit{
+			Checker:           CheckerRandom,
+			Limit:             100000,
+			DemoDelay:         2 * time.Second,
+			DemoRejectionRate: 0.3,
+			Timeout:           creditPolicy.Timeout,
+			MaxAttempts:       creditPolicy.MaxAttempts,
+			RetryBackoff:      creditPolicy.RetryBackoff,
+			// Empty rather than nil, so they print as empty and read back the same
+			UserLimits:          UserLimits{},
+			BlacklistedUsers:    IDList{},
+			BlacklistedServices: IDList{},
+		},
+		Jobs: Jobs{
+			Concurrency:     jobOptions.Concurrency,
+			MaxAttempts:     jobOptions.MaxAttempts,
+			FailedRetention: jobOptions.FailedRetention,
+		},
+		Idempotency: Idempotency{
+			Retention: middleware.DefaultIdempotencyRetention,
+		},
+		Auth: Auth{
+			Mode:            string(middleware.AuthModeAPIKey),
+			MinAPIKeyLength: 10,
+		},
+		RateLimits: RateLimits{
+			IP:    minute(300),
+			Read:  minute(120),
+			Write: minute(30),
+			Admin: minute(60),
+		},
+		Health: Health{
+			CheckTimeout:   health.DefaultTimeout,
+			MaxPendingJobs: 1000,
+		},
+		Shutdown: Shutdown{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	positive := func(name string, d time.Duration) {
+		check(d > 0, "%s must be a positive duration, got %s", name, d)
+	}
+
+	check(c.Server.Address != "", "server.address is required")
+	check(c.Server.ServiceName != "", "server.service_name is required")
+
+	_, err := logging.ParseFormat(c.Log.Format)
+	check(err == nil, "log.format must be json or text, got %q", c.Log.Format)
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout:
+	case tracing.ExporterFile:
+		check(c.Tracing.File != "", "tracing.file is required with the file exporter")
+	default:
+		check(false, "tracing.exporter must be none, stdout or file, got %q", c.Tracing.Exporter)
+	}
+
+	switch c.Storage.Driver {
+	case DriverMemory:
+	case DriverSQL:
+		check(c.Storage.DSN != "", "storage.dsn is required with the sql driver")
+	case DriverFile:
+	default:
+		check(false, "storage.driver must be memory, sql or file, got %q", c.Storage.Driver)
+	}
+	if c.Storage.Persistent() {
+		check(c.Storage.DataDir != "", "storage.data_dir is required with the %s driver", c.Storage.Driver)
+	}
+
+	check(c.Cache.TTL >= 0, "cache.ttl must not be negative")
+	check(c.Cache.MaxEntries >= 0, "cache.max_entries must not be negative")
+	check(c.Cache.CleanupInterval >= 0, "cache.cleanup_interval must not be negative")
+	check(c.Cache.NotFoundTTL >= 0, "cache.not_found_ttl must not be negative")
+	switch c.Cache.Driver {
+	case CacheMemory:
+	case CacheRedis:
+		check(c.Cache.Redis.Address != "", "cache.redis.address is required with the redis driver")
+		check(c.Cache.Redis.DB >= 0, "cache.redis.db must not be negative")
+		check(c.Cache.Redis.PoolSize > 0, "cache.redis.pool_size must be positive")
+		positive("cache.redis.dial_timeout", c.Cache.Redis.DialTimeout)
+		positive("cache.redis.timeout", c.Cache.Redis.Timeout)
+		check(c.Cache.Redis.NearTTL >= 0, "cache.redis.near_ttl must not be negative")
+	default:
+		check(false, "cache.driver must be memory or redis, got %q", c.Cache.Driver)
+	}
+
+	check(c.Bookings.HighValueThreshold > 0, "bookings.high_value_threshold must be positive")
+	positive("bookings.default_ttl", c.Bookings.DefaultTTL)
+	for serviceID, ttl := range c.Bookings.ServiceTTLs {
+		positive(fmt.Sprintf("bookings.service_ttls[%d]", serviceID), ttl)
+	}
+	positive("bookings.expiry_interval", c.Bookings.ExpiryInterval)
+
+	switch c.Credit.Checker {
+	case CheckerRandom:
+		check(c.Credit.DemoDelay >= 0, "credit.demo_delay must not be negative")
+		check(c.Credit.DemoRejectionRate >= 0 && c.Credit.DemoRejectionRate <= 1,
+			"credit.demo_rejection_rate must be between 0 and 1, got %v", c.Credit.DemoRejectionRate)
+	case CheckerRules:
+		check(c.Credit.Limit > 0, "credit.limit must be positive")
+		for userID, limit := range c.Credit.UserLimits {
+			check(limit > 0, "credit.user_limits[%d] must be positive", userID)
+		}
+	case CheckerHTTP:
+		check(c.Credit.URL != "", "credit.url is required with the http checker")
+	default:
+		check(false, "credit.checker must be random, rules or http, got %q", c.Credit.Checker)
+	}
+	positive("credit.timeout", c.Credit.Timeout)
+	check(c.Credit.MaxAttempts >= 1, "credit.max_attempts must be at least 1")
+	check(c.Credit.RetryBackoff >= 0, "credit.retry_backoff must not be negative")
+
+	check(c.Jobs.Concurrency >= 1, "jobs.concurrency must be at least 1")
+	check(c.Jobs.MaxAttempts >= 1, "jobs.max_attempts must be at least 1")
+	positive("jobs.failed_retention", c.Jobs.FailedRetention)
+
+	positive("idempotency.retention", c.Idempotency.Retention)
+
+	mode := middleware.AuthMode(c.Auth.Mode)
+	check(mode.IsValid(), "auth.mode must be apikey, jwt or either, got %q", c.Auth.Mode)
+	check(c.Auth.MinAPIKeyLength >= 1, "auth.min_api_key_length must be at least 1")
+	if c.Auth.AdminAPIKey != "" {
+		check(len(c.Auth.AdminAPIKey) >= c.Auth.MinAPIKeyLength,
+			"auth.admin_api_key must be at least %d characters", c.Auth.MinAPIKeyLength)
+	}
+	if mode == middleware.AuthModeJWT || mode == middleware.AuthModeEither {
+		jwt := c.Auth.JWT
+		check(jwt.JWKSFile != "" || jwt.PublicKeyFile != "" || jwt.HS256Secret != "",
+			"auth.jwt needs jwks_file, public_key_file or hs256_secret with the %s mode", mode)
+	}
+
+	positive("health.check_timeout", c.Health.CheckTimeout)
+	check(c.Health.MaxPendingJobs >= 0, "health.max_pending_jobs must not be negative")
+
+	check(c.Shutdown.DrainDelay >= 0, "shutdown.drain_delay must not be negative")
+	positive("shutdown.timeout", c.Shutdown.Timeout)
+
+	return errors.Join(errs...)
+}
+
+// ServiceTTLs overrides the booking TTL per service ID. In environment
+// variables and flags it is written as "201=10m,202=1h".
+type ServiceTTLs map[int64]time.Duration
+
+// Set replaces the TTLs with those listed in value
+func (t *ServiceTTLs) Set(value string) error {
+	ttls := ServiceTTLs{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, ttl, ok := strings.Cut(pair, "=")
+		serviceID, idErr := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		duration, ttlErr := time.ParseDuration(strings.TrimSpace(ttl))
+		if !ok || idErr != nil || ttlErr != nil {
+			return fmt.Errorf("invalid service TTL %q: expected service_id=duration", pair)
+		}
+		ttls[serviceID] = duration
+	}
+	*t = ttls
+	return nil
+}
+
+// String formats the TTLs the way Set reads them, ordered by service ID
+func (t ServiceTTLs) String() string {
+	ids := make([]int64, 0, len(t))
+	for id := range t {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	pairs := make([]string, len(ids))
+	for i, id := range ids {
+		pairs[i] = fmt.Sprintf("%d=%s", id, t[id])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// UserLimits overrides the credit limit per user ID. In environment variables
+// and flags it is written as "101=50000,102=250000".
+type UserLimits map[int64]float64
+
+// Set replaces the limits with those listed in value
+func (l *UserLimits) Set(value string) error {
+	limits := UserLimits{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, limit, ok := strings.Cut(pair, "=")
+		userID, idErr := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		amount, limitErr := strconv.ParseFloat(strings.TrimSpace(limit), 64)
+		if !ok || idErr != nil || limitErr != nil {
+			return fmt.Errorf("invalid user limit %q: expected user_id=amount", pair)
+		}
+		limits[userID] = amount
+	}
+	*l = limits
+	return nil
+}
+
+// String formats the limits the way Set reads them, ordered by user ID
+func (l UserLimits) String() string {
+	ids := make([]int64, 0, len(l))
+	for id := range l {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	pairs := make([]string, len(ids))
+	for i, id := range ids {
+		pairs[i] = fmt.Sprintf("%d=%s", id, strconv.FormatFloat(l[id], 'f', -1, 64))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// IDList is a list of user or service IDs. In environment variables and
+// flags it is written as "101,102".
+type IDList []int64
+
+// Set replaces the IDs with those listed in value
+func (l *IDList) Set(value string) error {
+	ids := IDList{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ID %q", field)
+		}
+		ids = append(ids, id)
+	}
+	*l = ids
+	return nil
+}
+
+// String formats the IDs the way Set reads them
+func (l IDList) String() string {
+	fields := make([]string, len(l))
+	for i, id := range l {
+		fields[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(fields, ",")
+}
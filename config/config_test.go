@@ -0,0 +1,243 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/config"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// env returns a lookup function over vars instead of the process environment
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestDefault_IsValid(t *testing.T) {
+	// Execute
+	cfg := config.Default()
+
+	// Assert
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "127.0.0.1:3000", cfg.Server.Address)
+	assert.Equal(t, 50000.0, cfg.Bookings.HighValueThreshold)
+	assert.Equal(t, 5*time.Minute, cfg.Bookings.DefaultTTL)
+	assert.Equal(t, 2*time.Second, cfg.Credit.DemoDelay)
+	assert.Equal(t, 10, cfg.Auth.MinAPIKeyLength)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	// Setup: each layer overrides part of the one before
+	file := writeFile(t, "config.yaml", `
+server:
+  address: 0.0.0.0:8080
+bookings:
+  high_value_threshold: 75000
+  default_ttl: 10m
+  service_ttls:
+    201: 1h
+rate_limits:
+  write: 10/1s,burst=20
+log:
+  level: debug
+`)
+	vars := map[string]string{
+		"CONFIG_FILE":          file,
+		"HIGH_VALUE_THRESHOLD": "80000",
+		"SERVICE_TTLS":         "202=30m",
+		"CREDIT_DEMO_DELAY":    "500ms",
+	}
+
+	// Execute
+	loaded, err := config.Load("booking", []string{"--bookings.high_value_threshold=90000", "--server.address", ":9000"}, env(vars))
+
+	// Assert
+	require.NoError(t, err)
+	cfg := loaded.Config
+	assert.Equal(t, file, loaded.File)
+	assert.False(t, loaded.PrintConfig)
+	assert.Equal(t, ":9000", cfg.Server.Address)
+	assert.Equal(t, 90000.0, cfg.Bookings.HighValueThreshold)
+	assert.Equal(t, 10*time.Minute, cfg.Bookings.DefaultTTL)
+	assert.Equal(t, config.ServiceTTLs{202: 30 * time.Minute}, cfg.Bookings.ServiceTTLs)
+	assert.Equal(t, ratelimit.Limit{Requests: 10, Per: time.Second, Burst: 20}, cfg.RateLimits.Write)
+	assert.Equal(t, slog.LevelDebug, cfg.Log.Level)
+	assert.Equal(t, 500*time.Millisecond, cfg.Credit.DemoDelay)
+	assert.Equal(t, config.Default().Jobs, cfg.Jobs)
+}
+
+func TestLoad_JSONFile(t *testing.T) {
+	// Setup
+	file := writeFile(t, "config.json", `{"storage": {"driver": "file", "data_dir": "/var/lib/booking"}, "jobs": {"concurrency": 8}}`)
+
+	// Execute
+	loaded, err := config.Load("booking", []string{"--config", file}, env(nil))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, config.DriverFile, loaded.Config.Storage.Driver)
+	assert.Equal(t, "/var/lib/booking", loaded.Config.Storage.DataDir)
+	assert.Equal(t, 8, loaded.Config.Jobs.Concurrency)
+}
+
+func TestLoad_CreditRules(t *testing.T) {
+	// Setup
+	file := writeFile(t, "config.yaml", `
+credit:
+  checker: rules
+  user_limits:
+    101: 250000
+  blacklisted_services: [7, 8]
+`)
+	vars := map[string]string{"CREDIT_BLACKLISTED_USERS": "103, 104"}
+
+	// Execute
+	loaded, err := config.Load("booking", []string{"--config", file}, env(vars))
+
+	// Assert
+	require.NoError(t, err)
+	cfg := loaded.Config.Credit
+	assert.Equal(t, config.UserLimits{101: 250000}, cfg.UserLimits)
+	assert.Equal(t, config.IDList{103, 104}, cfg.BlacklistedUsers)
+	assert.Equal(t, config.IDList{7, 8}, cfg.BlacklistedServices)
+}
+
+func TestLoad_RejectsUnknownFileKeys(t *testing.T) {
+	// Setup
+	file := writeFile(t, "config.yaml", "server:\n  adress: :8080\n")
+
+	// Execute
+	_, err := config.Load("booking", []string{"--config", file}, env(nil))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "adress")
+}
+
+func TestLoad_ReportsInvalidValues(t *testing.T) {
+	// Execute
+	_, err := config.Load("booking", []string{"--jobs.concurrency=many"}, env(map[string]string{"BOOKING_TTL": "soon"}))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BOOKING_TTL")
+	assert.Contains(t, err.Error(), "--jobs.concurrency")
+}
+
+func TestLoad_PrintConfig(t *testing.T) {
+	// Execute
+	loaded, err := config.Load("booking", []string{"--print-config"}, env(nil))
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, loaded.PrintConfig)
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	// Setup
+	cfg := config.Default()
+	cfg.Storage.Driver = "postgres"
+	cfg.Credit.Checker = config.CheckerHTTP
+	cfg.Bookings.HighValueThreshold = 0
+	cfg.Auth.AdminAPIKey = "short"
+	cfg.Auth.Mode = "jwt"
+	cfg.Cache.Driver = config.CacheRedis
+	cfg.Cache.Redis.Address = ""
+
+	// Execute
+	err := cfg.Validate()
+
+	// Assert
+	require.Error(t, err)
+	for _, want := range []string{
+		"storage.driver",
+		"credit.url",
+		"bookings.high_value_threshold",
+		"auth.admin_api_key must be at least 10 characters",
+		"auth.jwt",
+		"cache.redis.address",
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestServiceTTLs_SetAndString(t *testing.T) {
+	// Setup
+	var ttls config.ServiceTTLs
+
+	// Execute
+	err := ttls.Set("202=1h, 201=10m")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, config.ServiceTTLs{201: 10 * time.Minute, 202: time.Hour}, ttls)
+	assert.Equal(t, "201=10m0s,202=1h0m0s", ttls.String())
+	assert.Error(t, ttls.Set("201"))
+}
+
+func TestUserLimits_SetAndString(t *testing.T) {
+	// Setup
+	var limits config.UserLimits
+
+	// Execute
+	err := limits.Set("102=10000, 101=250000.5")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, config.UserLimits{101: 250000.5, 102: 10000}, limits)
+	assert.Equal(t, "101=250000.5,102=10000", limits.String())
+	assert.Error(t, limits.Set("101=lots"))
+}
+
+func TestIDList_SetAndString(t *testing.T) {
+	// Setup
+	var ids config.IDList
+
+	// Execute
+	err := ids.Set("103, 104,")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, config.IDList{103, 104}, ids)
+	assert.Equal(t, "103,104", ids.String())
+	assert.Error(t, ids.Set("103,x"))
+}
+
+func TestPrint_RedactsSecretsAndRoundTrips(t *testing.T) {
+	// Setup
+	cfg := config.Default()
+	cfg.Auth.AdminAPIKey = "super-secret-admin-key"
+	cfg.Bookings.ServiceTTLs = config.ServiceTTLs{201: time.Hour}
+	var buf bytes.Buffer
+
+	// Execute
+	require.NoError(t, config.Print(&buf, cfg))
+
+	// Assert
+	assert.NotContains(t, buf.String(), "super-secret-admin-key")
+	assert.Contains(t, buf.String(), "admin_api_key: REDACTED")
+	assert.Equal(t, "super-secret-admin-key", cfg.Auth.AdminAPIKey, "printing must not change the configuration")
+
+	file := writeFile(t, "printed.yaml", buf.String())
+	loaded, err := config.Load("booking", []string{"--config", file}, env(nil))
+	require.NoError(t, err)
+	loaded.Config.Auth.AdminAPIKey = cfg.Auth.AdminAPIKey
+	assert.Equal(t, cfg, loaded.Config)
+}
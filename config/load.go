@@ -0,0 +1,223 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable pointing at the configuration file
+// when --config is not given
+const FileEnv = "CONFIG_FILE"
+
+// redacted replaces secrets in printed configurations
+const redacted = "REDACTED"
+
+// Loaded is the result of Load
+type Loaded struct {
+	Config *Config
+	// File is the configuration file read, if any
+	File string
+	// PrintConfig is set by --print-config
+	PrintConfig bool
+}
+
+// Load builds the configuration from the defaults, the file named by --config
+// or CONFIG_FILE, the environment variables and finally the flags in args.
+// lookupEnv is normally os.LookupEnv. The result is not validated.
+func Load(name string, args []string, lookupEnv func(string) (string, bool)) (*Loaded, error) {
+	config := Default()
+	fields := fieldsOf(config)
+
+	type override struct {
+		field field
+		value string
+	}
+	var overrides []override
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	file := flags.String("config", "", "YAML or JSON configuration file (env "+FileEnv+")")
+	printConfig := flags.Bool("print-config", false, "print the effective configuration and exit")
+	for _, f := range fields {
+		f := f
+		flags.Func(f.path, "env "+f.env, func(value string) error {
+			overrides = append(overrides, override{field: f, value: value})
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	loaded := &Loaded{Config: config, File: *file, PrintConfig: *printConfig}
+	if loaded.File == "" {
+		loaded.File, _ = lookupEnv(FileEnv)
+	}
+	if loaded.File != "" {
+		if err := decodeFile(loaded.File, config); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if value, ok := lookupEnv(f.env); ok && value != "" {
+			if err := setValue(f.value, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+	for _, o := range overrides {
+		if err := setValue(o.field.value, o.value); err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", o.field.path, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+// decodeFile reads a YAML file into config; JSON is accepted as YAML.
+// Keys that match no setting are rejected so typos do not go unnoticed.
+func decodeFile(path string, config *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Print writes config as YAML with secrets redacted
+func Print(w io.Writer, config *Config) error {
+	printed := *config
+	for _, f := range fieldsOf(&printed) {
+		if f.secret && !f.value.IsZero() {
+			f.value.SetString(redacted)
+		}
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&printed); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// field is a single setting
+type field struct {
+	// path is the dotted key in the file, which is also the flag name
+	path   string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+// fieldsOf lists the settings of config in declaration order
+func fieldsOf(config *Config) []field {
+	return appendFields(nil, "", reflect.ValueOf(config).Elem())
+}
+
+func appendFields(fields []field, prefix string, v reflect.Value) []field {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		path := prefix + key
+		value := v.Field(i)
+
+		if sf.Type.Kind() == reflect.Struct && !isScalar(value) {
+			fields = appendFields(fields, path+".", value)
+			continue
+		}
+		fields = append(fields, field{
+			path:   path,
+			env:    sf.Tag.Get("env"),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  value,
+		})
+	}
+	return fields
+}
+
+// stringSetter is implemented by settings with their own string syntax
+type stringSetter interface {
+	Set(string) error
+}
+
+// isScalar reports whether v is set from a single string even though it may be a struct
+func isScalar(v reflect.Value) bool {
+	switch v.Addr().Interface().(type) {
+	case stringSetter, encoding.TextUnmarshaler:
+		return true
+	}
+	return false
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue parses value into v
+func setValue(v reflect.Value, value string) error {
+	switch target := v.Addr().Interface().(type) {
+	case stringSetter:
+		return target.Set(value)
+	case encoding.TextUnmarshaler:
+		return target.UnmarshalText([]byte(value))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
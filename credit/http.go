@@ -0,0 +1,96 @@
+package credit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// checkRequest is the JSON body sent to the credit check service
+type checkRequest struct {
+	BookingID int64   `json:"booking_id"`
+	UserID    int64   `json:"user_id"`
+	ServiceID int64   `json:"service_id"`
+	Price     float64 `json:"price"`
+}
+
+// checkResponse is the JSON body expected back from the credit check service
+type checkResponse struct {
+	Approved *bool  `json:"approved"`
+	Reason   string `json:"reason"`
+}
+
+// HTTPChecker asks a remote credit check service for a decision.
+// The service receives a POST with the booking as JSON and must answer
+// 200 with {"approved": bool, "reason": string}.
+type HTTPChecker struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPChecker creates a new instance of HTTPChecker.
+// Timeouts are driven by the request context, so client may be http.DefaultClient.
+func NewHTTPChecker(url string, client *http.Client) *HTTPChecker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPChecker{
+		url:    url,
+		client: client,
+	}
+}
+
+// Name returns the checker name recorded on bookings
+func (c *HTTPChecker) Name() string {
+	return "http"
+}
+
+// CheckCredit posts the booking to the credit check service and decodes its decision
+func (c *HTTPChecker) CheckCredit(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error) {
+	body, err := json.Marshal(checkRequest{
+		BookingID: booking.ID,
+		UserID:    booking.UserID,
+		ServiceID: booking.ServiceID,
+		Price:     booking.Price,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Drain a little of the body so the connection can be reused
+		_, _ = io.CopyN(io.Discard, resp.Body, 4096)
+		return nil, fmt.Errorf("credit check service returned status %d", resp.StatusCode)
+	}
+
+	var decoded checkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decode credit check response: %w", err)
+	}
+	if decoded.Approved == nil {
+		return nil, fmt.Errorf("credit check response has no decision")
+	}
+
+	return &models.CreditDecision{
+		Approved: *decoded.Approved,
+		Reason:   decoded.Reason,
+	}, nil
+}
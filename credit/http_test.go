@@ -0,0 +1,101 @@
+package credit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/credit"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ usecase.CreditChecker = (*credit.HTTPChecker)(nil)
+
+func TestHTTPChecker_Decision(t *testing.T) {
+	// Arrange - a local stub of the credit check service
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"approved": false, "reason": "insufficient history"}`))
+	}))
+	defer server.Close()
+
+	checker := credit.NewHTTPChecker(server.URL, server.Client())
+
+	// Act
+	decision, err := checker.CheckCredit(context.Background(), &models.Booking{
+		ID:        42,
+		UserID:    123,
+		ServiceID: 456,
+		Price:     75000,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, decision.Approved)
+	assert.Equal(t, "insufficient history", decision.Reason)
+	assert.Equal(t, float64(42), received["booking_id"])
+	assert.Equal(t, float64(75000), received["price"])
+}
+
+func TestHTTPChecker_ServerError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	checker := credit.NewHTTPChecker(server.URL, nil)
+
+	// Act
+	decision, err := checker.CheckCredit(context.Background(), &models.Booking{ID: 1})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Nil(t, decision)
+}
+
+func TestHTTPChecker_MissingDecision(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"reason": "?"}`))
+	}))
+	defer server.Close()
+
+	checker := credit.NewHTTPChecker(server.URL, nil)
+
+	// Act
+	_, err := checker.CheckCredit(context.Background(), &models.Booking{ID: 1})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestHTTPChecker_RespectsContextTimeout(t *testing.T) {
+	// Arrange - the stub answers too slowly
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	checker := credit.NewHTTPChecker(server.URL, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := checker.CheckCredit(ctx, &models.Booking{ID: 1})
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,57 @@
+// Package credit provides implementations of usecase.CreditChecker.
+package credit
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// RandomChecker simulates an external credit bureau for demos:
+// it waits for a fixed delay and then rejects a fixed share of bookings at random
+type RandomChecker struct {
+	delay      time.Duration
+	rejectRate float64
+	rng        *rand.Rand
+	mu         sync.Mutex
+}
+
+// NewRandomChecker creates a new instance of RandomChecker
+func NewRandomChecker(delay time.Duration, rejectRate float64) *RandomChecker {
+	return &RandomChecker{
+		delay:      delay,
+		rejectRate: rejectRate,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Name returns the checker name recorded on bookings
+func (c *RandomChecker) Name() string {
+	return "random"
+}
+
+// CheckCredit waits for the simulated processing time and returns a random decision
+func (c *RandomChecker) CheckCredit(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error) {
+	// Simulate some processing time
+	timer := time.NewTimer(c.delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	c.mu.Lock()
+	roll := c.rng.Float64()
+	c.mu.Unlock()
+
+	if roll < c.rejectRate {
+		return &models.CreditDecision{Approved: false, Reason: "randomly rejected (demo checker)"}, nil
+	}
+
+	return &models.CreditDecision{Approved: true, Reason: "randomly approved (demo checker)"}, nil
+}
@@ -0,0 +1,44 @@
+package credit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/credit"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ usecase.CreditChecker = (*credit.RandomChecker)(nil)
+
+func TestRandomChecker_Extremes(t *testing.T) {
+	// Arrange
+	alwaysApprove := credit.NewRandomChecker(0, 0)
+	alwaysReject := credit.NewRandomChecker(0, 1)
+
+	// Act
+	approved, err := alwaysApprove.CheckCredit(context.Background(), &models.Booking{})
+	assert.NoError(t, err)
+	rejected, err := alwaysReject.CheckCredit(context.Background(), &models.Booking{})
+	assert.NoError(t, err)
+
+	// Assert
+	assert.True(t, approved.Approved)
+	assert.False(t, rejected.Approved)
+}
+
+func TestRandomChecker_CanceledWhileWaiting(t *testing.T) {
+	// Arrange
+	checker := credit.NewRandomChecker(time.Minute, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	decision, err := checker.CheckCredit(ctx, &models.Booking{})
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, decision)
+}
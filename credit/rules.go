@@ -0,0 +1,86 @@
+package credit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// RulesConfig configures RulesChecker
+type RulesConfig struct {
+	// DefaultLimit is the highest price approved for users without their own limit; 0 means no limit
+	DefaultLimit float64
+	// UserLimits overrides DefaultLimit per user ID
+	UserLimits map[int64]float64
+	// BlacklistedUsers are always rejected
+	BlacklistedUsers []int64
+	// BlacklistedServices are always rejected
+	BlacklistedServices []int64
+}
+
+// RulesChecker makes deterministic credit decisions from per-user limits and blacklists
+type RulesChecker struct {
+	defaultLimit        float64
+	userLimits          map[int64]float64
+	blacklistedUsers    map[int64]bool
+	blacklistedServices map[int64]bool
+}
+
+// NewRulesChecker creates a new instance of RulesChecker
+func NewRulesChecker(config RulesConfig) *RulesChecker {
+	checker := &RulesChecker{
+		defaultLimit:        config.DefaultLimit,
+		userLimits:          make(map[int64]float64, len(config.UserLimits)),
+		blacklistedUsers:    make(map[int64]bool, len(config.BlacklistedUsers)),
+		blacklistedServices: make(map[int64]bool, len(config.BlacklistedServices)),
+	}
+
+	for userID, limit := range config.UserLimits {
+		checker.userLimits[userID] = limit
+	}
+	for _, userID := range config.BlacklistedUsers {
+		checker.blacklistedUsers[userID] = true
+	}
+	for _, serviceID := range config.BlacklistedServices {
+		checker.blacklistedServices[serviceID] = true
+	}
+
+	return checker
+}
+
+// Name returns the checker name recorded on bookings
+func (c *RulesChecker) Name() string {
+	return "rules"
+}
+
+// CheckCredit applies the blacklists first, then the user's price limit
+func (c *RulesChecker) CheckCredit(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error) {
+	if c.blacklistedUsers[booking.UserID] {
+		return &models.CreditDecision{
+			Approved: false,
+			Reason:   fmt.Sprintf("user %d is blacklisted", booking.UserID),
+		}, nil
+	}
+
+	if c.blacklistedServices[booking.ServiceID] {
+		return &models.CreditDecision{
+			Approved: false,
+			Reason:   fmt.Sprintf("service %d is blacklisted", booking.ServiceID),
+		}, nil
+	}
+
+	limit, ok := c.userLimits[booking.UserID]
+	if !ok {
+		limit = c.defaultLimit
+	}
+
+	if limit > 0 && booking.Price > limit {
+		return &models.CreditDecision{
+			Approved: false,
+			Reason:   fmt.Sprintf("price %.2f exceeds credit limit %.2f", booking.Price, limit),
+		}, nil
+	}
+
+	return &models.CreditDecision{Approved: true, Reason: "price within credit limit"}, nil
+}
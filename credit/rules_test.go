@@ -0,0 +1,61 @@
+package credit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/credit"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ usecase.CreditChecker = (*credit.RulesChecker)(nil)
+
+func TestRulesChecker(t *testing.T) {
+	// Arrange
+	checker := credit.NewRulesChecker(credit.RulesConfig{
+		DefaultLimit:        100000,
+		UserLimits:          map[int64]float64{7: 250000},
+		BlacklistedUsers:    []int64{13},
+		BlacklistedServices: []int64{666},
+	})
+
+	tests := []struct {
+		name     string
+		booking  models.Booking
+		approved bool
+		reason   string
+	}{
+		{"within default limit", models.Booking{UserID: 1, ServiceID: 1, Price: 90000}, true, "price within credit limit"},
+		{"over default limit", models.Booking{UserID: 1, ServiceID: 1, Price: 150000}, false, "price 150000.00 exceeds credit limit 100000.00"},
+		{"per-user limit overrides default", models.Booking{UserID: 7, ServiceID: 1, Price: 150000}, true, "price within credit limit"},
+		{"blacklisted user", models.Booking{UserID: 13, ServiceID: 1, Price: 60000}, false, "user 13 is blacklisted"},
+		{"blacklisted service", models.Booking{UserID: 1, ServiceID: 666, Price: 60000}, false, "service 666 is blacklisted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			decision, err := checker.CheckCredit(context.Background(), &tt.booking)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.approved, decision.Approved)
+			assert.Equal(t, tt.reason, decision.Reason)
+		})
+	}
+}
+
+func TestRulesChecker_NoLimit(t *testing.T) {
+	// Arrange
+	checker := credit.NewRulesChecker(credit.RulesConfig{})
+
+	// Act
+	decision, err := checker.CheckCredit(context.Background(), &models.Booking{Price: 1e9})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, decision.Approved)
+	assert.Equal(t, "rules", checker.Name())
+}
@@ -23,14 +23,325 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/jobs/failed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List background jobs that used up their attempts and were moved to the dead-letter list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List failed jobs",
+                "responses": {
+                    "200": {
+                        "description": "Failed jobs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/jobs.Job"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/jobs/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a failed job back to the queue with a fresh set of attempts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Retry a failed job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requeued job",
+                        "schema": {
+                            "$ref": "#/definitions/jobs.Job"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Job has not failed",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every issued API key, including expired and revoked keys. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "API keys",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "API key lacks the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue a new API key. The secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Issue an API key",
+                "parameters": [
+                    {
+                        "description": "API key details",
+                        "name": "key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.IssueAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Issued key and its secret",
+                        "schema": {
+                            "$ref": "#/definitions/dto.IssuedAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "API key lacks the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke an API key; requests using it are rejected from then on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revoked key",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKey"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "API key lacks the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/bookings": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all bookings with optional sorting and filtering",
+                "description": "List bookings one page at a time with optional filtering and sorting. Pass next_cursor from a page as cursor to get the next one, keeping the same sort and order.\nCustomers only see their own bookings.",
                 "consumes": [
                     "application/json"
                 ],
@@ -40,47 +351,131 @@ const docTemplate = `{
                 "tags": [
                     "bookings"
                 ],
-                "summary": "Get all bookings",
+                "summary": "List bookings",
                 "parameters": [
                     {
+                        "enum": [
+                            "id",
+                            "price",
+                            "date"
+                        ],
                         "type": "string",
-                        "description": "Sort by field (price or date)",
+                        "description": "Sort by field (id, price or date)",
                         "name": "sort",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
-                        "description": "Filter high-value bookings (price \u003e 50,000)",
+                        "description": "Filter high-value bookings (price above the configured threshold, 50,000 by default)",
                         "name": "high-value",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "confirmed",
+                            "rejected",
+                            "canceled"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by service ID",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum price, inclusive",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum price, inclusive",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after this RFC 3339 time",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before this RFC 3339 time",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of bookings",
+                        "description": "Page of bookings",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BookingListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters or cursor",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Booking"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Customers can only list their own bookings",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -89,9 +484,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Create a new booking with the provided details",
+                "description": "Create a new booking with the provided details. Pending bookings are canceled at expires_at, which defaults to the service TTL.\nRetries sent with the same Idempotency-Key get the original response instead of creating another booking.",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,6 +509,13 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.CreateBookingRequest"
                         }
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "description": "Client-chosen key that makes the request safe to retry",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -118,33 +523,54 @@ const docTemplate = `{
                         "description": "Created booking",
                         "schema": {
                             "$ref": "#/definitions/models.Booking"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "Set to true when the response was replayed for a retry"
+                            }
                         }
                     },
                     "400": {
                         "description": "Invalid request parameters",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Customers can only create bookings for themselves",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with the same Idempotency-Key is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key was already used for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -155,9 +581,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Get detailed information about a specific booking",
+                "description": "Get detailed information about a specific booking. The ETag header carries the booking version.",
                 "consumes": [
                     "application/json"
                 ],
@@ -176,6 +605,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return the booking if its ETag matches",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -183,33 +618,42 @@ const docTemplate = `{
                         "description": "Booking details",
                         "schema": {
                             "$ref": "#/definitions/models.Booking"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Booking version"
+                            }
                         }
                     },
                     "400": {
                         "description": "Invalid booking ID format",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "404": {
                         "description": "Booking not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Booking version does not match If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -218,9 +662,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Cancel an existing booking by its ID",
+                "description": "Cancel an existing booking by its ID. Send If-Match with the ETag from GET to cancel only an unchanged booking.",
                 "consumes": [
                     "application/json"
                 ],
@@ -239,6 +686,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the booking version being canceled",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -246,33 +699,54 @@ const docTemplate = `{
                         "description": "Canceled booking details",
                         "schema": {
                             "$ref": "#/definitions/models.Booking"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Booking version"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid booking ID or cannot cancel",
+                        "description": "Invalid booking ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Only admins can cancel a confirmed booking",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "404": {
                         "description": "Booking not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Booking was modified concurrently (conflict) or cannot be canceled in its status (invalid_transition)",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Booking version does not match If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -280,6 +754,22 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "dto.BookingListResponse": {
+            "description": "One page of bookings and the cursor of the next page",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Booking"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoiaWQiLCJpIjoyMH0"
+                }
+            }
+        },
         "dto.CreateBookingRequest": {
             "description": "Request payload for creating a new booking",
             "type": "object",
@@ -289,6 +779,12 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt overrides the service TTL for this booking",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-11T12:30:00Z"
+                },
                 "price": {
                     "type": "number",
                     "example": 30000
@@ -303,6 +799,186 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.IssueAPIKeyRequest": {
+            "description": "Request payload for issuing an API key",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "mobile app"
+                },
+                "owner_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "roles": {
+                    "description": "Roles defaults to customer, which requires an owner",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "customer"
+                    ]
+                },
+                "scopes": {
+                    "description": "Scopes defaults to bookings:read and bookings:write",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "bookings:read",
+                        "bookings:write"
+                    ]
+                }
+            }
+        },
+        "dto.IssuedAPIKeyResponse": {
+            "description": "The secret is only returned once; store it securely",
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/models.APIKey"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "bk_Xy3k9QaZ..."
+                }
+            }
+        },
+        "jobs.Job": {
+            "description": "Background job and its retry state",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-11T12:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f2b9c1e8a7d4c3b"
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "credit check failed"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                },
+                "run_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-11T12:00:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jobs.Status"
+                        }
+                    ],
+                    "example": "failed"
+                },
+                "trace_parent": {
+                    "type": "string",
+                    "example": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "credit_check"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-11T12:00:00Z"
+                }
+            }
+        },
+        "jobs.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusRunning",
+                "StatusFailed"
+            ]
+        },
+        "models.APIKey": {
+            "description": "API key metadata; the secret is never returned after issue",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3f2a9c1e7b6d4a58"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "mobile app"
+                },
+                "owner_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the secret, shown so a key can be recognised",
+                    "type": "string",
+                    "example": "bk_Xy3k9QaZ"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "customer"
+                    ]
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "bookings:read",
+                        "bookings:write"
+                    ]
+                }
+            }
+        },
         "models.Booking": {
             "description": "Booking entity representing a customer's service booking",
             "type": "object",
@@ -312,6 +988,19 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2024-03-11T12:00:00Z"
                 },
+                "credit_check": {
+                    "description": "CreditCheck is set once the credit check of a high-value booking has run",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CreditCheck"
+                        }
+                    ]
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-11T12:05:00Z"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -340,6 +1029,10 @@ const docTemplate = `{
                 "user_id": {
                     "type": "integer",
                     "example": 123
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
@@ -357,6 +1050,151 @@ const docTemplate = `{
                 "BookingStatusRejected",
                 "BookingStatusCanceled"
             ]
+        },
+        "models.CreditCheck": {
+            "description": "Outcome of the credit check run for a high-value booking",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "checked_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-11T12:00:02Z"
+                },
+                "checker": {
+                    "type": "string",
+                    "example": "rules"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "outcome": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CreditCheckOutcome"
+                        }
+                    ],
+                    "example": "approved"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "price within limit"
+                },
+                "timed_out": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.CreditCheckOutcome": {
+            "type": "string",
+            "enum": [
+                "approved",
+                "rejected",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "CreditCheckApproved",
+                "CreditCheckRejected",
+                "CreditCheckFailed"
+            ]
+        },
+        "problem.Code": {
+            "type": "string",
+            "enum": [
+                "bad_request",
+                "validation_failed",
+                "unauthorized",
+                "forbidden",
+                "not_found",
+                "method_not_allowed",
+                "conflict",
+                "invalid_transition",
+                "precondition_failed",
+                "idempotency_key_reused",
+                "idempotency_key_in_use",
+                "rate_limited",
+                "unavailable",
+                "internal_error"
+            ],
+            "x-enum-varnames": [
+                "CodeBadRequest",
+                "CodeValidation",
+                "CodeUnauthorized",
+                "CodeForbidden",
+                "CodeNotFound",
+                "CodeMethodNotAllowed",
+                "CodeConflict",
+                "CodeInvalidTransition",
+                "CodePreconditionFailed",
+                "CodeIdempotencyKeyReused",
+                "CodeIdempotencyKeyInUse",
+                "CodeRateLimited",
+                "CodeUnavailable",
+                "CodeInternal"
+            ]
+        },
+        "problem.InvalidParam": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "price"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "must be positive"
+                }
+            }
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/problem.Code"
+                        }
+                    ],
+                    "example": "not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "booking not found"
+                },
+                "instance": {
+                    "description": "Instance is the path of the request that failed",
+                    "type": "string",
+                    "example": "/api/bookings/42"
+                },
+                "invalid_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problem.InvalidParam"
+                    }
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f2a9c1e8b7d4a60"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "description": "Type is a URI identifying the problem type, derived from Code",
+                    "type": "string",
+                    "example": "urn:problem-type:booking-system:not_found"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -365,6 +1203,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "X-API-Key",
             "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT bearer token, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// IssueAPIKeyRequest is the DTO for issuing a new API key
+// @Description Request payload for issuing an API key
+type IssueAPIKeyRequest struct {
+	Name    string `json:"name" validate:"required" example:"mobile app" description:"Human-readable label"`
+	OwnerID int64  `json:"owner_id" example:"123" description:"User the key acts for; 0 for service keys"`
+	// Roles defaults to customer, which requires an owner
+	Roles []string `json:"roles,omitempty" example:"customer" description:"Roles (customer, operator, admin)"`
+	// Scopes defaults to bookings:read and bookings:write
+	Scopes    []string   `json:"scopes,omitempty" example:"bookings:read,bookings:write" description:"Granted scopes (bookings:read, bookings:write, admin)"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time" example:"2025-01-01T00:00:00Z" description:"Time after which the key is no longer accepted"`
+}
+
+// IssuedAPIKeyResponse carries a newly issued key together with its secret
+// @Description The secret is only returned once; store it securely
+type IssuedAPIKeyResponse struct {
+	Secret string         `json:"secret" example:"bk_Xy3k9QaZ..." description:"API key to send in the X-API-Key header"`
+	APIKey *models.APIKey `json:"api_key"`
+}
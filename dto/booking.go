@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
 
 // CreateBookingRequest is the DTO for creating a new booking
 // @Description Request payload for creating a new booking
@@ -8,6 +12,8 @@ type CreateBookingRequest struct {
 	UserID    int64   `json:"user_id" validate:"required" example:"123" description:"User ID"`
 	ServiceID int64   `json:"service_id" validate:"required" example:"456" description:"Service ID"`
 	Price     float64 `json:"price" validate:"required" example:"30000.0" description:"Booking price"`
+	// ExpiresAt overrides the service TTL for this booking
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time" example:"2024-03-11T12:30:00Z" description:"Time after which the booking is canceled if still pending"`
 }
 
 // BookingResponse is the DTO for returning booking information
@@ -23,8 +29,25 @@ type BookingResponse struct {
 }
 
 // BookingsQueryParams represents query parameters for listing bookings
-// @Description Query parameters for filtering and sorting bookings
+// @Description Query parameters for filtering, sorting and paginating bookings
 type BookingsQueryParams struct {
-	Sort      string `query:"sort" example:"price" description:"Sort by field (price or date)"`
-	HighValue bool   `query:"high-value" example:"true" description:"Filter high-value bookings (price > 50,000)"`
+	Sort        string     `query:"sort" example:"price" description:"Sort by field (id, price or date)"`
+	Order       string     `query:"order" example:"desc" description:"Sort direction (asc or desc)"`
+	HighValue   bool       `query:"high-value" example:"true" description:"Filter high-value bookings (price above the configured threshold, 50,000 by default)"`
+	Status      string     `query:"status" example:"pending" description:"Filter by booking status"`
+	UserID      int64      `query:"user_id" example:"123" description:"Filter by user ID"`
+	ServiceID   int64      `query:"service_id" example:"456" description:"Filter by service ID"`
+	MinPrice    *float64   `query:"min_price" example:"10000" description:"Minimum price, inclusive"`
+	MaxPrice    *float64   `query:"max_price" example:"90000" description:"Maximum price, inclusive"`
+	CreatedFrom *time.Time `query:"created_from" format:"date-time" description:"Created at or after this time"`
+	CreatedTo   *time.Time `query:"created_to" format:"date-time" description:"Created before this time"`
+	Limit       int        `query:"limit" example:"20" description:"Page size (1-100, default 20)"`
+	Cursor      string     `query:"cursor" description:"Opaque cursor from next_cursor of the previous page"`
+}
+
+// BookingListResponse is the DTO for a page of bookings
+// @Description One page of bookings and the cursor of the next page
+type BookingListResponse struct {
+	Data       []*models.Booking `json:"data" description:"Bookings on this page"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"eyJzIjoiaWQiLCJpIjoyMH0" description:"Cursor of the next page; absent on the last page"`
 }
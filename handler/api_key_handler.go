@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+)
+
+// APIKeyHandler manages HTTP requests for the API key admin endpoints
+type APIKeyHandler struct {
+	apiKeyUseCase usecase.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new instance of APIKeyHandler
+func NewAPIKeyHandler(apiKeyUseCase usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// IssueKey godoc
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Summary Issue an API key
+// @Description Issue a new API key. The secret is only returned in this response.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key body dto.IssueAPIKeyRequest true "API key details"
+// @Success 201 {object} dto.IssuedAPIKeyResponse "Issued key and its secret"
+// @Failure 400 {object} problem.Problem "Invalid request parameters"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 403 {object} problem.Problem "API key lacks the admin scope"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
+// @Failure 500 {object} problem.Problem "Internal server error"
+// @Router /admin/keys [post]
+func (h *APIKeyHandler) IssueKey(c *fiber.Ctx) error {
+	req := new(dto.IssueAPIKeyRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return invalidParam("body", "must be a JSON key request")
+	}
+
+	if req.Name == "" {
+		return invalidParam("name", "is required")
+	}
+	if req.OwnerID < 0 {
+		return invalidParam("owner_id", "must not be negative")
+	}
+
+	for _, role := range req.Roles {
+		if !auth.ValidRole(role) {
+			return invalidParam("roles", "contains unknown role "+role)
+		}
+	}
+
+	// Customer keys, the default, are limited to the bookings of their owner
+	if req.OwnerID == 0 && (len(req.Roles) == 0 || containsString(req.Roles, auth.RoleCustomer)) {
+		return invalidParam("owner_id", "is required for customer keys")
+	}
+
+	for _, scope := range req.Scopes {
+		if !auth.ValidScope(scope) {
+			return invalidParam("scopes", "contains unknown scope "+scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return invalidParam("expires_at", "must be in the future")
+	}
+
+	issued, err := h.apiKeyUseCase.IssueKey(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(issued)
+}
+
+// ListKeys godoc
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Summary List API keys
+// @Description List every issued API key, including expired and revoked keys. Secrets are never returned.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.APIKey "API keys"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 403 {object} problem.Problem "API key lacks the admin scope"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
+// @Failure 500 {object} problem.Problem "Internal server error"
+// @Router /admin/keys [get]
+func (h *APIKeyHandler) ListKeys(c *fiber.Ctx) error {
+	keys, err := h.apiKeyUseCase.ListKeys(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(keys)
+}
+
+// RevokeKey godoc
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Summary Revoke an API key
+// @Description Revoke an API key; requests using it are rejected from then on
+// @Tags admin
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKey "Revoked key"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 403 {object} problem.Problem "API key lacks the admin scope"
+// @Failure 404 {object} problem.Problem "API key not found"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
+// @Failure 500 {object} problem.Problem "Internal server error"
+// @Router /admin/keys/{id} [delete]
+func (h *APIKeyHandler) RevokeKey(c *fiber.Ctx) error {
+	key, err := h.apiKeyUseCase.RevokeKey(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(key)
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/handler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAPIKeyApp(mockUseCase *mocks.APIKeyUseCase) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	apiKeyHandler := handler.NewAPIKeyHandler(mockUseCase)
+
+	app.Post("/api/admin/keys", apiKeyHandler.IssueKey)
+	app.Get("/api/admin/keys", apiKeyHandler.ListKeys)
+	app.Delete("/api/admin/keys/:id", apiKeyHandler.RevokeKey)
+
+	return app
+}
+
+func TestIssueKeyHandler(t *testing.T) {
+	// Create mock use case
+	mockUseCase := mocks.NewAPIKeyUseCase(t)
+
+	// Setup expectations
+	issued := &dto.IssuedAPIKeyResponse{
+		Secret: "bk_secret",
+		APIKey: &models.APIKey{ID: "key-1", Name: "mobile", Prefix: "bk_secre", Hash: "stored-hash", OwnerID: 123},
+	}
+	mockUseCase.On("IssueKey", mock.Anything, mock.MatchedBy(func(r *dto.IssueAPIKeyRequest) bool {
+		return r.Name == "mobile" && r.OwnerID == 123
+	})).Return(issued, nil)
+
+	// Execute
+	app := setupAPIKeyApp(mockUseCase)
+	body, _ := json.Marshal(dto.IssueAPIKeyRequest{Name: "mobile", OwnerID: 123, Scopes: []string{"bookings:read"}})
+	req := httptest.NewRequest("POST", "/api/admin/keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	// Assert
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, "bk_secret", result["secret"])
+	apiKey, ok := result["api_key"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "key-1", apiKey["id"])
+	assert.NotContains(t, apiKey, "hash")
+}
+
+func TestIssueKeyHandler_InvalidRequest(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name string
+		req  dto.IssueAPIKeyRequest
+	}{
+		{"missing name", dto.IssueAPIKeyRequest{OwnerID: 123}},
+		{"negative owner", dto.IssueAPIKeyRequest{Name: "mobile", OwnerID: -1}},
+		{"unknown scope", dto.IssueAPIKeyRequest{Name: "mobile", OwnerID: 123, Scopes: []string{"bookings:delete"}}},
+		{"unknown role", dto.IssueAPIKeyRequest{Name: "mobile", OwnerID: 123, Roles: []string{"superuser"}}},
+		{"customer without owner", dto.IssueAPIKeyRequest{Name: "mobile", Roles: []string{"customer"}}},
+		{"default role without owner", dto.IssueAPIKeyRequest{Name: "mobile"}},
+		{"expired", dto.IssueAPIKeyRequest{Name: "mobile", OwnerID: 123, ExpiresAt: &past}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock use case - it must not be called
+			mockUseCase := mocks.NewAPIKeyUseCase(t)
+
+			// Execute
+			app := setupAPIKeyApp(mockUseCase)
+			body, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest("POST", "/api/admin/keys", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			// Assert
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestListKeysHandler(t *testing.T) {
+	// Create mock use case
+	mockUseCase := mocks.NewAPIKeyUseCase(t)
+	mockUseCase.On("ListKeys", mock.Anything).Return([]*models.APIKey{
+		{ID: "key-1", Name: "mobile"},
+		{ID: "key-2", Name: "ops"},
+	}, nil)
+
+	// Execute
+	app := setupAPIKeyApp(mockUseCase)
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/admin/keys", nil))
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result []*models.APIKey
+	json.NewDecoder(resp.Body).Decode(&result)
+	require.Len(t, result, 2)
+	assert.Equal(t, "key-2", result[1].ID)
+}
+
+func TestRevokeKeyHandler(t *testing.T) {
+	// Create mock use case
+	mockUseCase := mocks.NewAPIKeyUseCase(t)
+	revokedAt := time.Now()
+	mockUseCase.On("RevokeKey", mock.Anything, "key-1").Return(&models.APIKey{ID: "key-1", RevokedAt: &revokedAt}, nil)
+	mockUseCase.On("RevokeKey", mock.Anything, "missing").Return(nil, usecase.ErrAPIKeyNotFound)
+
+	app := setupAPIKeyApp(mockUseCase)
+
+	// Execute
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/api/admin/keys/key-1", nil))
+	missing, _ := app.Test(httptest.NewRequest("DELETE", "/api/admin/keys/missing", nil))
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.StatusNotFound, missing.StatusCode)
+}
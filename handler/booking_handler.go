@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
 )
 
@@ -33,38 +37,52 @@ func NewBookingHandler(bookingUseCase usecase.BookingUseCase) *BookingHandler {
 
 // CreateBooking godoc
 // @Security ApiKeyAuth
+// @Security BearerAuth
 // @Summary Create a new booking
-// @Description Create a new booking with the provided details
+// @Description Create a new booking with the provided details. Pending bookings are canceled at expires_at, which defaults to the service TTL.
+// @Description Retries sent with the same Idempotency-Key get the original response instead of creating another booking.
 // @Tags bookings
 // @Accept json
 // @Produce json
 // @Param booking body dto.CreateBookingRequest true "Booking Information"
+// @Param Idempotency-Key header string false "Client-chosen key that makes the request safe to retry" maxlength(255)
 // @Success 201 {object} models.Booking "Created booking"
-// @Failure 400 {object} map[string]string "Invalid request parameters"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Header 201 {string} Idempotent-Replayed "Set to true when the response was replayed for a retry"
+// @Failure 400 {object} problem.Problem "Invalid request parameters"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 403 {object} problem.Problem "Customers can only create bookings for themselves"
+// @Failure 409 {object} problem.Problem "A request with the same Idempotency-Key is still in progress"
+// @Failure 422 {object} problem.Problem "Idempotency-Key was already used for a different request"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
+// @Failure 500 {object} problem.Problem "Internal server error"
 // @Router /bookings [post]
 func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
+	span := startSpan(c, "BookingHandler.CreateBooking")
+	defer span.End()
+
 	req := new(dto.CreateBookingRequest)
 
 	if err := c.BodyParser(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return invalidParam("body", "must be a JSON booking")
 	}
 
 	// Validate required fields
-	if req.UserID <= 0 || req.ServiceID <= 0 || req.Price <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "UserID, ServiceID, and Price are required and must be positive values",
-		})
+	switch {
+	case req.UserID <= 0:
+		return invalidParam("user_id", "is required and must be positive")
+	case req.ServiceID <= 0:
+		return invalidParam("service_id", "is required and must be positive")
+	case req.Price <= 0:
+		return invalidParam("price", "is required and must be positive")
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return invalidParam("expires_at", "must be in the future")
 	}
 
-	booking, err := h.bookingUseCase.CreateBooking(c.Context(), req)
+	booking, err := h.bookingUseCase.CreateBooking(c.UserContext(), req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(booking)
@@ -72,30 +90,40 @@ func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
 
 // GetBooking godoc
 // @Security ApiKeyAuth
+// @Security BearerAuth
 // @Summary Get a booking by ID
-// @Description Get detailed information about a specific booking
+// @Description Get detailed information about a specific booking. The ETag header carries the booking version.
 // @Tags bookings
 // @Accept json
 // @Produce json
 // @Param id path int true "Booking ID" minimum(1)
+// @Param If-Match header string false "Only return the booking if its ETag matches"
 // @Success 200 {object} models.Booking "Booking details"
-// @Failure 400 {object} map[string]string "Invalid booking ID format"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Booking not found"
+// @Header 200 {string} ETag "Booking version"
+// @Failure 400 {object} problem.Problem "Invalid booking ID format"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 404 {object} problem.Problem "Booking not found"
+// @Failure 412 {object} problem.Problem "Booking version does not match If-Match"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
 // @Router /bookings/{id} [get]
 func (h *BookingHandler) GetBooking(c *fiber.Ctx) error {
+	span := startSpan(c, "BookingHandler.GetBooking")
+	defer span.End()
+
 	id, err := c.ParamsInt("id")
 	if err != nil || id <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid booking ID format",
-		})
+		return invalidParam("id", "must be a positive integer")
 	}
 
-	booking, err := h.bookingUseCase.GetBookingByID(c.Context(), int64(id))
+	booking, err := h.bookingUseCase.GetBookingByID(c.UserContext(), int64(id))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Booking not found",
-		})
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, bookingETag(booking))
+
+	if ifMatch := c.Get(fiber.HeaderIfMatch); ifMatch != "" && !ifMatchSatisfied(ifMatch, booking) {
+		return errIfMatchFailed
 	}
 
 	return c.Status(fiber.StatusOK).JSON(booking)
@@ -103,67 +131,107 @@ func (h *BookingHandler) GetBooking(c *fiber.Ctx) error {
 
 // GetAllBookings godoc
 // @Security ApiKeyAuth
-// @Summary Get all bookings
-// @Description Get a list of all bookings with optional sorting and filtering
+// @Security BearerAuth
+// @Summary List bookings
+// @Description List bookings one page at a time with optional filtering and sorting. Pass next_cursor from a page as cursor to get the next one, keeping the same sort and order.
+// @Description Customers only see their own bookings.
 // @Tags bookings
 // @Accept json
 // @Produce json
-// @Param sort query string false "Sort by field (price or date)"
-// @Param high-value query boolean false "Filter high-value bookings (price > 50,000)"
-// @Success 200 {array} models.Booking "List of bookings"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Param sort query string false "Sort by field (id, price or date)" Enums(id, price, date)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param high-value query boolean false "Filter high-value bookings (price above the configured threshold, 50,000 by default)"
+// @Param status query string false "Filter by status" Enums(pending, confirmed, rejected, canceled)
+// @Param user_id query int false "Filter by user ID"
+// @Param service_id query int false "Filter by service ID"
+// @Param min_price query number false "Minimum price, inclusive"
+// @Param max_price query number false "Maximum price, inclusive"
+// @Param created_from query string false "Created at or after this RFC 3339 time"
+// @Param created_to query string false "Created before this RFC 3339 time"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param cursor query string false "Cursor from next_cursor of the previous page"
+// @Success 200 {object} dto.BookingListResponse "Page of bookings"
+// @Failure 400 {object} problem.Problem "Invalid query parameters or cursor"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 403 {object} problem.Problem "Customers can only list their own bookings"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
+// @Failure 500 {object} problem.Problem "Internal server error"
 // @Router /bookings [get]
 func (h *BookingHandler) GetAllBookings(c *fiber.Ctx) error {
-	params := &dto.BookingsQueryParams{
-		Sort:      c.Query("sort"),
-		HighValue: c.Query("high-value") == "true",
+	span := startSpan(c, "BookingHandler.GetAllBookings")
+	defer span.End()
+
+	params, err := parseBookingsQuery(c)
+	if err != nil {
+		return err
 	}
 
-	bookings, err := h.bookingUseCase.GetAllBookings(c.Context(), params)
+	page, err := h.bookingUseCase.GetAllBookings(c.UserContext(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(bookings)
+	return c.Status(fiber.StatusOK).JSON(page)
 }
 
 // CancelBooking godoc
 // @Security ApiKeyAuth
+// @Security BearerAuth
 // @Summary Cancel a booking
-// @Description Cancel an existing booking by its ID
+// @Description Cancel an existing booking by its ID. Send If-Match with the ETag from GET to cancel only an unchanged booking.
 // @Tags bookings
 // @Accept json
 // @Produce json
 // @Param id path int true "Booking ID" minimum(1)
+// @Param If-Match header string false "ETag of the booking version being canceled"
 // @Success 200 {object} models.Booking "Canceled booking details"
-// @Failure 400 {object} map[string]string "Invalid booking ID or cannot cancel"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Booking not found"
+// @Header 200 {string} ETag "Booking version"
+// @Failure 400 {object} problem.Problem "Invalid booking ID"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 403 {object} problem.Problem "Only admins can cancel a confirmed booking"
+// @Failure 404 {object} problem.Problem "Booking not found"
+// @Failure 409 {object} problem.Problem "Booking was modified concurrently (conflict) or cannot be canceled in its status (invalid_transition)"
+// @Failure 412 {object} problem.Problem "Booking version does not match If-Match"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
 // @Router /bookings/{id} [delete]
 func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
+	span := startSpan(c, "BookingHandler.CancelBooking")
+	defer span.End()
+
 	id, err := c.ParamsInt("id")
 	if err != nil || id <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid booking ID format",
-		})
+		return invalidParam("id", "must be a positive integer")
 	}
 
-	booking, err := h.bookingUseCase.CancelBooking(c.Context(), int64(id))
+	// Translate If-Match into the version the client expects to cancel
+	var expectedVersion int64
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch != "" {
+		versions, wildcard := parseIfMatch(ifMatch)
+		switch {
+		case wildcard:
+			// "*" matches any existing booking
+		case len(versions) == 1:
+			expectedVersion = versions[0]
+		case len(versions) > 1:
+			return invalidParam("If-Match", "must contain a single entity tag")
+		default:
+			return errIfMatchFailed
+		}
+	}
+
+	booking, err := h.bookingUseCase.CancelBooking(c.UserContext(), int64(id), expectedVersion)
 	if err != nil {
-		// Check if this is a business rule error (cannot cancel confirmed booking)
-		if err.Error() == "cannot cancel a confirmed booking" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+		if errors.Is(err, usecase.ErrVersionConflict) {
+			if ifMatch != "" {
+				return errIfMatchFailed
+			}
+			return problem.New(fiber.StatusConflict, problem.CodeConflict, "Booking was modified concurrently, please retry")
 		}
-		// Otherwise, it's likely a "not found" error
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Booking not found",
-		})
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, bookingETag(booking))
+
 	return c.Status(fiber.StatusOK).JSON(booking)
 }
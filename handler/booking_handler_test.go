@@ -3,6 +3,7 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -12,12 +13,15 @@ import (
 	"github.com/hydr0g3nz/spd-fiber-booking-system/handler"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func setupApp(mockUseCase *mocks.BookingUseCase) *fiber.App {
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
 	bookingHandler := handler.NewBookingHandler(mockUseCase)
 
 	app.Post("/api/bookings", bookingHandler.CreateBooking)
@@ -154,8 +158,10 @@ func TestGetAllBookingsHandler(t *testing.T) {
 		},
 	}
 
-	// Setup expectations - match any query parameters
-	mockUseCase.On("GetAllBookings", mock.Anything, mock.Anything).Return(bookings, nil)
+	// Setup expectations - the query parameters reach the use case
+	mockUseCase.On("GetAllBookings", mock.Anything, mock.MatchedBy(func(p *dto.BookingsQueryParams) bool {
+		return p.Sort == "price" && p.HighValue
+	})).Return(&dto.BookingListResponse{Data: bookings, NextCursor: "next"}, nil)
 
 	// Setup app with mock
 	app := setupApp(mockUseCase)
@@ -169,16 +175,141 @@ func TestGetAllBookingsHandler(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 
 	// Parse response body
-	var responseBookings []*models.Booking
-	json.NewDecoder(resp.Body).Decode(&responseBookings)
+	var page dto.BookingListResponse
+	json.NewDecoder(resp.Body).Decode(&page)
 
-	assert.Equal(t, 2, len(responseBookings))
-	assert.Equal(t, bookings[0].ID, responseBookings[0].ID)
-	assert.Equal(t, bookings[1].ID, responseBookings[1].ID)
+	assert.Equal(t, 2, len(page.Data))
+	assert.Equal(t, bookings[0].ID, page.Data[0].ID)
+	assert.Equal(t, bookings[1].ID, page.Data[1].ID)
+	assert.Equal(t, "next", page.NextCursor)
 
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestGetAllBookingsHandler_Filters(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+
+	// Setup expectations - every filter is parsed into the params
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	mockUseCase.On("GetAllBookings", mock.Anything, mock.MatchedBy(func(p *dto.BookingsQueryParams) bool {
+		return p.Sort == "date" && p.Order == "desc" &&
+			p.Status == "pending" && p.UserID == 123 && p.ServiceID == 456 &&
+			*p.MinPrice == 1000 && *p.MaxPrice == 90000 &&
+			p.CreatedFrom.Equal(from) && p.CreatedTo.Equal(to) &&
+			p.Limit == 5 && p.Cursor == "abc"
+	})).Return(&dto.BookingListResponse{Data: []*models.Booking{}}, nil)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request
+	req := httptest.NewRequest("GET", "/api/bookings?sort=date&order=desc&status=pending&user_id=123&service_id=456"+
+		"&min_price=1000&max_price=90000&created_from=2024-03-01T00:00:00Z&created_to=2024-04-01T00:00:00Z&limit=5&cursor=abc", nil)
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestGetAllBookingsHandler_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		param string
+	}{
+		{"unknown sort field", "sort=name", "sort"},
+		{"unknown order", "order=up", "order"},
+		{"unknown status", "status=archived", "status"},
+		{"non-numeric user", "user_id=abc", "user_id"},
+		{"negative price", "min_price=-1", "min_price"},
+		{"inverted price range", "min_price=500&max_price=100", "min_price"},
+		{"malformed time", "created_from=yesterday", "created_from"},
+		{"inverted time range", "created_from=2024-04-01T00:00:00Z&created_to=2024-03-01T00:00:00Z", "created_from"},
+		{"zero limit", "limit=0", "limit"},
+		{"limit too large", "limit=101", "limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock use case
+			mockUseCase := new(mocks.BookingUseCase)
+			app := setupApp(mockUseCase)
+
+			// Perform request
+			req := httptest.NewRequest("GET", "/api/bookings?"+tt.query, nil)
+			resp, err := app.Test(req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.StatusCode)
+			mockUseCase.AssertNotCalled(t, "GetAllBookings")
+
+			var errorResponse problem.Problem
+			json.NewDecoder(resp.Body).Decode(&errorResponse)
+			assert.Equal(t, problem.CodeValidation, errorResponse.Code)
+			if assert.Len(t, errorResponse.InvalidParams, 1) {
+				assert.Equal(t, tt.param, errorResponse.InvalidParams[0].Name)
+			}
+		})
+	}
+}
+
+func TestGetBookingHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{"not found", repository.ErrBookingNotFound, 404, problem.CodeNotFound},
+		{"store unavailable", &repository.UnavailableError{Err: errors.New("database is locked")}, 503, problem.CodeUnavailable},
+		{"unexpected failure", errors.New("disk full"), 500, problem.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock use case
+			mockUseCase := new(mocks.BookingUseCase)
+			mockUseCase.On("GetBookingByID", mock.Anything, int64(1)).Return(nil, tt.err)
+			app := setupApp(mockUseCase)
+
+			// Perform request
+			resp, err := app.Test(httptest.NewRequest("GET", "/api/bookings/1", nil))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			var errorResponse problem.Problem
+			json.NewDecoder(resp.Body).Decode(&errorResponse)
+			assert.Equal(t, tt.wantCode, errorResponse.Code)
+			assert.Equal(t, "/api/bookings/1", errorResponse.Instance)
+			assert.NotContains(t, errorResponse.Detail, "disk full", "internal errors must not reach the client")
+		})
+	}
+}
+
+func TestGetAllBookingsHandler_InvalidCursor(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+	mockUseCase.On("GetAllBookings", mock.Anything, mock.Anything).Return(nil, usecase.ErrInvalidCursor)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request
+	req := httptest.NewRequest("GET", "/api/bookings?cursor=garbage", nil)
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
 func TestCancelBookingHandler_Success(t *testing.T) {
 	// Create mock use case
 	mockUseCase := new(mocks.BookingUseCase)
@@ -196,7 +327,7 @@ func TestCancelBookingHandler_Success(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockUseCase.On("CancelBooking", mock.Anything, bookingID).Return(canceledBooking, nil)
+	mockUseCase.On("CancelBooking", mock.Anything, bookingID, int64(0)).Return(canceledBooking, nil)
 
 	// Setup app with mock
 	app := setupApp(mockUseCase)
@@ -227,8 +358,12 @@ func TestCancelBookingHandler_CannotCancelConfirmed(t *testing.T) {
 	bookingID := int64(1)
 
 	// Setup expectations - confirmed booking cannot be canceled
-	mockUseCase.On("CancelBooking", mock.Anything, bookingID).Return(nil,
-		fiber.NewError(fiber.StatusBadRequest, "cannot cancel a confirmed booking"))
+	mockUseCase.On("CancelBooking", mock.Anything, bookingID, int64(0)).Return(nil,
+		&models.TransitionError{
+			From:  models.BookingStatusConfirmed,
+			To:    models.BookingStatusCanceled,
+			Actor: models.ActorCustomer,
+		})
 
 	// Setup app with mock
 	app := setupApp(mockUseCase)
@@ -239,13 +374,15 @@ func TestCancelBookingHandler_CannotCancelConfirmed(t *testing.T) {
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, 400, resp.StatusCode)
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.Equal(t, problem.ContentType, resp.Header.Get("Content-Type"))
 
 	// Parse response body
-	var errorResponse map[string]string
+	var errorResponse problem.Problem
 	json.NewDecoder(resp.Body).Decode(&errorResponse)
 
-	assert.Equal(t, "cannot cancel a confirmed booking", errorResponse["error"])
+	assert.Equal(t, problem.CodeInvalidTransition, errorResponse.Code)
+	assert.Equal(t, "cannot cancel a confirmed booking", errorResponse.Detail)
 
 	mockUseCase.AssertExpectations(t)
 }
@@ -279,3 +416,221 @@ func TestCreateBookingHandler_InvalidRequest(t *testing.T) {
 	// Usecase should never be called with invalid data
 	mockUseCase.AssertNotCalled(t, "CreateBooking")
 }
+
+func TestCreateBookingHandler_ExpiresAtInPast(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+
+	// Invalid request (expiry time already passed)
+	reqData := map[string]interface{}{
+		"user_id":    123,
+		"service_id": 456,
+		"price":      1000.0,
+		"expires_at": time.Now().Add(-time.Minute),
+	}
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request
+	reqBody, _ := json.Marshal(reqData)
+	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+	mockUseCase.AssertNotCalled(t, "CreateBooking")
+}
+
+func TestCreateBookingHandler_ExpiresAtPassedToUseCase(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+
+	// Create test data
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	reqData := map[string]interface{}{
+		"user_id":    123,
+		"service_id": 456,
+		"price":      1000.0,
+		"expires_at": expiresAt,
+	}
+
+	// Setup expectations
+	mockUseCase.On("CreateBooking", mock.Anything, mock.MatchedBy(func(r *dto.CreateBookingRequest) bool {
+		return r.ExpiresAt != nil && r.ExpiresAt.Equal(expiresAt)
+	})).Return(&models.Booking{ID: 1, ExpiresAt: expiresAt}, nil)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request
+	reqBody, _ := json.Marshal(reqData)
+	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestGetBookingHandler_ETag(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+
+	// Create test data
+	booking := &models.Booking{
+		ID:      1,
+		Status:  models.BookingStatusPending,
+		Version: 3,
+	}
+	mockUseCase.On("GetBookingByID", mock.Anything, int64(1)).Return(booking, nil)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform requests - without a precondition, with a matching and with a stale one
+	plain, err := app.Test(httptest.NewRequest("GET", "/api/bookings/1", nil))
+	assert.NoError(t, err)
+
+	matching := httptest.NewRequest("GET", "/api/bookings/1", nil)
+	matching.Header.Set("If-Match", `"3"`)
+	matchingResp, err := app.Test(matching)
+	assert.NoError(t, err)
+
+	stale := httptest.NewRequest("GET", "/api/bookings/1", nil)
+	stale.Header.Set("If-Match", `"2"`)
+	staleResp, err := app.Test(stale)
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 200, plain.StatusCode)
+	assert.Equal(t, `"3"`, plain.Header.Get("ETag"))
+	assert.Equal(t, 200, matchingResp.StatusCode)
+	assert.Equal(t, 412, staleResp.StatusCode)
+	assert.Equal(t, `"3"`, staleResp.Header.Get("ETag"))
+}
+
+func TestCancelBookingHandler_IfMatchPassesVersion(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+
+	// Create test data
+	canceledBooking := &models.Booking{
+		ID:      1,
+		Status:  models.BookingStatusCanceled,
+		Version: 4,
+	}
+
+	// Setup expectations - the ETag is forwarded as the expected version
+	mockUseCase.On("CancelBooking", mock.Anything, int64(1), int64(3)).Return(canceledBooking, nil)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request
+	req := httptest.NewRequest("DELETE", "/api/bookings/1", nil)
+	req.Header.Set("If-Match", `"3"`)
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `"4"`, resp.Header.Get("ETag"))
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestCancelBookingHandler_VersionConflict(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+	conflict := &repository.VersionConflictError{ID: 1, ExpectedVersion: 3, CurrentVersion: 4}
+
+	// Setup expectations
+	mockUseCase.On("CancelBooking", mock.Anything, int64(1), int64(3)).Return(nil, conflict)
+	mockUseCase.On("CancelBooking", mock.Anything, int64(1), int64(0)).Return(nil, conflict)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform requests
+	conditional := httptest.NewRequest("DELETE", "/api/bookings/1", nil)
+	conditional.Header.Set("If-Match", `"3"`)
+	conditionalResp, err := app.Test(conditional)
+	assert.NoError(t, err)
+
+	unconditionalResp, err := app.Test(httptest.NewRequest("DELETE", "/api/bookings/1", nil))
+	assert.NoError(t, err)
+
+	// Assert - a failed If-Match is 412, a lost race without one is 409
+	assert.Equal(t, 412, conditionalResp.StatusCode)
+	assert.Equal(t, 409, unconditionalResp.StatusCode)
+}
+
+func TestCancelBookingHandler_UnrecognizedIfMatch(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request - a weak tag can never satisfy If-Match
+	req := httptest.NewRequest("DELETE", "/api/bookings/1", nil)
+	req.Header.Set("If-Match", `W/"3"`)
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 412, resp.StatusCode)
+	mockUseCase.AssertNotCalled(t, "CancelBooking")
+}
+
+func TestCancelBookingHandler_ForbiddenTransition(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+
+	// Setup expectations - the move is legal, but only for admins
+	mockUseCase.On("CancelBooking", mock.Anything, int64(1), int64(0)).Return(nil,
+		&models.TransitionError{
+			From:      models.BookingStatusConfirmed,
+			To:        models.BookingStatusCanceled,
+			Actor:     models.ActorCustomer,
+			Forbidden: true,
+		})
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/api/bookings/1", nil))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	var errorResponse problem.Problem
+	json.NewDecoder(resp.Body).Decode(&errorResponse)
+	assert.Equal(t, problem.CodeForbidden, errorResponse.Code)
+	assert.Equal(t, "customer cannot cancel a confirmed booking", errorResponse.Detail)
+}
+
+func TestCreateBookingHandler_Forbidden(t *testing.T) {
+	// Create mock use case
+	mockUseCase := new(mocks.BookingUseCase)
+	mockUseCase.On("CreateBooking", mock.Anything, mock.Anything).Return(nil, usecase.ErrForbidden)
+
+	// Setup app with mock
+	app := setupApp(mockUseCase)
+
+	// Perform request
+	reqBody, _ := json.Marshal(&dto.CreateBookingRequest{UserID: 123, ServiceID: 456, Price: 1000})
+	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+)
+
+// parseBookingsQuery reads and validates the query parameters of GET /bookings.
+// Invalid parameters are reported as *usecase.ValidationError.
+func parseBookingsQuery(c *fiber.Ctx) (*dto.BookingsQueryParams, error) {
+	params := &dto.BookingsQueryParams{
+		Sort:      c.Query("sort"),
+		Order:     c.Query("order"),
+		HighValue: c.Query("high-value") == "true",
+		Status:    c.Query("status"),
+		Cursor:    c.Query("cursor"),
+	}
+
+	switch params.Sort {
+	case "", "id", "price", "date":
+	default:
+		return nil, invalidParam("sort", "must be one of id, price or date")
+	}
+
+	switch params.Order {
+	case "", "asc", "desc":
+	default:
+		return nil, invalidParam("order", "must be asc or desc")
+	}
+
+	if params.Status != "" && !models.BookingStatus(params.Status).IsValid() {
+		return nil, invalidParam("status", "must be one of pending, confirmed, rejected or canceled")
+	}
+
+	var err error
+	if params.UserID, err = positiveIntQuery(c, "user_id"); err != nil {
+		return nil, err
+	}
+	if params.ServiceID, err = positiveIntQuery(c, "service_id"); err != nil {
+		return nil, err
+	}
+
+	if params.MinPrice, err = priceQuery(c, "min_price"); err != nil {
+		return nil, err
+	}
+	if params.MaxPrice, err = priceQuery(c, "max_price"); err != nil {
+		return nil, err
+	}
+	if params.MinPrice != nil && params.MaxPrice != nil && *params.MinPrice > *params.MaxPrice {
+		return nil, invalidParam("min_price", "must not exceed max_price")
+	}
+
+	if params.CreatedFrom, err = timeQuery(c, "created_from"); err != nil {
+		return nil, err
+	}
+	if params.CreatedTo, err = timeQuery(c, "created_to"); err != nil {
+		return nil, err
+	}
+	if params.CreatedFrom != nil && params.CreatedTo != nil && !params.CreatedFrom.Before(*params.CreatedTo) {
+		return nil, invalidParam("created_from", "must be before created_to")
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > usecase.MaxPageSize {
+			return nil, invalidParam("limit", fmt.Sprintf("must be between 1 and %d", usecase.MaxPageSize))
+		}
+		params.Limit = limit
+	}
+
+	return params, nil
+}
+
+// positiveIntQuery parses an optional positive integer query parameter; zero means absent
+func positiveIntQuery(c *fiber.Ctx, key string) (int64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, invalidParam(key, "must be a positive integer")
+	}
+
+	return n, nil
+}
+
+// priceQuery parses an optional non-negative price query parameter
+func priceQuery(c *fiber.Ctx, key string) (*float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price < 0 {
+		return nil, invalidParam(key, "must be a non-negative number")
+	}
+
+	return &price, nil
+}
+
+// timeQuery parses an optional RFC 3339 timestamp query parameter
+func timeQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, invalidParam(key, "must be an RFC 3339 timestamp")
+	}
+
+	return &t, nil
+}
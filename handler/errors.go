@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+)
+
+// errIfMatchFailed answers a conditional request whose If-Match does not hold
+var errIfMatchFailed = problem.New(fiber.StatusPreconditionFailed, problem.CodePreconditionFailed,
+	"Booking version does not match If-Match")
+
+// ErrorHandler is the application's error handler. Handlers return errors
+// instead of writing error responses; ErrorHandler maps each error kind to
+// an application/problem+json response with a stable code. Unexpected errors
+// are answered with a generic 500, so internal details do not reach the
+// client; the Logging and Tracing middleware record them instead.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	return problem.Write(c, problemFor(err))
+}
+
+// problemFor maps an error to the problem describing it
+func problemFor(err error) *problem.Problem {
+	var (
+		p             *problem.Problem
+		validationErr *usecase.ValidationError
+		transitionErr *models.TransitionError
+		fiberErr      *fiber.Error
+	)
+
+	switch {
+	case errors.As(err, &p):
+		return p
+	case errors.As(err, &validationErr):
+		p = problem.New(fiber.StatusBadRequest, problem.CodeValidation, err.Error())
+		p.InvalidParams = []problem.InvalidParam{{Name: validationErr.Field, Reason: validationErr.Reason}}
+		return p
+	case errors.Is(err, usecase.ErrValidation):
+		return problem.New(fiber.StatusBadRequest, problem.CodeValidation, err.Error())
+	// The status change is legal but not for this caller, e.g. force-canceling as a non-admin
+	case errors.As(err, &transitionErr) && transitionErr.Forbidden, errors.Is(err, usecase.ErrForbidden):
+		return problem.New(fiber.StatusForbidden, problem.CodeForbidden, err.Error())
+	case errors.Is(err, usecase.ErrInvalidTransition):
+		return problem.New(fiber.StatusConflict, problem.CodeInvalidTransition, err.Error())
+	case errors.Is(err, usecase.ErrNotFound), errors.Is(err, jobs.ErrJobNotFound):
+		return problem.New(fiber.StatusNotFound, problem.CodeNotFound, err.Error())
+	case errors.Is(err, usecase.ErrConflict), errors.Is(err, jobs.ErrJobNotFailed):
+		return problem.New(fiber.StatusConflict, problem.CodeConflict, err.Error())
+	case errors.Is(err, usecase.ErrUnavailable):
+		return problem.New(fiber.StatusServiceUnavailable, problem.CodeUnavailable,
+			"The service is temporarily unavailable, please retry")
+	case errors.As(err, &fiberErr):
+		if fiberErr.Code >= fiber.StatusInternalServerError {
+			return problem.New(fiberErr.Code, problem.CodeForStatus(fiberErr.Code), "")
+		}
+		return problem.New(fiberErr.Code, problem.CodeForStatus(fiberErr.Code), fiberErr.Message)
+	default:
+		return problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "An unexpected error occurred")
+	}
+}
+
+// invalidParam reports a request parameter that failed validation
+func invalidParam(name, reason string) error {
+	return &usecase.ValidationError{Field: name, Reason: reason}
+}
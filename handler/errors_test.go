@@ -0,0 +1,98 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/handler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failWith answers GET /fail with the error handler's response to err
+func failWith(t *testing.T, err error) (*problem.Problem, string) {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(middleware.RequestID())
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return err
+	})
+
+	req := httptest.NewRequest("GET", "/fail", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	resp, testErr := app.Test(req)
+	require.NoError(t, testErr)
+
+	var body problem.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, resp.StatusCode, body.Status)
+	return &body, resp.Header.Get(fiber.HeaderContentType)
+}
+
+func TestErrorHandler_MapsErrorKinds(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{"validation", &usecase.ValidationError{Field: "price", Reason: "must be positive"}, 400, problem.CodeValidation},
+		{"invalid cursor", usecase.ErrInvalidCursor, 400, problem.CodeValidation},
+		{"forbidden", fmt.Errorf("%w: customers only", usecase.ErrForbidden), 403, problem.CodeForbidden},
+		{"forbidden transition", &models.TransitionError{From: models.BookingStatusConfirmed, To: models.BookingStatusCanceled, Actor: models.ActorCustomer, Forbidden: true}, 403, problem.CodeForbidden},
+		{"invalid transition", &models.TransitionError{From: models.BookingStatusRejected, To: models.BookingStatusCanceled, Actor: models.ActorCustomer}, 409, problem.CodeInvalidTransition},
+		{"booking not found", repository.ErrBookingNotFound, 404, problem.CodeNotFound},
+		{"api key not found", usecase.ErrAPIKeyNotFound, 404, problem.CodeNotFound},
+		{"job not found", jobs.ErrJobNotFound, 404, problem.CodeNotFound},
+		{"version conflict", &repository.VersionConflictError{ID: 1, ExpectedVersion: 1, CurrentVersion: 2}, 409, problem.CodeConflict},
+		{"job not failed", jobs.ErrJobNotFailed, 409, problem.CodeConflict},
+		{"unavailable", fmt.Errorf("load booking: %w", &repository.UnavailableError{Err: errors.New("database is locked")}), 503, problem.CodeUnavailable},
+		{"fiber error", fiber.ErrMethodNotAllowed, 405, problem.CodeMethodNotAllowed},
+		{"explicit problem", problem.New(fiber.StatusPreconditionFailed, problem.CodePreconditionFailed, "stale"), 412, problem.CodePreconditionFailed},
+		{"unexpected", errors.New("boom"), 500, problem.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			body, contentType := failWith(t, tt.err)
+
+			// Assert
+			assert.Equal(t, problem.ContentType, contentType)
+			assert.Equal(t, tt.wantStatus, body.Status)
+			assert.Equal(t, tt.wantCode, body.Code)
+			assert.Equal(t, "urn:problem-type:booking-system:"+string(tt.wantCode), body.Type)
+			assert.NotEmpty(t, body.Title)
+			assert.Equal(t, "/fail", body.Instance)
+			assert.Equal(t, "req-42", body.RequestID)
+		})
+	}
+}
+
+func TestErrorHandler_ValidationListsInvalidParam(t *testing.T) {
+	// Execute
+	body, _ := failWith(t, &usecase.ValidationError{Field: "price", Reason: "must be positive"})
+
+	// Assert
+	assert.Equal(t, "price must be positive", body.Detail)
+	assert.Equal(t, []problem.InvalidParam{{Name: "price", Reason: "must be positive"}}, body.InvalidParams)
+}
+
+func TestErrorHandler_HidesInternalDetails(t *testing.T) {
+	// Execute
+	body, _ := failWith(t, errors.New("open /var/lib/booking/wal: permission denied"))
+
+	// Assert
+	assert.Equal(t, "An unexpected error occurred", body.Detail)
+}
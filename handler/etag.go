@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// bookingETag returns the strong entity tag of a booking, derived from its version
+func bookingETag(booking *models.Booking) string {
+	return `"` + strconv.FormatInt(booking.Version, 10) + `"`
+}
+
+// parseIfMatch parses an If-Match header into the booking versions it lists.
+// wildcard is true for "*". Weak and unrecognized entity tags are skipped because
+// If-Match requires strong comparison, so they can never match.
+func parseIfMatch(header string) (versions []int64, wildcard bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil || version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, false
+}
+
+// ifMatchSatisfied reports whether the If-Match header matches the booking's current version
+func ifMatchSatisfied(header string, booking *models.Booking) bool {
+	versions, wildcard := parseIfMatch(header)
+	if wildcard {
+		return true
+	}
+
+	for _, version := range versions {
+		if version == booking.Version {
+			return true
+		}
+	}
+
+	return false
+}
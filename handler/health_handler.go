@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/health"
+)
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler creates a new instance of HealthHandler
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Liveness reports whether the process is alive: its background loops are
+// running and beating. A failing probe means the instance should be restarted.
+func (h *HealthHandler) Liveness(c *fiber.Ctx) error {
+	return sendReport(c, h.checker.Liveness(c.UserContext()))
+}
+
+// Readiness reports whether the service can take traffic: the liveness checks
+// plus the dependencies requests need. It fails while the service drains.
+func (h *HealthHandler) Readiness(c *fiber.Ctx) error {
+	return sendReport(c, h.checker.Readiness(c.UserContext()))
+}
+
+// sendReport answers 200 when report is up and 503 otherwise
+func sendReport(c *fiber.Ctx, report health.Report) error {
+	status := fiber.StatusOK
+	if report.Status != health.StatusUp {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(report)
+}
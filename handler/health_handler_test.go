@@ -0,0 +1,92 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/handler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupHealthApp serves the probes of a checker whose repository check returns repoErr
+func setupHealthApp(repoErr error) (*fiber.App, *health.Checker) {
+	checker := health.NewChecker(time.Second)
+	checker.AddLivenessCheck("expiry_scheduler", func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, nil
+	})
+	checker.AddReadinessCheck("repository", func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, repoErr
+	})
+
+	app := fiber.New()
+	healthHandler := handler.NewHealthHandler(checker)
+	app.Get("/healthz", healthHandler.Liveness)
+	app.Get("/readyz", healthHandler.Readiness)
+
+	return app, checker
+}
+
+func getReport(t *testing.T, app *fiber.App, path string) (int, health.Report) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	return resp.StatusCode, report
+}
+
+func TestHealthHandler_Healthy(t *testing.T) {
+	// Arrange
+	app, _ := setupHealthApp(nil)
+
+	// Act
+	liveStatus, live := getReport(t, app, "/healthz")
+	readyStatus, ready := getReport(t, app, "/readyz")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, liveStatus)
+	assert.Equal(t, health.StatusUp, live.Status)
+	assert.Len(t, live.Components, 1)
+	assert.Equal(t, fiber.StatusOK, readyStatus)
+	assert.Equal(t, health.StatusUp, ready.Components["repository"].Status)
+}
+
+func TestHealthHandler_UnreachableRepository(t *testing.T) {
+	// Arrange
+	app, _ := setupHealthApp(errors.New("database is closed"))
+
+	// Act
+	liveStatus, _ := getReport(t, app, "/healthz")
+	readyStatus, ready := getReport(t, app, "/readyz")
+
+	// Assert - still alive, but not ready
+	assert.Equal(t, fiber.StatusOK, liveStatus)
+	assert.Equal(t, fiber.StatusServiceUnavailable, readyStatus)
+	assert.Equal(t, health.StatusDown, ready.Status)
+	assert.Equal(t, "database is closed", ready.Components["repository"].Error)
+}
+
+func TestHealthHandler_Draining(t *testing.T) {
+	// Arrange
+	app, checker := setupHealthApp(nil)
+
+	// Act
+	checker.Drain()
+	liveStatus, _ := getReport(t, app, "/healthz")
+	readyStatus, ready := getReport(t, app, "/readyz")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, liveStatus)
+	assert.Equal(t, fiber.StatusServiceUnavailable, readyStatus)
+	assert.True(t, ready.Draining)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+)
+
+// JobHandler manages HTTP requests for the background job admin endpoints
+type JobHandler struct {
+	queue *jobs.Queue
+}
+
+// NewJobHandler creates a new instance of JobHandler
+func NewJobHandler(queue *jobs.Queue) *JobHandler {
+	return &JobHandler{
+		queue: queue,
+	}
+}
+
+// GetFailedJobs godoc
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Summary List failed jobs
+// @Description List background jobs that used up their attempts and were moved to the dead-letter list
+// @Tags admin
+// @Produce json
+// @Success 200 {array} jobs.Job "Failed jobs"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
+// @Failure 500 {object} problem.Problem "Internal server error"
+// @Router /admin/jobs/failed [get]
+func (h *JobHandler) GetFailedJobs(c *fiber.Ctx) error {
+	failed, err := h.queue.Failed(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(failed)
+}
+
+// RetryJob godoc
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Summary Retry a failed job
+// @Description Move a failed job back to the queue with a fresh set of attempts
+// @Tags admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobs.Job "Requeued job"
+// @Failure 401 {object} problem.Problem "Unauthorized"
+// @Failure 404 {object} problem.Problem "Job not found"
+// @Failure 409 {object} problem.Problem "Job has not failed"
+// @Failure 429 {object} problem.Problem "Rate limit exceeded; see Retry-After"
+// @Failure 500 {object} problem.Problem "Internal server error"
+// @Router /admin/jobs/{id}/retry [post]
+func (h *JobHandler) RetryJob(c *fiber.Ctx) error {
+	job, err := h.queue.Retry(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(job)
+}
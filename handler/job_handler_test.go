@@ -0,0 +1,101 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/handler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupJobApp serves the job admin endpoints from a queue whose workers are not started
+func setupJobApp(t *testing.T) (*fiber.App, jobs.Store) {
+	store := jobs.NewMemoryStore()
+	now := time.Now()
+	require.NoError(t, store.Save(context.Background(), &jobs.Job{
+		ID: "failed-1", Type: "credit_check", Status: jobs.StatusFailed,
+		Attempts: 5, MaxAttempts: 5, LastError: "timeout", CreatedAt: now,
+	}))
+	require.NoError(t, store.Save(context.Background(), &jobs.Job{
+		ID: "pending-1", Type: "credit_check", Status: jobs.StatusPending,
+		MaxAttempts: 5, CreatedAt: now.Add(time.Second),
+	}))
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	jobHandler := handler.NewJobHandler(jobs.NewQueue(store, jobs.Options{}))
+
+	app.Get("/api/admin/jobs/failed", jobHandler.GetFailedJobs)
+	app.Post("/api/admin/jobs/:id/retry", jobHandler.RetryJob)
+
+	return app, store
+}
+
+func TestGetFailedJobsHandler(t *testing.T) {
+	// Arrange
+	app, _ := setupJobApp(t)
+
+	// Act
+	req := httptest.NewRequest("GET", "/api/admin/jobs/failed", nil)
+	resp, _ := app.Test(req)
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result []*jobs.Job
+	json.NewDecoder(resp.Body).Decode(&result)
+	require.Len(t, result, 1)
+	assert.Equal(t, "failed-1", result[0].ID)
+	assert.Equal(t, "timeout", result[0].LastError)
+}
+
+func TestRetryJobHandler(t *testing.T) {
+	// Arrange
+	app, store := setupJobApp(t)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/admin/jobs/failed-1/retry", nil)
+	resp, _ := app.Test(req)
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result jobs.Job
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, jobs.StatusPending, result.Status)
+	assert.Equal(t, 0, result.Attempts)
+
+	stored, err := store.Get(context.Background(), "failed-1")
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusPending, stored.Status)
+}
+
+func TestRetryJobHandler_Errors(t *testing.T) {
+	// Arrange
+	app, _ := setupJobApp(t)
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"unknown job", "missing", fiber.StatusNotFound},
+		{"job has not failed", "pending-1", fiber.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			req := httptest.NewRequest("POST", "/api/admin/jobs/"+tt.id+"/retry", nil)
+			resp, _ := app.Test(req)
+
+			// Assert
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
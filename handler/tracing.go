@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan runs the rest of a handler in a span named after it. The span
+// replaces the user context, so the use case calls join it.
+func startSpan(c *fiber.Ctx, name string) trace.Span {
+	ctx, span := tracing.Tracer().Start(c.UserContext(), name)
+	c.SetUserContext(ctx)
+	return span
+}
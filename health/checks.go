@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+)
+
+// Pinger is a store that can tell whether it is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck reports whether store is reachable. Stores that cannot become
+// unreachable, e.g. in-memory ones, need not implement Pinger and are always up.
+func PingCheck(store interface{}) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		pinger, ok := store.(Pinger)
+		if !ok {
+			return nil, nil
+		}
+		return nil, pinger.Ping(ctx)
+	}
+}
+
+// StatsReporter is a cache that reports its statistics
+type StatsReporter interface {
+	Stats() utils.CacheStats
+}
+
+// CacheCheck reports the statistics of cache without touching its entries.
+// Caches that implement Pinger are down while the ping fails; in-memory
+// caches are always up.
+func CacheCheck(cache StatsReporter) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		stats := cache.Stats()
+		details := map[string]interface{}{
+			"entries":     stats.Entries,
+			"hits":        stats.Hits,
+			"misses":      stats.Misses,
+			"evictions":   stats.Evictions,
+			"expirations": stats.Expirations,
+		}
+		if pinger, ok := cache.(Pinger); ok {
+			return details, pinger.Ping(ctx)
+		}
+		return details, nil
+	}
+}
+
+// Heartbeat is a background loop that records when it last did its work
+type Heartbeat interface {
+	Running() bool
+	LastRun() time.Time
+}
+
+// HeartbeatCheck reports whether loop is running and has beaten within maxAge
+func HeartbeatCheck(loop Heartbeat, maxAge time.Duration) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		lastRun := loop.LastRun()
+		details := map[string]interface{}{}
+		if !lastRun.IsZero() {
+			details["last_run"] = lastRun
+			details["age_seconds"] = time.Since(lastRun).Seconds()
+		}
+
+		if !loop.Running() {
+			return details, errors.New("not running")
+		}
+		if lastRun.IsZero() || time.Since(lastRun) > maxAge {
+			return details, fmt.Errorf("no heartbeat in the last %s", maxAge)
+		}
+		return details, nil
+	}
+}
+
+// BacklogCounter reports the backlog of a job queue
+type BacklogCounter interface {
+	Backlog(ctx context.Context) (jobs.Backlog, error)
+}
+
+// BacklogCheck reports the job queue backlog. The queue is down when more than
+// maxPending jobs are waiting; a maxPending of zero never fails on size.
+func BacklogCheck(queue BacklogCounter, maxPending int) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		backlog, err := queue.Backlog(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		details := map[string]interface{}{
+			"pending": backlog.Pending,
+			"running": backlog.Running,
+			"failed":  backlog.Failed,
+		}
+		if !backlog.OldestPending.IsZero() {
+			details["oldest_pending"] = backlog.OldestPending
+		}
+		if maxPending > 0 && backlog.Pending > maxPending {
+			return details, fmt.Errorf("%d jobs pending, more than %d", backlog.Pending, maxPending)
+		}
+		return details, nil
+	}
+}
@@ -0,0 +1,163 @@
+// Package health reports whether the service is alive and ready for traffic.
+// Liveness checks detect a wedged process that should be restarted; readiness
+// checks also cover the dependencies requests need and fail while the service
+// drains before shutdown.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds each check when no timeout is configured
+const DefaultTimeout = 2 * time.Second
+
+// Status is the state of a component or of the whole service
+type Status string
+
+// Status constants
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Check reports the state of one component. An error marks the component
+// down; details are included in the report either way.
+type Check func(ctx context.Context) (details map[string]interface{}, err error)
+
+// Component is the reported state of one component
+type Component struct {
+	Status  Status                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report is the result of running the checks
+type Report struct {
+	Status     Status               `json:"status"`
+	Draining   bool                 `json:"draining,omitempty"`
+	Components map[string]Component `json:"components"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the registered checks
+type Checker struct {
+	timeout  time.Duration
+	draining atomic.Bool
+
+	mu        sync.RWMutex
+	liveness  []namedCheck
+	readiness []namedCheck
+}
+
+// NewChecker creates a checker bounding every check by timeout
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// AddLivenessCheck registers a check that is part of both liveness and readiness
+func (c *Checker) AddLivenessCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveness = append(c.liveness, namedCheck{name: name, check: check})
+}
+
+// AddReadinessCheck registers a check that is only part of readiness
+func (c *Checker) AddReadinessCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readiness = append(c.readiness, namedCheck{name: name, check: check})
+}
+
+// Drain marks the service as shutting down; readiness fails from then on so
+// load balancers stop sending new requests
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Draining reports whether Drain was called
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Liveness runs the liveness checks
+func (c *Checker) Liveness(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.liveness...)
+	c.mu.RUnlock()
+
+	return c.run(ctx, checks)
+}
+
+// Readiness runs the liveness and readiness checks. It is down while draining.
+func (c *Checker) Readiness(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append(append([]namedCheck(nil), c.liveness...), c.readiness...)
+	c.mu.RUnlock()
+
+	report := c.run(ctx, checks)
+	if c.Draining() {
+		report.Status = StatusDown
+		report.Draining = true
+	}
+	return report
+}
+
+// run runs checks concurrently and combines their results
+func (c *Checker) run(ctx context.Context, checks []namedCheck) Report {
+	components := make([]Component, len(checks))
+
+	var wg sync.WaitGroup
+	for i, named := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			components[i] = c.runOne(ctx, check)
+		}(i, named.check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Components: make(map[string]Component, len(checks))}
+	for i, named := range checks {
+		report.Components[named.name] = components[i]
+		if components[i].Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// runOne runs a single check within the timeout
+func (c *Checker) runOne(ctx context.Context, check Check) Component {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type result struct {
+		details map[string]interface{}
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		details, err := check(ctx)
+		done <- result{details: details, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return Component{Status: StatusDown, Error: r.err.Error(), Details: r.details}
+		}
+		return Component{Status: StatusUp, Details: r.details}
+	case <-ctx.Done():
+		return Component{Status: StatusDown, Error: "check did not finish: " + ctx.Err().Error()}
+	}
+}
@@ -0,0 +1,199 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/health"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func up(ctx context.Context) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func down(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{"attempts": 3}, errors.New("unreachable")
+}
+
+func TestChecker_LivenessAndReadiness(t *testing.T) {
+	// Setup
+	checker := health.NewChecker(time.Second)
+	checker.AddLivenessCheck("scheduler", up)
+	checker.AddReadinessCheck("repository", down)
+
+	// Execute
+	liveness := checker.Liveness(context.Background())
+	readiness := checker.Readiness(context.Background())
+
+	// Assert - a dependency failure makes the service unready but not dead
+	assert.Equal(t, health.StatusUp, liveness.Status)
+	assert.Equal(t, []string{"scheduler"}, keys(liveness))
+
+	assert.Equal(t, health.StatusDown, readiness.Status)
+	assert.Equal(t, health.StatusUp, readiness.Components["scheduler"].Status)
+	repository := readiness.Components["repository"]
+	assert.Equal(t, health.StatusDown, repository.Status)
+	assert.Equal(t, "unreachable", repository.Error)
+	assert.Equal(t, 3, repository.Details["attempts"])
+}
+
+func TestChecker_DrainFailsReadinessOnly(t *testing.T) {
+	// Setup
+	checker := health.NewChecker(time.Second)
+	checker.AddLivenessCheck("scheduler", up)
+	checker.AddReadinessCheck("repository", up)
+
+	// Execute
+	checker.Drain()
+
+	// Assert
+	readiness := checker.Readiness(context.Background())
+	assert.True(t, checker.Draining())
+	assert.Equal(t, health.StatusDown, readiness.Status)
+	assert.True(t, readiness.Draining)
+	assert.Equal(t, health.StatusUp, readiness.Components["repository"].Status)
+	assert.Equal(t, health.StatusUp, checker.Liveness(context.Background()).Status)
+}
+
+func TestChecker_SlowCheckTimesOut(t *testing.T) {
+	// Setup
+	release := make(chan struct{})
+	defer close(release)
+	checker := health.NewChecker(20 * time.Millisecond)
+	checker.AddReadinessCheck("repository", func(ctx context.Context) (map[string]interface{}, error) {
+		<-release
+		return nil, nil
+	})
+
+	// Execute
+	started := time.Now()
+	report := checker.Readiness(context.Background())
+
+	// Assert
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Contains(t, report.Components["repository"].Error, "deadline exceeded")
+}
+
+// pinger fails its ping with err
+type pinger struct{ err error }
+
+func (p pinger) Ping(ctx context.Context) error { return p.err }
+
+func TestPingCheck(t *testing.T) {
+	// Execute
+	_, reachable := health.PingCheck(pinger{})(context.Background())
+	_, unreachable := health.PingCheck(pinger{err: errors.New("closed")})(context.Background())
+	_, inMemory := health.PingCheck(struct{}{})(context.Background())
+
+	// Assert
+	assert.NoError(t, reachable)
+	assert.EqualError(t, unreachable, "closed")
+	assert.NoError(t, inMemory)
+}
+
+func TestCacheCheck(t *testing.T) {
+	// Setup
+	cache := utils.NewInMemoryCache[utils.CacheKey, any]()
+	bookings := utils.NewNamespacedCache[int64, string](cache, "booking")
+	bookings.Set(1, "cached")
+	bookings.Get(1)
+	bookings.Get(2)
+
+	// Execute
+	details, err := health.CacheCheck(cache)(context.Background())
+
+	// Assert - the check reads the statistics without touching the cache
+	require.NoError(t, err)
+	assert.Equal(t, 1, details["entries"])
+	assert.Equal(t, uint64(1), details["hits"])
+	assert.Equal(t, uint64(1), details["misses"])
+	assert.Equal(t, utils.CacheStats{Hits: 1, Misses: 1, Entries: 1}, cache.Stats())
+}
+
+// pingedCache is a cache statistics source that is also a Pinger
+type pingedCache struct {
+	err error
+}
+
+func (c pingedCache) Stats() utils.CacheStats        { return utils.CacheStats{Hits: 3} }
+func (c pingedCache) Ping(ctx context.Context) error { return c.err }
+
+func TestCacheCheck_PingsRemoteCache(t *testing.T) {
+	// Execute
+	details, reachable := health.CacheCheck(pingedCache{})(context.Background())
+	_, unreachable := health.CacheCheck(pingedCache{err: errors.New("closed")})(context.Background())
+
+	// Assert
+	assert.NoError(t, reachable)
+	assert.Equal(t, uint64(3), details["hits"])
+	assert.EqualError(t, unreachable, "closed")
+}
+
+// heartbeat is a background loop with a fixed state
+type heartbeat struct {
+	running bool
+	lastRun time.Time
+}
+
+func (h heartbeat) Running() bool      { return h.running }
+func (h heartbeat) LastRun() time.Time { return h.lastRun }
+
+func TestHeartbeatCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		loop    heartbeat
+		healthy bool
+	}{
+		{"recent heartbeat", heartbeat{running: true, lastRun: time.Now()}, true},
+		{"stale heartbeat", heartbeat{running: true, lastRun: time.Now().Add(-time.Hour)}, false},
+		{"no heartbeat yet", heartbeat{running: true}, false},
+		{"stopped", heartbeat{lastRun: time.Now()}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			_, err := health.HeartbeatCheck(tt.loop, time.Minute)(context.Background())
+
+			// Assert
+			assert.Equal(t, tt.healthy, err == nil, "error: %v", err)
+		})
+	}
+}
+
+// backlog is a job queue with a fixed backlog
+type backlog jobs.Backlog
+
+func (b backlog) Backlog(ctx context.Context) (jobs.Backlog, error) { return jobs.Backlog(b), nil }
+
+func TestBacklogCheck(t *testing.T) {
+	// Setup
+	queue := backlog{Pending: 3, Failed: 1}
+
+	// Execute
+	details, withinLimit := health.BacklogCheck(queue, 10)(context.Background())
+	_, overLimit := health.BacklogCheck(queue, 2)(context.Background())
+	_, unlimited := health.BacklogCheck(queue, 0)(context.Background())
+
+	// Assert
+	assert.NoError(t, withinLimit)
+	assert.Equal(t, 3, details["pending"])
+	assert.Equal(t, 1, details["failed"])
+	assert.Error(t, overLimit)
+	assert.NoError(t, unlimited)
+}
+
+func keys(report health.Report) []string {
+	names := make([]string, 0, len(report.Components))
+	for name := range report.Components {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jsonfile"
+)
+
+// FileStore keeps idempotency records in memory and persists them to a JSON
+// file, so retries are still recognised after a restart. Every change
+// rewrites the file atomically.
+type FileStore struct {
+	path string
+	set  *recordSet
+	mu   sync.Mutex
+}
+
+// fileStoreData is the on-disk layout of the idempotency file
+type fileStoreData struct {
+	Records []*Record `json:"records"`
+}
+
+// OpenFileStore loads the records stored at path, creating its directory if
+// needed. Records that expired while the service was down are dropped, and so
+// are reservations without a response: the requests holding them died with
+// the previous process, and keeping them would answer every retry with a
+// conflict until the retention ends.
+func OpenFileStore(path string) (*FileStore, error) {
+	var stored fileStoreData
+	if err := jsonfile.Load(path, &stored); err != nil {
+		return nil, fmt.Errorf("load idempotency file: %w", err)
+	}
+
+	store := &FileStore{
+		path: path,
+		set:  newRecordSet(),
+	}
+	now := time.Now()
+	for _, record := range stored.Records {
+		if record.Completed() && !record.Expired(now) {
+			store.set.records[record.Key] = record
+		}
+	}
+
+	return store, nil
+}
+
+// Reserve stores record unless its key is already in use, and persists the change
+func (s *FileStore) Reserve(ctx context.Context, record *Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing := s.set.lookup(record.Key, time.Now()); existing != nil {
+		return existing, nil
+	}
+	return nil, jsonfile.Put(s.set.records, record.Key, record.Clone(), s.persist)
+}
+
+// Complete attaches the response to a reserved key and persists the change
+func (s *FileStore) Complete(ctx context.Context, key string, response *Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	completed, err := s.set.completed(key, response, time.Now())
+	if err != nil {
+		return err
+	}
+	return jsonfile.Put(s.set.records, key, completed, s.persist)
+}
+
+// Release forgets a key and persists the change
+func (s *FileStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return jsonfile.Remove(s.set.records, key, s.persist)
+}
+
+// persist writes all records to the file; the caller must hold the lock.
+// Records dropped by the sweep of expired records leave the file with it.
+func (s *FileStore) persist() error {
+	records := make([]*Record, 0, len(s.set.records))
+	for _, record := range s.set.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+
+	return jsonfile.Save(s.path, fileStoreData{Records: records})
+}
@@ -0,0 +1,56 @@
+// Package idempotency remembers the responses to requests sent with an
+// Idempotency-Key so that retries are answered without repeating the request.
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrKeyNotFound is returned when a key is not stored or has expired
+var ErrKeyNotFound = errors.New("idempotency key not found")
+
+// Response is the stored response replayed to retries
+type Response struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body"`
+}
+
+// Record tracks one idempotency key
+type Record struct {
+	Key string `json:"key"`
+	// Fingerprint identifies the request the key was first used with
+	Fingerprint string `json:"fingerprint"`
+	// Response is nil while the first request is still in flight
+	Response  *Response `json:"response,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Completed reports whether the response has been stored
+func (r *Record) Completed() bool {
+	return r.Response != nil
+}
+
+// Expired reports whether the retention window has passed at now
+func (r *Record) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// Clone returns a deep copy of the record
+func (r *Record) Clone() *Record {
+	clone := *r
+	clone.Response = r.Response.Clone()
+	return &clone
+}
+
+// Clone returns a deep copy of the response; it is nil-safe
+func (r *Response) Clone() *Response {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	clone.Body = append([]byte(nil), r.Body...)
+	return &clone
+}
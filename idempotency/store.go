@@ -0,0 +1,115 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval bounds how often expired records are purged
+const sweepInterval = time.Minute
+
+// Store keeps idempotency records until they expire
+type Store interface {
+	// Reserve stores record unless an unexpired record with the same key exists.
+	// It returns the existing record, or nil when the key was reserved.
+	Reserve(ctx context.Context, record *Record) (*Record, error)
+	// Complete attaches the response to a reserved key
+	Complete(ctx context.Context, key string, response *Response) error
+	// Release forgets a key so the request can be retried from scratch
+	Release(ctx context.Context, key string) error
+}
+
+// recordSet holds the state shared by the store implementations; callers
+// provide the locking
+type recordSet struct {
+	records   map[string]*Record
+	nextSweep time.Time
+}
+
+func newRecordSet() *recordSet {
+	return &recordSet{records: make(map[string]*Record)}
+}
+
+// lookup sweeps expired records and returns a copy of the unexpired record
+// held for key, or nil
+func (s *recordSet) lookup(key string, now time.Time) *Record {
+	s.sweep(now)
+
+	if existing, ok := s.records[key]; ok && !existing.Expired(now) {
+		return existing.Clone()
+	}
+	return nil
+}
+
+// completed returns a copy of the record held for key with response attached
+func (s *recordSet) completed(key string, response *Response, now time.Time) (*Record, error) {
+	record, ok := s.records[key]
+	if !ok || record.Expired(now) {
+		return nil, ErrKeyNotFound
+	}
+
+	completed := record.Clone()
+	completed.Response = response.Clone()
+	return completed, nil
+}
+
+// sweep drops expired records, at most once per sweepInterval
+func (s *recordSet) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(sweepInterval)
+
+	for key, record := range s.records {
+		if record.Expired(now) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// MemoryStore keeps idempotency records in memory; they do not survive a restart
+type MemoryStore struct {
+	set *recordSet
+	mu  sync.Mutex
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		set: newRecordSet(),
+	}
+}
+
+// Reserve stores record unless its key is already in use
+func (s *MemoryStore) Reserve(ctx context.Context, record *Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing := s.set.lookup(record.Key, time.Now()); existing != nil {
+		return existing, nil
+	}
+	s.set.records[record.Key] = record.Clone()
+	return nil, nil
+}
+
+// Complete attaches the response to a reserved key
+func (s *MemoryStore) Complete(ctx context.Context, key string, response *Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	completed, err := s.set.completed(key, response, time.Now())
+	if err != nil {
+		return err
+	}
+	s.set.records[key] = completed
+	return nil
+}
+
+// Release forgets a key; releasing a missing key is not an error
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.set.records, key)
+	return nil
+}
@@ -0,0 +1,130 @@
+package idempotency_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecord(key string, retention time.Duration) *idempotency.Record {
+	now := time.Now()
+	return &idempotency.Record{
+		Key:         key,
+		Fingerprint: "fp-" + key,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(retention),
+	}
+}
+
+// testStore checks the behaviour shared by every Store implementation
+func testStore(t *testing.T, store idempotency.Store) {
+	ctx := context.Background()
+
+	t.Run("reserve then replay", func(t *testing.T) {
+		// Execute
+		existing, err := store.Reserve(ctx, newRecord("a", time.Hour))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+
+		inFlight, err := store.Reserve(ctx, newRecord("a", time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, inFlight)
+		assert.False(t, inFlight.Completed())
+
+		require.NoError(t, store.Complete(ctx, "a", &idempotency.Response{
+			StatusCode:  201,
+			ContentType: "application/json",
+			Body:        []byte(`{"id":1}`),
+		}))
+		completed, err := store.Reserve(ctx, newRecord("a", time.Hour))
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, completed)
+		assert.Equal(t, "fp-a", completed.Fingerprint)
+		assert.Equal(t, 201, completed.Response.StatusCode)
+		assert.Equal(t, `{"id":1}`, string(completed.Response.Body))
+	})
+
+	t.Run("release frees the key", func(t *testing.T) {
+		// Execute
+		_, err := store.Reserve(ctx, newRecord("b", time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.Release(ctx, "b"))
+		existing, err := store.Reserve(ctx, newRecord("b", time.Hour))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+
+	t.Run("expired key can be reused", func(t *testing.T) {
+		// Execute
+		_, err := store.Reserve(ctx, newRecord("c", 10*time.Millisecond))
+		require.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+		existing, err := store.Reserve(ctx, newRecord("c", time.Hour))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+
+	t.Run("complete unknown key", func(t *testing.T) {
+		// Execute
+		err := store.Complete(ctx, "missing", &idempotency.Response{StatusCode: 201})
+
+		// Assert
+		assert.ErrorIs(t, err, idempotency.ErrKeyNotFound)
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, idempotency.NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := idempotency.OpenFileStore(filepath.Join(t.TempDir(), "idempotency.json"))
+	require.NoError(t, err)
+
+	testStore(t, store)
+}
+
+func TestFileStore_PersistsAcrossReopen(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "idempotency.json")
+	store, err := idempotency.OpenFileStore(path)
+	require.NoError(t, err)
+
+	_, err = store.Reserve(ctx, newRecord("kept", time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(ctx, "kept", &idempotency.Response{StatusCode: 201, Body: []byte(`{"id":7}`)}))
+	_, err = store.Reserve(ctx, newRecord("expiring", 10*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(ctx, "expiring", &idempotency.Response{StatusCode: 201}))
+	_, err = store.Reserve(ctx, newRecord("in-flight", time.Hour))
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	// Act
+	reopened, err := idempotency.OpenFileStore(path)
+	require.NoError(t, err)
+	kept, err := reopened.Reserve(ctx, newRecord("kept", time.Hour))
+	require.NoError(t, err)
+	expired, err := reopened.Reserve(ctx, newRecord("expiring", time.Hour))
+	require.NoError(t, err)
+	abandoned, err := reopened.Reserve(ctx, newRecord("in-flight", time.Hour))
+	require.NoError(t, err)
+
+	// Assert
+	require.NotNil(t, kept)
+	assert.Equal(t, `{"id":7}`, string(kept.Response.Body))
+	assert.Nil(t, expired)
+	assert.Nil(t, abandoned, "a reservation left by the previous process must not block retries")
+}
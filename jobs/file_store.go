@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jsonfile"
+)
+
+// FileStore keeps jobs in memory and persists them to a JSON file.
+// Every change rewrites the file atomically, so a crash leaves either the
+// previous or the new set of jobs on disk.
+type FileStore struct {
+	path string
+	jobs map[string]*Job
+	mu   sync.RWMutex
+}
+
+// fileStoreData is the on-disk layout of the job file
+type fileStoreData struct {
+	Jobs []*Job `json:"jobs"`
+}
+
+// OpenFileStore loads the jobs stored at path, creating its directory if needed
+func OpenFileStore(path string) (*FileStore, error) {
+	var stored fileStoreData
+	if err := jsonfile.Load(path, &stored); err != nil {
+		return nil, fmt.Errorf("load job file: %w", err)
+	}
+
+	store := &FileStore{
+		path: path,
+		jobs: make(map[string]*Job, len(stored.Jobs)),
+	}
+	for _, job := range stored.Jobs {
+		store.jobs[job.ID] = job
+	}
+
+	return store, nil
+}
+
+// Save inserts or replaces a job and persists the change
+func (s *FileStore) Save(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return jsonfile.Put(s.jobs, job.ID, job.Clone(), s.persist)
+}
+
+// Get returns a job by ID
+func (s *FileStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	return job.Clone(), nil
+}
+
+// Delete removes a job and persists the change
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return jsonfile.Remove(s.jobs, id, s.persist)
+}
+
+// List returns all jobs ordered by creation time
+func (s *FileStore) List(ctx context.Context) ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return sortedJobs(s.jobs), nil
+}
+
+// NextDue returns the pending job that has been due the longest, or nil
+func (s *FileStore) NextDue(ctx context.Context, now time.Time) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return nextDue(s.jobs, now), nil
+}
+
+// persist writes all jobs to the file; the caller must hold the write lock
+func (s *FileStore) persist() error {
+	return jsonfile.Save(s.path, fileStoreData{Jobs: sortedJobs(s.jobs)})
+}
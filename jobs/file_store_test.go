@@ -0,0 +1,72 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_PersistsAcrossReopen(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs", "jobs.json")
+	store, err := jobs.OpenFileStore(path)
+	require.NoError(t, err)
+
+	first := &jobs.Job{ID: "a", Type: "credit_check", Payload: json.RawMessage(`{"booking_id":1}`), Status: jobs.StatusPending, CreatedAt: time.Now()}
+	second := &jobs.Job{ID: "b", Type: "credit_check", Status: jobs.StatusFailed, LastError: "timeout", CreatedAt: time.Now().Add(time.Second)}
+	require.NoError(t, store.Save(ctx, first))
+	require.NoError(t, store.Save(ctx, second))
+	require.NoError(t, store.Delete(ctx, "a"))
+
+	// Act
+	reopened, err := jobs.OpenFileStore(path)
+	require.NoError(t, err)
+	list, err := reopened.List(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "b", list[0].ID)
+	assert.Equal(t, jobs.StatusFailed, list[0].Status)
+	assert.Equal(t, "timeout", list[0].LastError)
+
+	_, err = reopened.Get(ctx, "a")
+	assert.ErrorIs(t, err, jobs.ErrJobNotFound)
+}
+
+func TestFileStore_ReturnsCopies(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := jobs.OpenFileStore(filepath.Join(t.TempDir(), "jobs.json"))
+	require.NoError(t, err)
+	job := &jobs.Job{ID: "a", Status: jobs.StatusPending}
+	require.NoError(t, store.Save(ctx, job))
+
+	// Act - mutating the caller's copy must not change the stored job
+	job.Status = jobs.StatusFailed
+	stored, err := store.Get(ctx, "a")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, jobs.StatusPending, stored.Status)
+}
+
+func TestFileStore_CorruptFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+
+	// Act
+	_, err := jobs.OpenFileStore(path)
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+// Package jobs runs persisted background jobs on a pool of workers, retrying
+// failed jobs with exponential backoff and keeping those that exhaust their
+// attempts in a dead-letter list until they are retried by hand.
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Status represents the state of a job as a string type
+type Status string
+
+// Status constants
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	// StatusFailed marks a dead-lettered job that used up its attempts
+	StatusFailed Status = "failed"
+)
+
+var (
+	// ErrJobNotFound is returned when a job does not exist
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotFailed is returned when retrying a job that is not dead-lettered
+	ErrJobNotFailed = errors.New("job has not failed")
+)
+
+// Job is a unit of background work
+// @Description Background job and its retry state
+type Job struct {
+	ID          string          `json:"id" example:"5f2b9c1e8a7d4c3b" description:"Job ID"`
+	Type        string          `json:"type" example:"credit_check" description:"Job type"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object" description:"Job arguments"`
+	Status      Status          `json:"status" example:"failed" description:"Job status (pending, running, failed)"`
+	Attempts    int             `json:"attempts" example:"5" description:"Number of attempts made"`
+	MaxAttempts int             `json:"max_attempts" example:"5" description:"Attempts allowed before the job is dead-lettered"`
+	LastError   string          `json:"last_error,omitempty" example:"credit check failed" description:"Error from the last attempt"`
+	RequestID   string          `json:"request_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736" description:"ID of the request that enqueued the job"`
+	TraceParent string          `json:"trace_parent,omitempty" example:"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" description:"W3C traceparent of the span that enqueued the job"`
+	RunAt       time.Time       `json:"run_at" format:"date-time" example:"2024-03-11T12:00:00Z" description:"Earliest time of the next attempt"`
+	CreatedAt   time.Time       `json:"created_at" format:"date-time" example:"2024-03-11T12:00:00Z" description:"Creation timestamp"`
+	UpdatedAt   time.Time       `json:"updated_at" format:"date-time" example:"2024-03-11T12:00:00Z" description:"Last update timestamp"`
+}
+
+// Decode unmarshals the job payload into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Clone returns a copy of the job that shares no memory with it
+func (j *Job) Clone() *Job {
+	clone := *j
+	clone.Payload = append(json.RawMessage(nil), j.Payload...)
+	return &clone
+}
+
+// permanentError marks a job error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the queue dead-letters the job without further retries
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,541 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler runs a job. A returned error schedules a retry with backoff unless
+// the error is Permanent or the job has used all of its attempts.
+type Handler func(ctx context.Context, job *Job) error
+
+// Options configures a Queue; zero fields take the defaults
+type Options struct {
+	// Concurrency is the number of workers running jobs in parallel
+	Concurrency int
+	// MaxAttempts is the number of attempts before a job is dead-lettered
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles on each further retry
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// PollInterval is how often idle workers look for due jobs
+	PollInterval time.Duration
+	// FailedRetention is how long dead-lettered jobs are kept after their
+	// last attempt before they are deleted
+	FailedRetention time.Duration
+}
+
+// pruneInterval bounds how often dead-lettered jobs past their retention are deleted
+const pruneInterval = time.Hour
+
+// DefaultOptions returns the options used for zero fields
+func DefaultOptions() Options {
+	return Options{
+		Concurrency:     4,
+		MaxAttempts:     5,
+		Backoff:         time.Second,
+		MaxBackoff:      5 * time.Minute,
+		PollInterval:    time.Second,
+		FailedRetention: 7 * 24 * time.Hour,
+	}
+}
+
+// Queue runs persisted jobs on a pool of workers
+type Queue struct {
+	store   Store
+	options Options
+
+	handlers   map[string]Handler
+	handlersMu sync.RWMutex
+
+	// claimMu serializes picking the next job so no two workers run the same one
+	claimMu sync.Mutex
+	wake    chan struct{}
+	// nextPrune is when dead-lettered jobs are next pruned; guarded by claimMu
+	nextPrune time.Time
+
+	mu sync.Mutex
+	// cancel interrupts running jobs; stopClaiming only stops workers taking new ones
+	cancel       context.CancelFunc
+	stopClaiming context.CancelFunc
+	wg           sync.WaitGroup
+	// workers counts the worker goroutines currently running
+	workers atomic.Int32
+}
+
+// NewQueue creates a job queue backed by store. Call Start to run jobs.
+func NewQueue(store Store, options Options) *Queue {
+	defaults := DefaultOptions()
+	if options.Concurrency <= 0 {
+		options.Concurrency = defaults.Concurrency
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaults.MaxAttempts
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = defaults.Backoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaults.MaxBackoff
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaults.PollInterval
+	}
+	if options.FailedRetention <= 0 {
+		options.FailedRetention = defaults.FailedRetention
+	}
+
+	return &Queue{
+		store:    store,
+		options:  options,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler for a job type
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.handlersMu.Lock()
+	defer q.handlersMu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Enqueue persists a new job that runs as soon as a worker is free.
+// The payload is stored as JSON and can be read back with Job.Decode.
+// The request ID and span carried by ctx are kept so the job's logs and spans
+// can be traced back to the request.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s job payload: %w", jobType, err)
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          id,
+		Type:        jobType,
+		Payload:     data,
+		Status:      StatusPending,
+		MaxAttempts: q.options.MaxAttempts,
+		RequestID:   logging.RequestIDFromContext(ctx),
+		TraceParent: tracing.Inject(ctx),
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := q.store.Save(ctx, job); err != nil {
+		return nil, err
+	}
+
+	q.notify()
+
+	return job, nil
+}
+
+// Start recovers jobs interrupted by a crash and starts the workers.
+// Workers stop when ctx is canceled or Stop is called.
+func (q *Queue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.cancel != nil {
+		return errors.New("job queue already started")
+	}
+
+	// Jobs still marked running were interrupted; run them again
+	jobs, err := q.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Status != StatusRunning {
+			continue
+		}
+		job.Status = StatusPending
+		job.UpdatedAt = time.Now()
+		if err := q.store.Save(ctx, job); err != nil {
+			return err
+		}
+		slog.InfoContext(jobContext(ctx, job), "Recovered interrupted job")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	claimCtx, stopClaiming := context.WithCancel(ctx)
+	q.cancel, q.stopClaiming = cancel, stopClaiming
+
+	q.wg.Add(q.options.Concurrency)
+	for i := 0; i < q.options.Concurrency; i++ {
+		go q.work(ctx, claimCtx)
+	}
+
+	return nil
+}
+
+// Stop cancels running jobs and waits for the workers to exit.
+// Jobs interrupted this way are run again without using up an attempt.
+func (q *Queue) Stop() {
+	cancel, stopClaiming := q.detach()
+	if cancel == nil {
+		return
+	}
+
+	stopClaiming()
+	cancel()
+	q.wg.Wait()
+}
+
+// Shutdown stops the workers from taking new jobs and waits for the running
+// ones to finish. Jobs still running when ctx is done are canceled and run
+// again on the next Start without using up an attempt; Shutdown then returns
+// the context's error.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	cancel, stopClaiming := q.detach()
+	if cancel == nil {
+		return nil
+	}
+
+	stopClaiming()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		cancel()
+		return nil
+	case <-ctx.Done():
+		cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// detach marks the queue as stopped and returns the functions that stop its workers
+func (q *Queue) detach() (cancel, stopClaiming context.CancelFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cancel, stopClaiming = q.cancel, q.stopClaiming
+	q.cancel, q.stopClaiming = nil, nil
+	return cancel, stopClaiming
+}
+
+// Workers returns the number of worker goroutines currently running
+func (q *Queue) Workers() int {
+	return int(q.workers.Load())
+}
+
+// Failed returns the dead-lettered jobs, oldest first
+func (q *Queue) Failed(ctx context.Context) ([]*Job, error) {
+	jobs, err := q.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make([]*Job, 0)
+	for _, job := range jobs {
+		if job.Status == StatusFailed {
+			failed = append(failed, job)
+		}
+	}
+
+	return failed, nil
+}
+
+// Backlog counts the stored jobs by state
+type Backlog struct {
+	// Pending jobs are waiting for a worker, including those backing off before a retry
+	Pending int `json:"pending"`
+	Running int `json:"running"`
+	Failed  int `json:"failed"`
+	// OldestPending is when the longest-waiting pending job was queued; zero when none are
+	OldestPending time.Time `json:"oldest_pending,omitempty"`
+}
+
+// Backlog returns how many jobs are waiting, running and dead-lettered
+func (q *Queue) Backlog(ctx context.Context) (Backlog, error) {
+	jobs, err := q.store.List(ctx)
+	if err != nil {
+		return Backlog{}, err
+	}
+
+	var backlog Backlog
+	for _, job := range jobs {
+		switch job.Status {
+		case StatusPending:
+			backlog.Pending++
+			if backlog.OldestPending.IsZero() || job.CreatedAt.Before(backlog.OldestPending) {
+				backlog.OldestPending = job.CreatedAt
+			}
+		case StatusRunning:
+			backlog.Running++
+		case StatusFailed:
+			backlog.Failed++
+		}
+	}
+
+	return backlog, nil
+}
+
+// Retry moves a dead-lettered job back to the queue with a fresh set of attempts
+func (q *Queue) Retry(ctx context.Context, id string) (*Job, error) {
+	q.claimMu.Lock()
+	defer q.claimMu.Unlock()
+
+	job, err := q.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != StatusFailed {
+		return nil, ErrJobNotFailed
+	}
+
+	now := time.Now()
+	job.Status = StatusPending
+	job.Attempts = 0
+	job.RunAt = now
+	job.UpdatedAt = now
+
+	if err := q.store.Save(ctx, job); err != nil {
+		return nil, err
+	}
+
+	q.notify()
+
+	return job, nil
+}
+
+// work runs due jobs in ctx until claimCtx is canceled
+func (q *Queue) work(ctx, claimCtx context.Context) {
+	defer q.wg.Done()
+	q.workers.Add(1)
+	defer q.workers.Add(-1)
+
+	timer := time.NewTimer(q.options.PollInterval)
+	defer timer.Stop()
+
+	for claimCtx.Err() == nil {
+		job, err := q.claim(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error claiming job", "error", err)
+		}
+		if job != nil {
+			// Let another worker look for more due jobs
+			q.notify()
+			q.run(ctx, job)
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(q.options.PollInterval)
+
+		select {
+		case <-claimCtx.Done():
+		case <-q.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// claim marks the next due job as running and returns it, or nil when none is due
+func (q *Queue) claim(ctx context.Context) (*Job, error) {
+	q.claimMu.Lock()
+	defer q.claimMu.Unlock()
+
+	now := time.Now()
+	q.pruneFailed(ctx, now)
+
+	job, err := q.store.NextDue(ctx, now)
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	job.Status = StatusRunning
+	job.Attempts++
+	job.UpdatedAt = now
+
+	if err := q.store.Save(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// pruneFailed deletes dead-lettered jobs whose last attempt is older than
+// FailedRetention. It runs at most once per pruneInterval, or per retention
+// if that is shorter; the caller must hold claimMu.
+func (q *Queue) pruneFailed(ctx context.Context, now time.Time) {
+	if now.Before(q.nextPrune) {
+		return
+	}
+	q.nextPrune = now.Add(min(q.options.FailedRetention, pruneInterval))
+
+	jobs, err := q.store.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing jobs to prune", "error", err)
+		return
+	}
+
+	pruned := 0
+	for _, job := range jobs {
+		if job.Status != StatusFailed || now.Sub(job.UpdatedAt) < q.options.FailedRetention {
+			continue
+		}
+		if err := q.store.Delete(ctx, job.ID); err != nil {
+			slog.ErrorContext(jobContext(ctx, job), "Error deleting dead-lettered job", "error", err)
+			return
+		}
+		pruned++
+	}
+	if pruned > 0 {
+		slog.InfoContext(ctx, "Deleted dead-lettered jobs past their retention", "count", pruned, "retention", q.options.FailedRetention)
+	}
+}
+
+// run executes a claimed job and records the outcome
+func (q *Queue) run(ctx context.Context, job *Job) {
+	ctx, span := startJobSpan(jobContext(ctx, job), job)
+	err := q.execute(ctx, job)
+	tracing.End(span, err)
+
+	// The store must still be updated when the queue is stopping
+	storeCtx := jobContext(context.Background(), job)
+	now := time.Now()
+
+	if err == nil {
+		if err := q.store.Delete(storeCtx, job.ID); err != nil {
+			slog.ErrorContext(storeCtx, "Error removing completed job", "error", err)
+		}
+		return
+	}
+
+	job.LastError = err.Error()
+	job.UpdatedAt = now
+
+	switch {
+	case ctx.Err() != nil:
+		// Interrupted by Stop or Shutdown; the attempt does not count
+		job.Status = StatusPending
+		job.Attempts--
+		job.RunAt = now
+		slog.WarnContext(storeCtx, "Job interrupted by shutdown, it will run again after restart", "error", err)
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		job.Status = StatusFailed
+		slog.ErrorContext(storeCtx, "Job failed", "attempts", job.Attempts, "error", err)
+	default:
+		job.Status = StatusPending
+		job.RunAt = now.Add(q.backoff(job.Attempts))
+		slog.WarnContext(storeCtx, "Job attempt failed, retrying",
+			"attempt", job.Attempts, "retry_at", job.RunAt, "error", err)
+	}
+
+	if err := q.store.Save(storeCtx, job); err != nil {
+		slog.ErrorContext(storeCtx, "Error saving job", "error", err)
+	}
+}
+
+// execute calls the job's handler, turning a panic into an error
+func (q *Queue) execute(ctx context.Context, job *Job) (err error) {
+	q.handlersMu.RLock()
+	handler, exists := q.handlers[job.Type]
+	q.handlersMu.RUnlock()
+
+	if !exists {
+		return Permanent(fmt.Errorf("no handler registered for job type %q", job.Type))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.Clone())
+}
+
+// jobContext returns ctx carrying the job's request ID and identity for logging
+func jobContext(ctx context.Context, job *Job) context.Context {
+	if job.RequestID != "" {
+		ctx = logging.WithRequestID(ctx, job.RequestID)
+	}
+	return logging.With(ctx, "job_id", job.ID, "job_type", job.Type)
+}
+
+// startJobSpan starts the span of one attempt at job. Attempts run long after
+// the request that enqueued the job has been answered, so each gets a trace
+// of its own, linked to the span of that request.
+func startJobSpan(ctx context.Context, job *Job) (context.Context, trace.Span) {
+	options := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("job.id", job.ID),
+			attribute.String("job.type", job.Type),
+			attribute.Int("job.attempt", job.Attempts),
+		),
+	}
+	if origin := tracing.Extract(job.TraceParent); origin.IsValid() {
+		options = append(options, trace.WithLinks(trace.Link{SpanContext: origin}))
+	}
+	return tracing.Tracer().Start(ctx, "job "+job.Type, options...)
+}
+
+// backoff returns the delay before the retry following the given attempt
+func (q *Queue) backoff(attempt int) time.Duration {
+	delay := q.options.Backoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= q.options.MaxBackoff {
+			return q.options.MaxBackoff
+		}
+	}
+	if delay > q.options.MaxBackoff {
+		return q.options.MaxBackoff
+	}
+	return delay
+}
+
+// notify wakes an idle worker without blocking
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// newJobID returns a random 16-character hex job ID
+func newJobID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
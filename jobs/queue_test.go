@@ -0,0 +1,541 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testOptions keeps retries fast enough for unit tests
+var testOptions = jobs.Options{
+	Concurrency:  2,
+	MaxAttempts:  3,
+	Backoff:      time.Millisecond,
+	MaxBackoff:   5 * time.Millisecond,
+	PollInterval: 5 * time.Millisecond,
+}
+
+func startQueue(t *testing.T, store jobs.Store, register func(q *jobs.Queue)) *jobs.Queue {
+	t.Helper()
+
+	queue := jobs.NewQueue(store, testOptions)
+	register(queue)
+	require.NoError(t, queue.Start(context.Background()))
+	t.Cleanup(queue.Stop)
+
+	return queue
+}
+
+// waitForJobs waits until the store holds exactly n jobs
+func waitForJobs(t *testing.T, store jobs.Store, n int) []*jobs.Job {
+	t.Helper()
+
+	var list []*jobs.Job
+	assert.Eventually(t, func() bool {
+		list, _ = store.List(context.Background())
+		return len(list) == n
+	}, time.Second, time.Millisecond)
+
+	return list
+}
+
+// waitForFailed waits until the queue has exactly n dead-lettered jobs
+func waitForFailed(t *testing.T, queue *jobs.Queue, n int) []*jobs.Job {
+	t.Helper()
+
+	var failed []*jobs.Job
+	require.Eventually(t, func() bool {
+		failed, _ = queue.Failed(context.Background())
+		return len(failed) == n
+	}, time.Second, time.Millisecond)
+
+	return failed
+}
+
+func TestQueue_RunsJobAndRemovesIt(t *testing.T) {
+	// Arrange
+	store := jobs.NewMemoryStore()
+	received := make(chan int64, 1)
+	queue := startQueue(t, store, func(q *jobs.Queue) {
+		q.Register("greet", func(ctx context.Context, job *jobs.Job) error {
+			var payload struct {
+				BookingID int64 `json:"booking_id"`
+			}
+			if err := job.Decode(&payload); err != nil {
+				return err
+			}
+			received <- payload.BookingID
+			return nil
+		})
+	})
+
+	// Act
+	job, err := queue.Enqueue(context.Background(), "greet", map[string]int64{"booking_id": 42})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusPending, job.Status)
+	select {
+	case id := <-received:
+		assert.Equal(t, int64(42), id)
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+	waitForJobs(t, store, 0)
+}
+
+func TestQueue_CarriesRequestIDToHandler(t *testing.T) {
+	// Arrange
+	received := make(chan string, 1)
+	queue := startQueue(t, jobs.NewMemoryStore(), func(q *jobs.Queue) {
+		q.Register("greet", func(ctx context.Context, job *jobs.Job) error {
+			received <- logging.RequestIDFromContext(ctx)
+			return nil
+		})
+	})
+	ctx := logging.WithRequestID(context.Background(), "req-42")
+
+	// Act
+	job, err := queue.Enqueue(ctx, "greet", nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "req-42", job.RequestID)
+	select {
+	case requestID := <-received:
+		assert.Equal(t, "req-42", requestID)
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+}
+
+func TestQueue_LinksJobSpanToEnqueuingSpan(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	tracing.Install(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	received := make(chan trace.SpanContext, 1)
+	queue := startQueue(t, jobs.NewMemoryStore(), func(q *jobs.Queue) {
+		q.Register("greet", func(ctx context.Context, job *jobs.Job) error {
+			received <- trace.SpanContextFromContext(ctx)
+			return nil
+		})
+	})
+	ctx, request := tracing.Tracer().Start(context.Background(), "POST /api/bookings")
+	defer request.End()
+
+	// Act
+	job, err := queue.Enqueue(ctx, "greet", nil)
+	require.NoError(t, err)
+
+	// Assert - the job runs in a trace of its own that links back to the request
+	assert.Equal(t, tracing.Inject(ctx), job.TraceParent)
+	var handlerSpan trace.SpanContext
+	select {
+	case handlerSpan = <-received:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+	assert.NotEqual(t, request.SpanContext().TraceID(), handlerSpan.TraceID())
+
+	var spans []sdktrace.ReadOnlySpan
+	require.Eventually(t, func() bool {
+		spans = recorder.Ended()
+		return len(spans) == 1
+	}, time.Second, time.Millisecond)
+	span := spans[0]
+	assert.Equal(t, "job greet", span.Name())
+	assert.Equal(t, trace.SpanKindConsumer, span.SpanKind())
+	assert.Equal(t, handlerSpan.SpanID(), span.SpanContext().SpanID())
+	require.Len(t, span.Links(), 1)
+	assert.Equal(t, request.SpanContext().TraceID(), span.Links()[0].SpanContext.TraceID())
+	assert.Equal(t, request.SpanContext().SpanID(), span.Links()[0].SpanContext.SpanID())
+}
+
+func TestQueue_RetriesUntilSuccess(t *testing.T) {
+	// Arrange - fail twice, then succeed
+	store := jobs.NewMemoryStore()
+	var calls int32
+	queue := startQueue(t, store, func(q *jobs.Queue) {
+		q.Register("flaky", func(ctx context.Context, job *jobs.Job) error {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return errors.New("temporarily unavailable")
+			}
+			return nil
+		})
+	})
+
+	// Act
+	_, err := queue.Enqueue(context.Background(), "flaky", nil)
+	require.NoError(t, err)
+
+	// Assert
+	waitForJobs(t, store, 0)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestQueue_DeadLettersAfterMaxAttempts(t *testing.T) {
+	// Arrange
+	store := jobs.NewMemoryStore()
+	var calls int32
+	queue := startQueue(t, store, func(q *jobs.Queue) {
+		q.Register("broken", func(ctx context.Context, job *jobs.Job) error {
+			atomic.AddInt32(&calls, 1)
+			return errors.New("service down")
+		})
+	})
+
+	// Act
+	job, err := queue.Enqueue(context.Background(), "broken", nil)
+	require.NoError(t, err)
+
+	// Assert
+	var failed []*jobs.Job
+	assert.Eventually(t, func() bool {
+		failed, _ = queue.Failed(context.Background())
+		return len(failed) == 1
+	}, time.Second, time.Millisecond)
+	require.Len(t, failed, 1)
+	assert.Equal(t, job.ID, failed[0].ID)
+	assert.Equal(t, jobs.StatusFailed, failed[0].Status)
+	assert.Equal(t, 3, failed[0].Attempts)
+	assert.Equal(t, "service down", failed[0].LastError)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestQueue_PermanentErrorSkipsRetries(t *testing.T) {
+	// Arrange
+	store := jobs.NewMemoryStore()
+	var calls int32
+	queue := startQueue(t, store, func(q *jobs.Queue) {
+		q.Register("invalid", func(ctx context.Context, job *jobs.Job) error {
+			atomic.AddInt32(&calls, 1)
+			return jobs.Permanent(errors.New("bad payload"))
+		})
+	})
+
+	// Act
+	_, err := queue.Enqueue(context.Background(), "invalid", nil)
+	require.NoError(t, err)
+
+	// Assert
+	list := waitForFailed(t, queue, 1)
+	assert.Equal(t, 1, list[0].Attempts)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestQueue_UnknownJobTypeIsDeadLettered(t *testing.T) {
+	// Arrange
+	queue := startQueue(t, jobs.NewMemoryStore(), func(q *jobs.Queue) {})
+
+	// Act
+	_, err := queue.Enqueue(context.Background(), "mystery", nil)
+	require.NoError(t, err)
+
+	// Assert
+	list := waitForFailed(t, queue, 1)
+	assert.Contains(t, list[0].LastError, `no handler registered for job type "mystery"`)
+}
+
+func TestQueue_PanicIsRecordedAsFailure(t *testing.T) {
+	// Arrange
+	queue := startQueue(t, jobs.NewMemoryStore(), func(q *jobs.Queue) {
+		q.Register("panics", func(ctx context.Context, job *jobs.Job) error {
+			panic("boom")
+		})
+	})
+
+	// Act
+	_, err := queue.Enqueue(context.Background(), "panics", nil)
+	require.NoError(t, err)
+
+	// Assert
+	list := waitForFailed(t, queue, 1)
+	assert.Equal(t, "job panicked: boom", list[0].LastError)
+}
+
+func TestQueue_RetryRequeuesFailedJob(t *testing.T) {
+	// Arrange - the handler fails until it is fixed
+	store := jobs.NewMemoryStore()
+	var fixed atomic.Value
+	fixed.Store(false)
+	queue := startQueue(t, store, func(q *jobs.Queue) {
+		q.Register("report", func(ctx context.Context, job *jobs.Job) error {
+			if !fixed.Load().(bool) {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+	})
+	job, err := queue.Enqueue(context.Background(), "report", nil)
+	require.NoError(t, err)
+	waitForFailed(t, queue, 1)
+
+	// Act
+	fixed.Store(true)
+	retried, err := queue.Retry(context.Background(), job.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusPending, retried.Status)
+	assert.Equal(t, 0, retried.Attempts)
+	waitForJobs(t, store, 0)
+}
+
+func TestQueue_RetryErrors(t *testing.T) {
+	// Arrange - a queue without workers keeps the job pending
+	store := jobs.NewMemoryStore()
+	queue := jobs.NewQueue(store, testOptions)
+	job, err := queue.Enqueue(context.Background(), "report", nil)
+	require.NoError(t, err)
+
+	// Act
+	_, notFailedErr := queue.Retry(context.Background(), job.ID)
+	_, notFoundErr := queue.Retry(context.Background(), "missing")
+
+	// Assert
+	assert.ErrorIs(t, notFailedErr, jobs.ErrJobNotFailed)
+	assert.ErrorIs(t, notFoundErr, jobs.ErrJobNotFound)
+}
+
+func TestQueue_StartRecoversInterruptedJobs(t *testing.T) {
+	// Arrange - a job left running by a crashed process
+	store := jobs.NewMemoryStore()
+	require.NoError(t, store.Save(context.Background(), &jobs.Job{
+		ID:          "interrupted",
+		Type:        "resume",
+		Status:      jobs.StatusRunning,
+		Attempts:    1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}))
+
+	ran := make(chan string, 1)
+
+	// Act
+	startQueue(t, store, func(q *jobs.Queue) {
+		q.Register("resume", func(ctx context.Context, job *jobs.Job) error {
+			ran <- job.ID
+			return nil
+		})
+	})
+
+	// Assert
+	select {
+	case id := <-ran:
+		assert.Equal(t, "interrupted", id)
+	case <-time.After(time.Second):
+		t.Fatal("interrupted job was not resumed")
+	}
+}
+
+func TestQueue_StopRequeuesRunningJobWithoutUsingAttempt(t *testing.T) {
+	// Arrange - a job that runs until it is canceled
+	store := jobs.NewMemoryStore()
+	started := make(chan struct{})
+	queue := jobs.NewQueue(store, testOptions)
+	queue.Register("long", func(ctx context.Context, job *jobs.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, queue.Start(context.Background()))
+
+	job, err := queue.Enqueue(context.Background(), "long", nil)
+	require.NoError(t, err)
+	<-started
+
+	// Act
+	queue.Stop()
+
+	// Assert
+	stored, err := store.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusPending, stored.Status)
+	assert.Equal(t, 0, stored.Attempts)
+}
+
+func TestQueue_ShutdownWaitsForRunningJobs(t *testing.T) {
+	// Arrange - a job that finishes once released, and one queued behind it
+	store := jobs.NewMemoryStore()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	queue := jobs.NewQueue(store, jobs.Options{Concurrency: 1, PollInterval: 5 * time.Millisecond})
+	queue.Register("slow", func(ctx context.Context, job *jobs.Job) error {
+		close(started)
+		<-release
+		return ctx.Err()
+	})
+	queue.Register("later", func(ctx context.Context, job *jobs.Job) error {
+		t.Error("a job was started after Shutdown")
+		return nil
+	})
+	require.NoError(t, queue.Start(context.Background()))
+
+	slow, err := queue.Enqueue(context.Background(), "slow", nil)
+	require.NoError(t, err)
+	<-started
+	later, err := queue.Enqueue(context.Background(), "later", nil)
+	require.NoError(t, err)
+
+	// Act
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- queue.Shutdown(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	// Assert - the running job completed; the queued one waits for the next start
+	select {
+	case err := <-shutdown:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown did not return")
+	}
+	_, err = store.Get(context.Background(), slow.ID)
+	assert.ErrorIs(t, err, jobs.ErrJobNotFound)
+	stored, err := store.Get(context.Background(), later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusPending, stored.Status)
+	assert.Equal(t, 0, queue.Workers())
+}
+
+func TestQueue_ShutdownDeadlineRequeuesRunningJob(t *testing.T) {
+	// Arrange - a job that runs until it is canceled
+	store := jobs.NewMemoryStore()
+	started := make(chan struct{})
+	queue := jobs.NewQueue(store, testOptions)
+	queue.Register("long", func(ctx context.Context, job *jobs.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, queue.Start(context.Background()))
+
+	job, err := queue.Enqueue(context.Background(), "long", nil)
+	require.NoError(t, err)
+	<-started
+
+	// Act
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = queue.Shutdown(ctx)
+
+	// Assert - the job is kept for the next start without using up an attempt
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	stored, err := store.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusPending, stored.Status)
+	assert.Equal(t, 0, stored.Attempts)
+	assert.NoError(t, queue.Shutdown(context.Background()), "shutting down twice is a no-op")
+}
+
+func TestQueue_Workers(t *testing.T) {
+	// Arrange
+	queue := jobs.NewQueue(jobs.NewMemoryStore(), testOptions)
+
+	// Act
+	require.NoError(t, queue.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return queue.Workers() == testOptions.Concurrency
+	}, time.Second, time.Millisecond)
+	queue.Stop()
+
+	// Assert - Stop waits for every worker
+	assert.Equal(t, 0, queue.Workers())
+}
+
+func TestQueue_Backlog(t *testing.T) {
+	// Arrange - a stopped queue, so nothing is picked up
+	store := jobs.NewMemoryStore()
+	queue := jobs.NewQueue(store, testOptions)
+	ctx := context.Background()
+	first, err := queue.Enqueue(ctx, "greet", nil)
+	require.NoError(t, err)
+	_, err = queue.Enqueue(ctx, "greet", nil)
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, store.Save(ctx, &jobs.Job{ID: "running", Status: jobs.StatusRunning, CreatedAt: now}))
+	require.NoError(t, store.Save(ctx, &jobs.Job{ID: "failed", Status: jobs.StatusFailed, CreatedAt: now}))
+
+	// Act
+	backlog, err := queue.Backlog(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, backlog.Pending)
+	assert.Equal(t, 1, backlog.Running)
+	assert.Equal(t, 1, backlog.Failed)
+	assert.True(t, first.CreatedAt.Equal(backlog.OldestPending))
+}
+
+func TestQueue_StartTwice(t *testing.T) {
+	// Arrange
+	queue := startQueue(t, jobs.NewMemoryStore(), func(q *jobs.Queue) {})
+
+	// Act
+	err := queue.Start(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestQueue_DeletesFailedJobsPastRetention(t *testing.T) {
+	// Arrange - one job was dead-lettered long ago, the other recently
+	ctx := context.Background()
+	store := jobs.NewMemoryStore()
+	now := time.Now()
+	require.NoError(t, store.Save(ctx, &jobs.Job{ID: "old", Status: jobs.StatusFailed, CreatedAt: now.Add(-3 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)}))
+	require.NoError(t, store.Save(ctx, &jobs.Job{ID: "recent", Status: jobs.StatusFailed, CreatedAt: now.Add(-3 * time.Hour), UpdatedAt: now.Add(-time.Minute)}))
+	options := testOptions
+	options.FailedRetention = time.Hour
+	queue := jobs.NewQueue(store, options)
+
+	// Act
+	require.NoError(t, queue.Start(ctx))
+	t.Cleanup(queue.Stop)
+
+	// Assert
+	remaining := waitForJobs(t, store, 1)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "recent", remaining[0].ID)
+}
+
+func TestStore_NextDue(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Now()
+	store := jobs.NewMemoryStore()
+	for _, job := range []*jobs.Job{
+		{ID: "later", Status: jobs.StatusPending, RunAt: now.Add(-time.Second)},
+		{ID: "overdue", Status: jobs.StatusPending, RunAt: now.Add(-time.Minute)},
+		{ID: "future", Status: jobs.StatusPending, RunAt: now.Add(time.Minute)},
+		{ID: "failed", Status: jobs.StatusFailed, RunAt: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, store.Save(ctx, job))
+	}
+
+	// Act
+	next, err := store.NextDue(ctx, now)
+	require.NoError(t, err)
+	none, err := store.NextDue(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	// Assert
+	require.NotNil(t, next)
+	assert.Equal(t, "overdue", next.ID)
+	assert.Nil(t, none)
+}
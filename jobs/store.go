@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store persists jobs between attempts and across restarts
+type Store interface {
+	// Save inserts the job or replaces the stored job with the same ID
+	Save(ctx context.Context, job *Job) error
+	Get(ctx context.Context, id string) (*Job, error)
+	Delete(ctx context.Context, id string) error
+	// List returns every stored job ordered by creation time
+	List(ctx context.Context) ([]*Job, error)
+	// NextDue returns the pending job whose RunAt is the earliest at or
+	// before now, or nil when no job is due
+	NextDue(ctx context.Context, now time.Time) (*Job, error)
+}
+
+// MemoryStore keeps jobs in memory; they do not survive a restart
+type MemoryStore struct {
+	jobs map[string]*Job
+	mu   sync.RWMutex
+}
+
+// NewMemoryStore creates an empty in-memory job store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs: make(map[string]*Job),
+	}
+}
+
+// Save inserts or replaces a job
+func (s *MemoryStore) Save(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job.Clone()
+	return nil
+}
+
+// Get returns a job by ID
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	return job.Clone(), nil
+}
+
+// Delete removes a job; deleting a missing job is not an error
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+// List returns all jobs ordered by creation time
+func (s *MemoryStore) List(ctx context.Context) ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return sortedJobs(s.jobs), nil
+}
+
+// NextDue returns the pending job that has been due the longest, or nil
+func (s *MemoryStore) NextDue(ctx context.Context, now time.Time) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return nextDue(s.jobs, now), nil
+}
+
+// nextDue returns a copy of the pending job with the earliest RunAt at or
+// before now, breaking ties by creation time and ID, or nil
+func nextDue(jobs map[string]*Job, now time.Time) *Job {
+	var next *Job
+	for _, job := range jobs {
+		if job.Status != StatusPending || job.RunAt.After(now) {
+			continue
+		}
+		if next == nil || job.RunAt.Before(next.RunAt) ||
+			job.RunAt.Equal(next.RunAt) && createdBefore(job, next) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	return next.Clone()
+}
+
+// createdBefore orders jobs by creation time, then ID
+func createdBefore(a, b *Job) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// sortedJobs copies jobs into a slice ordered by creation time, then ID
+func sortedJobs(jobs map[string]*Job) []*Job {
+	list := make([]*Job, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, job.Clone())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return createdBefore(list[i], list[j])
+	})
+	return list
+}
@@ -0,0 +1,81 @@
+// Package jsonfile persists small stores that are kept in memory, such as
+// jobs, idempotency keys and API keys, as JSON files rewritten in full on
+// every change.
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/atomicfile"
+)
+
+// Mode is the permission of the files. They hold service state such as API
+// key hashes, so they are private to the service user.
+const Mode = 0o600
+
+// Load decodes the file at path into v, creating the file's directory if
+// needed. A missing file leaves v untouched.
+func Load(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Save encodes v and replaces the file at path with it atomically
+func Save(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data, Mode)
+}
+
+// Put stores value under key in m and calls save. When save fails the
+// previous entry is put back, so m keeps matching the file.
+func Put[K comparable, V any](m map[K]V, key K, value V, save func() error) error {
+	previous, existed := m[key]
+	m[key] = value
+
+	if err := save(); err != nil {
+		if existed {
+			m[key] = previous
+		} else {
+			delete(m, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// Remove deletes key from m and calls save. When save fails the entry is put
+// back. Removing a missing key does not call save.
+func Remove[K comparable, V any](m map[K]V, key K, save func() error) error {
+	previous, existed := m[key]
+	if !existed {
+		return nil
+	}
+	delete(m, key)
+
+	if err := save(); err != nil {
+		m[key] = previous
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package jsonfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jsonfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type state struct {
+	Names []string `json:"names"`
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "data", "state.json")
+	var missing state
+	require.NoError(t, jsonfile.Load(path, &missing))
+
+	// Act
+	require.NoError(t, jsonfile.Save(path, state{Names: []string{"a", "b"}}))
+	var loaded state
+	err := jsonfile.Load(path, &loaded)
+
+	// Assert - a missing file loads as nothing, and saved files are private
+	require.NoError(t, err)
+	assert.Empty(t, missing.Names)
+	assert.Equal(t, []string{"a", "b"}, loaded.Names)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(jsonfile.Mode), info.Mode().Perm())
+}
+
+func TestLoad_CorruptFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+	// Act
+	var loaded state
+	err := jsonfile.Load(path, &loaded)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path)
+}
+
+func TestPutAndRemove_RollBackFailedSaves(t *testing.T) {
+	// Arrange
+	m := map[string]int{"kept": 1}
+	failed := errors.New("disk full")
+	fail := func() error { return failed }
+
+	// Act
+	replaceErr := jsonfile.Put(m, "kept", 2, fail)
+	insertErr := jsonfile.Put(m, "new", 3, fail)
+	removeErr := jsonfile.Remove(m, "kept", fail)
+	missingErr := jsonfile.Remove(m, "missing", fail)
+
+	// Assert - the map is left as it was
+	assert.ErrorIs(t, replaceErr, failed)
+	assert.ErrorIs(t, insertErr, failed)
+	assert.ErrorIs(t, removeErr, failed)
+	assert.NoError(t, missingErr)
+	assert.Equal(t, map[string]int{"kept": 1}, m)
+}
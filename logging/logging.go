@@ -0,0 +1,138 @@
+// Package logging configures structured logging with log/slog. Loggers built
+// by New add the request ID, the trace and span IDs and any attributes carried
+// by the context to every record logged with a context, e.g. slog.InfoContext.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Attributes added from the context
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+)
+
+// Config configures the logger built by New
+type Config struct {
+	Level slog.Level
+	// Format is FormatJSON or FormatText; JSON when empty
+	Format string
+}
+
+// ParseLevel reads a level name: debug, info, warn or error
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", value)
+	}
+	return level, nil
+}
+
+// ParseFormat reads a format name: json or text
+func ParseFormat(value string) (string, error) {
+	switch format := strings.ToLower(value); format {
+	case FormatJSON, FormatText:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid log format %q: must be json or text", value)
+	}
+}
+
+// New builds a logger writing to w
+func New(w io.Writer, config Config) *slog.Logger {
+	options := &slog.HandlerOptions{Level: config.Level}
+
+	var handler slog.Handler
+	if config.Format == FormatText {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	attrsKey
+)
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" when there is none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// With returns a context whose log records get the given attributes, passed
+// as alternating keys and values like slog.Logger.With
+func With(ctx context.Context, args ...any) context.Context {
+	record := slog.Record{}
+	record.Add(args...)
+
+	attrs := append([]slog.Attr(nil), attrsFromContext(ctx)...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	return context.WithValue(ctx, attrsKey, attrs)
+}
+
+func attrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey).([]slog.Attr)
+	return attrs
+}
+
+// contextHandler adds the request ID, the current span and the attributes from
+// the context to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the context attributes to record before passing it on
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if requestID := RequestIDFromContext(ctx); requestID != "" {
+			record.AddAttrs(slog.String(RequestIDKey, requestID))
+		}
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			record.AddAttrs(
+				slog.String(TraceIDKey, span.TraceID().String()),
+				slog.String(SpanIDKey, span.SpanID().String()),
+			)
+		}
+		record.AddAttrs(attrsFromContext(ctx)...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs keeps the context handling for derived loggers
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps the context handling for derived loggers
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
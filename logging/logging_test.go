@@ -0,0 +1,130 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestNew_AddsContextAttributes(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logging.Config{Level: slog.LevelInfo})
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	ctx = logging.With(ctx, "job_id", "job-1")
+	ctx = logging.With(ctx, "booking_id", int64(7))
+
+	// Execute
+	logger.InfoContext(ctx, "Credit check completed", "outcome", "approved")
+
+	// Assert
+	entry := decodeLine(t, &buf)
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "Credit check completed", entry["msg"])
+	assert.Equal(t, "req-1", entry[logging.RequestIDKey])
+	assert.Equal(t, "job-1", entry["job_id"])
+	assert.Equal(t, float64(7), entry["booking_id"])
+	assert.Equal(t, "approved", entry["outcome"])
+}
+
+func TestNew_DerivedLoggersKeepContextAttributes(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logging.Config{}).With("component", "jobs")
+	ctx := logging.WithRequestID(context.Background(), "req-2")
+
+	// Execute
+	logger.InfoContext(ctx, "Job started")
+
+	// Assert
+	entry := decodeLine(t, &buf)
+	assert.Equal(t, "jobs", entry["component"])
+	assert.Equal(t, "req-2", entry[logging.RequestIDKey])
+}
+
+func TestNew_LevelAndFormat(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logging.Config{Level: slog.LevelWarn, Format: logging.FormatText})
+
+	// Execute
+	logger.Info("Hidden")
+	logger.Warn("Shown", "booking_id", 1)
+
+	// Assert
+	out := buf.String()
+	assert.NotContains(t, out, "Hidden")
+	assert.True(t, strings.HasPrefix(out, "time="))
+	assert.Contains(t, out, `msg=Shown booking_id=1`)
+}
+
+func TestWith_DoesNotLeakIntoParentContext(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logging.Config{})
+	parent := logging.With(context.Background(), "a", 1)
+	_ = logging.With(parent, "b", 2)
+
+	// Execute
+	logger.InfoContext(parent, "Parent")
+
+	// Assert
+	entry := decodeLine(t, &buf)
+	assert.Equal(t, float64(1), entry["a"])
+	assert.NotContains(t, entry, "b")
+}
+
+func TestParseLevelAndFormat(t *testing.T) {
+	// Execute
+	debug, debugErr := logging.ParseLevel("debug")
+	warn, warnErr := logging.ParseLevel("WARN")
+	_, badLevelErr := logging.ParseLevel("verbose")
+	text, textErr := logging.ParseFormat("text")
+	_, badFormatErr := logging.ParseFormat("xml")
+
+	// Assert
+	require.NoError(t, debugErr)
+	require.NoError(t, warnErr)
+	require.NoError(t, textErr)
+	assert.Equal(t, slog.LevelDebug, debug)
+	assert.Equal(t, slog.LevelWarn, warn)
+	assert.Equal(t, logging.FormatText, text)
+	assert.Error(t, badLevelErr)
+	assert.Error(t, badFormatErr)
+}
+
+func TestNew_AddsTraceAndSpanIDs(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logging.Config{})
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	// Execute
+	logger.InfoContext(ctx, "Booking created")
+
+	// Assert
+	entry := decodeLine(t, &buf)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry[logging.TraceIDKey])
+	assert.Equal(t, "00f067aa0ba902b7", entry[logging.SpanIDKey])
+}
@@ -0,0 +1,136 @@
+// Package metrics collects the service's Prometheus metrics: HTTP traffic,
+// cache efficiency, the booking lifecycle and background work.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the collectors and the registry they are exposed from
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpDuration        *prometheus.HistogramVec
+	cacheLookups        *prometheus.CounterVec
+	bookingStatuses     *prometheus.CounterVec
+	creditChecks        *prometheus.CounterVec
+	creditCheckDuration *prometheus.HistogramVec
+	expiryRuns          prometheus.Counter
+	expiredBookings     prometheus.Counter
+}
+
+// New creates the metrics on a registry of their own, together with the Go
+// runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_lookups_total",
+			Help: "Booking cache lookups by result (hit or miss).",
+		}, []string{"result"}),
+		bookingStatuses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bookings_status_total",
+			Help: "Bookings entering each status; created bookings count as pending.",
+		}, []string{"status"}),
+		creditChecks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "credit_checks_total",
+			Help: "Completed credit checks by outcome.",
+		}, []string{"outcome"}),
+		creditCheckDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "credit_check_duration_seconds",
+			Help:    "Credit check duration including retries, by outcome.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"outcome"}),
+		expiryRuns: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "expiry_runs_total",
+			Help: "Completed expiry job runs.",
+		}),
+		expiredBookings: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "bookings_expired_total",
+			Help: "Pending bookings auto-canceled by the expiry job.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.cacheLookups,
+		m.bookingStatuses,
+		m.creditChecks,
+		m.creditCheckDuration,
+		m.expiryRuns,
+		m.expiredBookings,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a completed HTTP request
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// CacheHit records a cache lookup that found its key
+func (m *Metrics) CacheHit() {
+	m.cacheLookups.WithLabelValues("hit").Inc()
+}
+
+// CacheMiss records a cache lookup that did not find its key
+func (m *Metrics) CacheMiss() {
+	m.cacheLookups.WithLabelValues("miss").Inc()
+}
+
+// BookingStatus records a booking entering status
+func (m *Metrics) BookingStatus(status models.BookingStatus) {
+	m.bookingStatuses.WithLabelValues(status.String()).Inc()
+}
+
+// CreditCheckCompleted records the outcome and duration of a credit check
+func (m *Metrics) CreditCheckCompleted(outcome models.CreditCheckOutcome, duration time.Duration) {
+	m.creditChecks.WithLabelValues(string(outcome)).Inc()
+	m.creditCheckDuration.WithLabelValues(string(outcome)).Observe(duration.Seconds())
+}
+
+// ExpiryRun records an expiry run and the bookings it canceled
+func (m *Metrics) ExpiryRun(canceled int) {
+	m.expiryRuns.Inc()
+	m.expiredBookings.Add(float64(canceled))
+}
+
+// TrackGoroutines reports the number of goroutines returned by count as the
+// background work of component, read at every scrape
+func (m *Metrics) TrackGoroutines(component string, count func() int) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "background_goroutines",
+		Help:        "Goroutines running background work, by component.",
+		ConstLabels: prometheus.Labels{"component": component},
+	}, func() float64 {
+		return float64(count())
+	}))
+}
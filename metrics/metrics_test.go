@@ -0,0 +1,59 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/metrics"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics_ExposesRecordedValues(t *testing.T) {
+	// Setup
+	m := metrics.New()
+	workers := 3
+
+	// Execute
+	m.ObserveRequest("GET", "/api/bookings/:id", 200, 20*time.Millisecond)
+	m.ObserveRequest("GET", "/api/bookings/:id", 200, 30*time.Millisecond)
+	m.CacheHit()
+	m.CacheMiss()
+	m.CacheMiss()
+	m.BookingStatus(models.BookingStatusPending)
+	m.BookingStatus(models.BookingStatusConfirmed)
+	m.CreditCheckCompleted(models.CreditCheckApproved, 150*time.Millisecond)
+	m.ExpiryRun(2)
+	m.ExpiryRun(0)
+	m.TrackGoroutines("job_worker", func() int { return workers })
+	body := scrape(t, m)
+
+	// Assert
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/api/bookings/:id",status="200"} 2`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/api/bookings/:id",status="200"} 2`)
+	assert.Contains(t, body, `cache_lookups_total{result="hit"} 1`)
+	assert.Contains(t, body, `cache_lookups_total{result="miss"} 2`)
+	assert.Contains(t, body, `bookings_status_total{status="pending"} 1`)
+	assert.Contains(t, body, `bookings_status_total{status="confirmed"} 1`)
+	assert.Contains(t, body, `credit_checks_total{outcome="approved"} 1`)
+	assert.Contains(t, body, `credit_check_duration_seconds_sum{outcome="approved"} 0.15`)
+	assert.Contains(t, body, `expiry_runs_total 2`)
+	assert.Contains(t, body, `bookings_expired_total 2`)
+	assert.Contains(t, body, `background_goroutines{component="job_worker"} 3`)
+	assert.Contains(t, body, `go_goroutines`)
+}
@@ -1,26 +1,107 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
 )
 
-// Auth middleware for authentication
-func Auth() fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		// This is a mock authentication middleware
-		// In a real application, you would validate tokens, check permissions, etc.
+// PrincipalLocalsKey is the fiber.Ctx locals key holding the authenticated *auth.Principal
+const PrincipalLocalsKey = "principal"
+
+// APIKeyHeader carries the API key of the caller
+const APIKeyHeader = "X-API-Key"
+
+// Authenticator resolves an API key to the caller it belongs to
+type Authenticator interface {
+	Authenticate(ctx context.Context, secret string) (*auth.Principal, error)
+}
 
-		// For demo purposes, we'll just check for a mock API key
-		apiKey := c.Get("X-API-Key")
+// Auth middleware for API key authentication. It validates the X-API-Key
+// header against the key store and attaches the caller to the request: as the
+// PrincipalLocalsKey local for handlers, and on the user context for use cases.
+func Auth(authenticator Authenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey := c.Get(APIKeyHeader)
+		if apiKey == "" {
+			return problem.Write(c, problem.New(fiber.StatusUnauthorized, problem.CodeUnauthorized, "Missing API Key"))
+		}
 
-		// For demo, we'll accept any API key that's at least 10 characters
-		if len(apiKey) < 10 {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid API Key",
-			})
+		principal, err := authenticator.Authenticate(c.UserContext(), apiKey)
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			return problem.Write(c, problem.New(fiber.StatusUnauthorized, problem.CodeUnauthorized, "Invalid API Key"))
+		}
+		if err != nil {
+			return problem.Write(c, problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "Failed to authenticate request"))
 		}
 
 		// Authentication successful
+		attachPrincipal(c, principal)
+		return c.Next()
+	}
+}
+
+// AuthMode selects how requests are authenticated
+type AuthMode string
+
+// AuthMode constants
+const (
+	AuthModeAPIKey AuthMode = "apikey"
+	AuthModeJWT    AuthMode = "jwt"
+	// AuthModeEither accepts a bearer token when one is sent and an API key otherwise
+	AuthModeEither AuthMode = "either"
+)
+
+// IsValid checks if the mode is one of the defined constants
+func (m AuthMode) IsValid() bool {
+	switch m {
+	case AuthModeAPIKey, AuthModeJWT, AuthModeEither:
+		return true
+	default:
+		return false
+	}
+}
+
+// Either authenticates requests carrying an Authorization bearer token with
+// bearer and all other requests with apiKey
+func Either(apiKey, bearer fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := bearerToken(c); ok {
+			return bearer(c)
+		}
+		return apiKey(c)
+	}
+}
+
+// attachPrincipal makes the caller available to handlers through the
+// PrincipalLocalsKey local and to use cases through the user context
+func attachPrincipal(c *fiber.Ctx, principal *auth.Principal) {
+	c.Locals(PrincipalLocalsKey, principal)
+	c.SetUserContext(auth.WithPrincipal(c.UserContext(), principal))
+}
+
+// RequireScope rejects requests whose principal was not granted scope.
+// It must run after Auth.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFrom(c)
+		if principal == nil {
+			return problem.Write(c, problem.New(fiber.StatusUnauthorized, problem.CodeUnauthorized, "Unauthorized"))
+		}
+
+		if !principal.HasScope(scope) {
+			return problem.Write(c, problem.New(fiber.StatusForbidden, problem.CodeForbidden, "API key lacks the "+scope+" scope"))
+		}
+
 		return c.Next()
 	}
 }
+
+// PrincipalFrom returns the principal attached by Auth, or nil for unauthenticated requests
+func PrincipalFrom(c *fiber.Ctx) *auth.Principal {
+	principal, _ := c.Locals(PrincipalLocalsKey).(*auth.Principal)
+	return principal
+}
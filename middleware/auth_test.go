@@ -0,0 +1,126 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var readerPrincipal = &auth.Principal{
+	ID:     "key-1",
+	UserID: 123,
+	Scopes: []string{auth.ScopeBookingsRead},
+	Method: auth.MethodAPIKey,
+}
+
+// newAuthApp serves GET /bookings and GET /admin behind Auth, recording the
+// principal each handler saw in locals and in the user context
+func newAuthApp(authenticator middleware.Authenticator) (*fiber.App, *[2]*auth.Principal) {
+	var seen [2]*auth.Principal
+	app := fiber.New()
+	app.Use(middleware.Auth(authenticator))
+
+	record := func(c *fiber.Ctx) error {
+		seen[0] = middleware.PrincipalFrom(c)
+		seen[1], _ = auth.PrincipalFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	}
+	app.Get("/bookings", middleware.RequireScope(auth.ScopeBookingsRead), record)
+	app.Get("/admin", middleware.RequireScope(auth.ScopeAdmin), record)
+
+	return app, &seen
+}
+
+func getWithKey(app *fiber.App, path, key string) int {
+	req := httptest.NewRequest("GET", path, nil)
+	if key != "" {
+		req.Header.Set(middleware.APIKeyHeader, key)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func TestAuth_AttachesPrincipal(t *testing.T) {
+	// Setup
+	authenticator := mocks.NewAPIKeyUseCase(t)
+	authenticator.On("Authenticate", mock.Anything, "valid-key").Return(readerPrincipal, nil)
+	app, seen := newAuthApp(authenticator)
+
+	// Execute
+	status := getWithKey(app, "/bookings", "valid-key")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, readerPrincipal, seen[0])
+	assert.Equal(t, readerPrincipal, seen[1])
+}
+
+func TestAuth_RejectsRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		err      error
+		expected int
+	}{
+		{"missing key", "", nil, fiber.StatusUnauthorized},
+		{"invalid key", "revoked-key", auth.ErrInvalidCredentials, fiber.StatusUnauthorized},
+		{"store failure", "some-key", errors.New("database is locked"), fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			authenticator := mocks.NewAPIKeyUseCase(t)
+			if tt.key != "" {
+				authenticator.On("Authenticate", mock.Anything, tt.key).Return(nil, tt.err)
+			}
+			app, seen := newAuthApp(authenticator)
+
+			// Execute
+			status := getWithKey(app, "/bookings", tt.key)
+
+			// Assert
+			assert.Equal(t, tt.expected, status)
+			assert.Nil(t, seen[0])
+		})
+	}
+}
+
+func TestRequireScope_ForbidsMissingScope(t *testing.T) {
+	// Setup
+	authenticator := mocks.NewAPIKeyUseCase(t)
+	authenticator.On("Authenticate", mock.Anything, "valid-key").Return(readerPrincipal, nil)
+	app, seen := newAuthApp(authenticator)
+
+	// Execute
+	status := getWithKey(app, "/admin", "valid-key")
+
+	// Assert
+	assert.Equal(t, fiber.StatusForbidden, status)
+	assert.Nil(t, seen[0])
+}
+
+func TestRequireScope_WithoutAuth(t *testing.T) {
+	// Setup
+	app := fiber.New()
+	app.Get("/admin", middleware.RequireScope(auth.ScopeAdmin), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Execute
+	resp, err := app.Test(httptest.NewRequest("GET", "/admin", nil))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// handledErrorKey is the Locals key of an error already answered by handleError
+type handledErrorKey struct{}
+
+// handleError answers err with the app's error handler right away, so the
+// response status is final for the middleware about to read it. Middleware
+// further out see a handled request and get err from requestError.
+func handleError(c *fiber.Ctx, err error) {
+	if err == nil {
+		return
+	}
+
+	c.Locals(handledErrorKey{}, err)
+	if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+		_ = c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
+
+// requestError returns the error the request failed with: err as returned
+// by the next handler, or the one handleError answered further in
+func requestError(c *fiber.Ctx, err error) error {
+	if err != nil {
+		return err
+	}
+	handled, _ := c.Locals(handledErrorKey{}).(error)
+	return handled
+}
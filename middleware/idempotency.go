@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/idempotency"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen key for a retryable request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from the store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// DefaultIdempotencyRetention is how long keys are kept when no retention is configured
+	DefaultIdempotencyRetention = 24 * time.Hour
+	// maxIdempotencyKeyLength bounds the key a client may send
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyConfig configures the Idempotency middleware
+type IdempotencyConfig struct {
+	Store idempotency.Store
+	// Retention is how long a key and its response are kept
+	Retention time.Duration
+}
+
+// Idempotency makes requests carrying an Idempotency-Key safe to retry.
+// The first request with a key runs normally and its response is stored with
+// a fingerprint of the request; a retry with the same key gets the stored
+// response back, while reusing the key for a different request returns 422.
+// Server errors are not stored, so the client can retry them.
+func Idempotency(config IdempotencyConfig) fiber.Handler {
+	if config.Retention <= 0 {
+		config.Retention = DefaultIdempotencyRetention
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return problem.Write(c, problem.New(fiber.StatusBadRequest, problem.CodeValidation, "Idempotency-Key must be at most 255 characters"))
+		}
+
+		// Keys are scoped to the client so two clients cannot collide
+		storeKey := idempotencyScope(c) + ":" + key
+		now := time.Now()
+		record := &idempotency.Record{
+			Key:         storeKey,
+			Fingerprint: requestFingerprint(c),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(config.Retention),
+		}
+
+		ctx := c.UserContext()
+		existing, err := config.Store.Reserve(ctx, record)
+		if err != nil {
+			return problem.Write(c, problem.New(fiber.StatusInternalServerError, problem.CodeInternal, "Failed to check idempotency key"))
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				return problem.Write(c, problem.New(fiber.StatusUnprocessableEntity, problem.CodeIdempotencyKeyReused, "Idempotency-Key was already used for a different request"))
+			case !existing.Completed():
+				return problem.Write(c, problem.New(fiber.StatusConflict, problem.CodeIdempotencyKeyInUse, "A request with this Idempotency-Key is still in progress"))
+			default:
+				c.Set(IdempotentReplayedHeader, "true")
+				if existing.Response.ContentType != "" {
+					c.Set(fiber.HeaderContentType, existing.Response.ContentType)
+				}
+				return c.Status(existing.Response.StatusCode).Send(existing.Response.Body)
+			}
+		}
+
+		release := func() {
+			if releaseErr := config.Store.Release(ctx, storeKey); releaseErr != nil {
+				slog.ErrorContext(ctx, "Failed to release idempotency key", "error", releaseErr)
+			}
+		}
+		// A panicking handler skips the code below; free the key before the
+		// panic reaches the recover middleware so retries are not locked out
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		// Errors are answered here so client errors are stored like any other response
+		handleError(c, c.Next())
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			release()
+			return nil
+		}
+
+		response := &idempotency.Response{
+			StatusCode:  status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        c.Response().Body(),
+		}
+		if err := config.Store.Complete(ctx, storeKey, response); err != nil {
+			// Without a stored response retries would see the key as in flight
+			slog.ErrorContext(ctx, "Failed to store idempotent response", "error", err)
+			release()
+		}
+
+		return nil
+	}
+}
+
+// idempotencyScope identifies the client sending the request: the
+// authenticated principal, or the API key header when Auth has not run
+func idempotencyScope(c *fiber.Ctx) string {
+	if principal := PrincipalFrom(c); principal != nil {
+		return principal.Method + ":" + principal.ID
+	}
+	return fingerprint([]byte(c.Get(APIKeyHeader)))
+}
+
+// requestFingerprint identifies a request by method, path and body. JSON
+// bodies are compacted first so formatting differences do not count.
+func requestFingerprint(c *fiber.Ctx) string {
+	body := c.Body()
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+
+	data := make([]byte, 0, len(c.Method())+len(c.Path())+len(body)+2)
+	data = append(data, c.Method()...)
+	data = append(data, '\n')
+	data = append(data, c.Path()...)
+	data = append(data, '\n')
+	data = append(data, body...)
+
+	return fingerprint(data)
+}
+
+// fingerprint returns the hex-encoded SHA-256 of data
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
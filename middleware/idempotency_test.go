@@ -0,0 +1,205 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/idempotency"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdempotentApp serves POST /bookings behind the middleware; the handler
+// answers with the number of times it has run
+func newIdempotentApp(retention time.Duration, status int) (*fiber.App, *int32) {
+	var calls int32
+	app := fiber.New()
+	app.Post("/bookings", middleware.Idempotency(middleware.IdempotencyConfig{
+		Store:     idempotency.NewMemoryStore(),
+		Retention: retention,
+	}), func(c *fiber.Ctx) error {
+		n := atomic.AddInt32(&calls, 1)
+		return c.Status(status).JSON(fiber.Map{"call": n})
+	})
+	return app, &calls
+}
+
+func postBooking(t *testing.T, app *fiber.App, apiKey, key, body string) (int, string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/bookings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(data), resp.Header.Get(middleware.IdempotentReplayedHeader)
+}
+
+func TestIdempotency_ReplaysOriginalResponse(t *testing.T) {
+	// Setup
+	app, calls := newIdempotentApp(time.Hour, fiber.StatusCreated)
+
+	// Execute - the retry differs only in JSON formatting
+	status, body, replayed := postBooking(t, app, "client-key-1", "abc", `{"user_id":1,"price":100}`)
+	retryStatus, retryBody, retryReplayed := postBooking(t, app, "client-key-1", "abc", `{ "user_id": 1, "price": 100 }`)
+
+	// Assert
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, "", replayed)
+	assert.Equal(t, fiber.StatusCreated, retryStatus)
+	assert.Equal(t, body, retryBody)
+	assert.Equal(t, "true", retryReplayed)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestIdempotency_DifferentPayloadReturns422(t *testing.T) {
+	// Setup
+	app, calls := newIdempotentApp(time.Hour, fiber.StatusCreated)
+
+	// Execute
+	postBooking(t, app, "client-key-1", "abc", `{"price":100}`)
+	status, body, _ := postBooking(t, app, "client-key-1", "abc", `{"price":200}`)
+
+	// Assert
+	assert.Equal(t, fiber.StatusUnprocessableEntity, status)
+	assert.Contains(t, body, "different request")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestIdempotency_KeysAreScopedPerClient(t *testing.T) {
+	// Setup
+	app, calls := newIdempotentApp(time.Hour, fiber.StatusCreated)
+
+	// Execute
+	postBooking(t, app, "client-key-1", "abc", `{"price":100}`)
+	status, _, replayed := postBooking(t, app, "client-key-2", "abc", `{"price":200}`)
+
+	// Assert
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, "", replayed)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestIdempotency_WithoutKeyRunsEveryTime(t *testing.T) {
+	// Setup
+	app, calls := newIdempotentApp(time.Hour, fiber.StatusCreated)
+
+	// Execute
+	postBooking(t, app, "client-key-1", "", `{"price":100}`)
+	postBooking(t, app, "client-key-1", "", `{"price":100}`)
+
+	// Assert
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	// Setup
+	app, calls := newIdempotentApp(time.Hour, fiber.StatusInternalServerError)
+
+	// Execute
+	postBooking(t, app, "client-key-1", "abc", `{"price":100}`)
+	_, _, replayed := postBooking(t, app, "client-key-1", "abc", `{"price":100}`)
+
+	// Assert
+	assert.Equal(t, "", replayed)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	// Setup - the first call panics and is answered by the recover middleware
+	var calls int32
+	app := fiber.New()
+	app.Use(recover.New())
+	app.Post("/bookings", middleware.Idempotency(middleware.IdempotencyConfig{
+		Store: idempotency.NewMemoryStore(),
+	}), func(c *fiber.Ctx) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("handler failed")
+		}
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	// Execute
+	firstStatus, _, _ := postBooking(t, app, "client-key-1", "abc", `{"price":100}`)
+	retryStatus, _, _ := postBooking(t, app, "client-key-1", "abc", `{"price":100}`)
+
+	// Assert
+	assert.Equal(t, fiber.StatusInternalServerError, firstStatus)
+	assert.Equal(t, fiber.StatusCreated, retryStatus)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotency_KeyExpiresAfterRetention(t *testing.T) {
+	// Setup
+	app, calls := newIdempotentApp(20*time.Millisecond, fiber.StatusCreated)
+
+	// Execute
+	postBooking(t, app, "client-key-1", "abc", `{"price":100}`)
+	time.Sleep(30 * time.Millisecond)
+	_, _, replayed := postBooking(t, app, "client-key-1", "abc", `{"price":200}`)
+
+	// Assert
+	assert.Equal(t, "", replayed)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestIdempotency_InFlightRequestReturns409(t *testing.T) {
+	// Setup - the first request blocks until released
+	release := make(chan struct{})
+	started := make(chan struct{})
+	app := fiber.New()
+	app.Post("/bookings", middleware.Idempotency(middleware.IdempotencyConfig{
+		Store: idempotency.NewMemoryStore(),
+	}), func(c *fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	done := make(chan int)
+	go func() {
+		req := httptest.NewRequest("POST", "/bookings", strings.NewReader(`{}`))
+		req.Header.Set(middleware.IdempotencyKeyHeader, "abc")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			done <- 0
+			return
+		}
+		done <- resp.StatusCode
+	}()
+	<-started
+
+	// Execute
+	status, _, _ := postBooking(t, app, "", "abc", `{}`)
+	close(release)
+
+	// Assert
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, fiber.StatusCreated, <-done)
+}
+
+func TestIdempotency_RejectsLongKey(t *testing.T) {
+	// Setup
+	app, calls := newIdempotentApp(time.Hour, fiber.StatusCreated)
+
+	// Execute
+	status, _, _ := postBooking(t, app, "client-key-1", strings.Repeat("k", 256), `{}`)
+
+	// Assert
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, int32(0), atomic.LoadInt32(calls))
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+)
+
+// DefaultJWTLeeway is the clock skew tolerated when checking exp and nbf
+const DefaultJWTLeeway = 30 * time.Second
+
+// JWTConfig configures the JWT middleware
+type JWTConfig struct {
+	// Keys resolves the key that verifies a token's signature
+	Keys auth.KeySet
+	// Issuer, when set, must match the iss claim
+	Issuer string
+	// Audience, when set, must be one of the aud claim values
+	Audience string
+	// Leeway is the clock skew tolerated for exp and nbf; DefaultJWTLeeway when zero
+	Leeway time.Duration
+}
+
+// jwtClaims are the claims read from a bearer token. sub holds the numeric
+// user ID; scope is an optional space-separated list as in OAuth 2.0.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+	Scope string   `json:"scope,omitempty"`
+}
+
+// JWT middleware for bearer token authentication. It accepts HS256, RS256 and
+// ES256 tokens verified against config.Keys, requires exp, and checks nbf,
+// iss and aud. The claims are mapped onto a principal attached like Auth does.
+// Without a scope claim the scopes follow from the roles, see auth.ScopesForRoles.
+func JWT(config JWTConfig) fiber.Handler {
+	if config.Leeway == 0 {
+		config.Leeway = DefaultJWTLeeway
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{auth.AlgHS256, auth.AlgRS256, auth.AlgES256}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	parser := jwt.NewParser(options...)
+
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return config.Keys.VerificationKey(kid, token.Method.Alg())
+	}
+
+	return func(c *fiber.Ctx) error {
+		raw, ok := bearerToken(c)
+		if !ok {
+			return problem.Write(c, problem.New(fiber.StatusUnauthorized, problem.CodeUnauthorized, "Missing bearer token"))
+		}
+
+		claims := new(jwtClaims)
+		if _, err := parser.ParseWithClaims(raw, claims, keyFunc); err != nil {
+			return problem.Write(c, problem.New(fiber.StatusUnauthorized, problem.CodeUnauthorized, invalidTokenMessage(err)))
+		}
+
+		principal, ok := claims.principal()
+		if !ok {
+			return problem.Write(c, problem.New(fiber.StatusUnauthorized, problem.CodeUnauthorized, "Token subject must be a user ID"))
+		}
+
+		attachPrincipal(c, principal)
+		return c.Next()
+	}
+}
+
+// principal maps the claims onto the caller of the request; it fails when
+// the subject is not a user ID
+func (claims *jwtClaims) principal() (*auth.Principal, bool) {
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, false
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	if len(scopes) == 0 {
+		scopes = auth.ScopesForRoles(claims.Roles)
+	}
+
+	return &auth.Principal{
+		ID:     claims.Subject,
+		UserID: userID,
+		Roles:  claims.Roles,
+		Scopes: scopes,
+		Method: auth.MethodJWT,
+	}, true
+}
+
+// invalidTokenMessage describes why a token was rejected without echoing its contents
+func invalidTokenMessage(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "Token has expired"
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return "Token is not valid yet"
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return "Token issuer is not accepted"
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return "Token audience is not accepted"
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return "Token is missing a required claim"
+	default:
+		return "Invalid token"
+	}
+}
+
+// bearerToken returns the token from an "Authorization: Bearer <token>" header
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
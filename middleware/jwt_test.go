@@ -0,0 +1,225 @@
+package middleware_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var hmacSecret = []byte("test-hmac-secret")
+
+// validClaims returns claims accepted by newJWTApp
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub":   "123",
+		"iss":   "https://issuer.example",
+		"aud":   "booking-api",
+		"exp":   now.Add(time.Hour).Unix(),
+		"nbf":   now.Add(-time.Minute).Unix(),
+		"roles": []string{"customer"},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+// newJWTApp serves GET /bookings behind the JWT middleware and records the principal
+func newJWTApp(keys auth.KeySet) (*fiber.App, **auth.Principal) {
+	var seen *auth.Principal
+	app := fiber.New()
+	app.Use(middleware.JWT(middleware.JWTConfig{
+		Keys:     keys,
+		Issuer:   "https://issuer.example",
+		Audience: "booking-api",
+		Leeway:   time.Second,
+	}))
+	app.Get("/bookings", func(c *fiber.Ctx) error {
+		seen, _ = auth.PrincipalFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app, &seen
+}
+
+func getWithBearer(t *testing.T, app *fiber.App, token string) int {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/bookings", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestJWT_AcceptsSupportedAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keys := auth.StaticKeySet{
+		auth.HMACKey("hmac", hmacSecret),
+		{ID: "rsa", Algorithm: auth.AlgRS256, Key: &rsaKey.PublicKey},
+		{ID: "ec", Algorithm: auth.AlgES256, Key: &ecKey.PublicKey},
+	}
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    interface{}
+	}{
+		{"HS256", jwt.SigningMethodHS256, hmacSecret},
+		{"RS256", jwt.SigningMethodRS256, rsaKey},
+		{"ES256", jwt.SigningMethodES256, ecKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			app, seen := newJWTApp(keys)
+
+			// Execute
+			status := getWithBearer(t, app, sign(t, tt.method, tt.key, validClaims()))
+
+			// Assert
+			assert.Equal(t, fiber.StatusOK, status)
+			require.NotNil(t, *seen)
+			assert.Equal(t, int64(123), (*seen).UserID)
+			assert.Equal(t, auth.MethodJWT, (*seen).Method)
+			assert.Equal(t, []string{"customer"}, (*seen).Roles)
+			assert.Equal(t, []string{auth.ScopeBookingsRead, auth.ScopeBookingsWrite}, (*seen).Scopes)
+		})
+	}
+}
+
+func TestJWT_RejectsInvalidTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing token", ""},
+		{"malformed", "not.a.token"},
+		{"expired", sign(t, jwt.SigningMethodHS256, hmacSecret, with("exp", time.Now().Add(-time.Minute).Unix()))},
+		{"missing exp", sign(t, jwt.SigningMethodHS256, hmacSecret, with("exp", nil))},
+		{"not yet valid", sign(t, jwt.SigningMethodHS256, hmacSecret, with("nbf", time.Now().Add(time.Minute).Unix()))},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, hmacSecret, with("iss", "https://evil.example"))},
+		{"wrong audience", sign(t, jwt.SigningMethodHS256, hmacSecret, with("aud", "other-api"))},
+		{"wrong secret", sign(t, jwt.SigningMethodHS256, []byte("other-secret"), validClaims())},
+		{"unknown RSA key", sign(t, jwt.SigningMethodRS256, otherKey, validClaims())},
+		{"unsupported algorithm", sign(t, jwt.SigningMethodHS512, hmacSecret, validClaims())},
+		{"unsigned", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, validClaims())},
+		{"non-numeric subject", sign(t, jwt.SigningMethodHS256, hmacSecret, with("sub", "alice"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			app, seen := newJWTApp(auth.StaticKeySet{auth.HMACKey("", hmacSecret)})
+
+			// Execute
+			status := getWithBearer(t, app, tt.token)
+
+			// Assert
+			assert.Equal(t, fiber.StatusUnauthorized, status)
+			assert.Nil(t, *seen)
+		})
+	}
+}
+
+func TestJWT_ScopesFromClaims(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   map[string]interface{}
+		expected []string
+	}{
+		{"admin role", map[string]interface{}{"roles": []string{"admin"}},
+			[]string{auth.ScopeBookingsRead, auth.ScopeBookingsWrite, auth.ScopeAdmin}},
+		{"explicit scope claim", map[string]interface{}{"scope": "bookings:read"},
+			[]string{auth.ScopeBookingsRead}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			app, seen := newJWTApp(auth.StaticKeySet{auth.HMACKey("", hmacSecret)})
+			claims := validClaims()
+			for key, value := range tt.claims {
+				claims[key] = value
+			}
+
+			// Execute
+			status := getWithBearer(t, app, sign(t, jwt.SigningMethodHS256, hmacSecret, claims))
+
+			// Assert
+			assert.Equal(t, fiber.StatusOK, status)
+			require.NotNil(t, *seen)
+			assert.Equal(t, tt.expected, (*seen).Scopes)
+		})
+	}
+}
+
+func TestEither_DispatchesOnCredential(t *testing.T) {
+	// Setup
+	authenticator := mocks.NewAPIKeyUseCase(t)
+	authenticator.On("Authenticate", mock.Anything, "valid-key").Return(readerPrincipal, nil)
+
+	var seen *auth.Principal
+	app := fiber.New()
+	app.Use(middleware.Either(
+		middleware.Auth(authenticator),
+		middleware.JWT(middleware.JWTConfig{Keys: auth.StaticKeySet{auth.HMACKey("", hmacSecret)}}),
+	))
+	app.Get("/bookings", func(c *fiber.Ctx) error {
+		seen = middleware.PrincipalFrom(c)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Execute - an API key
+	keyStatus := getWithKey(app, "/bookings", "valid-key")
+	keyPrincipal := seen
+
+	// Execute - a bearer token
+	tokenStatus := getWithBearer(t, app, sign(t, jwt.SigningMethodHS256, hmacSecret, validClaims()))
+	tokenPrincipal := seen
+
+	// Execute - neither
+	noneStatus := getWithKey(app, "/bookings", "")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, keyStatus)
+	assert.Equal(t, auth.MethodAPIKey, keyPrincipal.Method)
+	assert.Equal(t, fiber.StatusOK, tokenStatus)
+	assert.Equal(t, auth.MethodJWT, tokenPrincipal.Method)
+	assert.Equal(t, fiber.StatusUnauthorized, noneStatus)
+}
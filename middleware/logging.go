@@ -1,36 +1,52 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// Logging middleware for request logging
+// Logging logs every request once it completes. Server errors are logged as
+// errors and client errors as warnings; the request ID comes from the context.
 func Logging() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Start timer
 		start := time.Now()
 
-		// Store the request path
+		// Store the request path before handlers can change it
+		method := c.Method()
 		path := c.Path()
 
-		// Process request
 		err := c.Next()
-
-		// Calculate response time
-		responseTime := time.Since(start)
-
-		// Log request details
-		log.Printf(
-			"[%s] %s - Status: %d - Response time: %v",
-			c.Method(),
-			path,
-			c.Response().StatusCode(),
-			responseTime,
-		)
-
-		return err
+		failure := requestError(c, err)
+		handleError(c, err)
+
+		status := c.Response().StatusCode()
+
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("ip", c.IP()),
+		}
+		if principal := PrincipalFrom(c); principal != nil {
+			attrs = append(attrs, slog.String("principal", principal.Method+":"+principal.ID))
+		}
+		if failure != nil {
+			attrs = append(attrs, slog.String("error", failure.Error()))
+		}
+
+		slog.LogAttrs(c.UserContext(), level, "Request completed", attrs...)
+
+		return nil
 	}
 }
@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogging_LogsRequestWithRequestID(t *testing.T) {
+	// Setup - capture the default logger
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, logging.Config{}))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	app := fiber.New()
+	app.Use(middleware.RequestID(), middleware.Logging())
+	app.Get("/bookings/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNotFound)
+	})
+
+	// Execute
+	req := httptest.NewRequest("GET", "/bookings/7", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-7")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	// Assert - client errors are warnings
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "Request completed", entry["msg"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/bookings/7", entry["path"])
+	assert.Equal(t, float64(404), entry["status"])
+	assert.Equal(t, "req-7", entry[logging.RequestIDKey])
+}
+
+func TestLogging_StatusAndErrorFromErrorHandler(t *testing.T) {
+	// Setup - the error handler maps a domain error; Metrics runs inside Logging
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, logging.Config{}))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	errUnavailable := errors.New("store unavailable")
+	observer := &recordingObserver{}
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		if errors.Is(err, errUnavailable) {
+			return problem.Write(c, problem.New(fiber.StatusServiceUnavailable, problem.CodeUnavailable, "retry later"))
+		}
+		return fiber.DefaultErrorHandler(c, err)
+	}})
+	app.Use(middleware.Logging(), middleware.Metrics(observer))
+	app.Get("/bookings", func(c *fiber.Ctx) error {
+		return errUnavailable
+	})
+
+	// Execute
+	resp, err := app.Test(httptest.NewRequest("GET", "/bookings", nil))
+	require.NoError(t, err)
+
+	// Assert - both see the mapped status and the error is still logged
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, []observedRequest{{"GET", "/bookings", fiber.StatusServiceUnavailable}}, observer.requests)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, float64(503), entry["status"])
+	assert.Equal(t, "store unavailable", entry["error"])
+}
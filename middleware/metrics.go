@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// RequestObserver records completed HTTP requests
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// Metrics reports every request to observer. Requests are labelled with the
+// matched route pattern, e.g. /api/bookings/:id, to keep the number of
+// series bounded.
+func Metrics(observer RequestObserver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		// Fiber reuses the request buffers; the observer may keep the label
+		method := utils.CopyString(c.Method())
+
+		handleError(c, c.Next())
+
+		observer.ObserveRequest(method, c.Route().Path, c.Response().StatusCode(), time.Since(start))
+
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// observedRequest is a request reported to recordingObserver
+type observedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+type recordingObserver struct {
+	mu       sync.Mutex
+	requests []observedRequest
+}
+
+func (o *recordingObserver) ObserveRequest(method, route string, status int, duration time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.requests = append(o.requests, observedRequest{method, route, status})
+}
+
+func TestMetrics_ObservesRoutePatternAndStatus(t *testing.T) {
+	// Setup
+	observer := &recordingObserver{}
+	app := fiber.New()
+	app.Use(middleware.Metrics(observer))
+	app.Get("/bookings/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Delete("/bookings/:id", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusConflict, "conflict")
+	})
+
+	// Execute
+	_, err := app.Test(httptest.NewRequest("GET", "/bookings/1", nil))
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest("DELETE", "/bookings/2", nil))
+	require.NoError(t, err)
+
+	// Assert - the path parameter does not become a label value
+	assert.Equal(t, []observedRequest{
+		{"GET", "/bookings/:id", fiber.StatusOK},
+		{"DELETE", "/bookings/:id", fiber.StatusConflict},
+	}, observer.requests)
+}
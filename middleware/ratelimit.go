@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/ratelimit"
+)
+
+// Rate limit response headers
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// RateLimitConfig configures the RateLimit middleware
+type RateLimitConfig struct {
+	Store ratelimit.Store
+	// Name keeps the buckets of route groups sharing a store apart
+	Name  string
+	Limit ratelimit.Limit
+	// KeyFunc picks the bucket for a request; RateLimitByClient when nil
+	KeyFunc func(c *fiber.Ctx) string
+}
+
+// RateLimit limits how often a client may call the routes behind it. Every
+// response carries the RateLimit-* headers; a client without tokens left gets
+// 429 with Retry-After. Requests are let through when the store fails, so an
+// outage of a shared store does not take the API down with it.
+func RateLimit(config RateLimitConfig) fiber.Handler {
+	if !config.Limit.Enabled() {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = RateLimitByClient
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := config.Name + ":" + config.KeyFunc(c)
+		result, err := config.Store.Take(c.UserContext(), key, config.Limit)
+		if err != nil {
+			slog.ErrorContext(c.UserContext(), "Rate limit store failed, allowing request", "error", err)
+			return c.Next()
+		}
+
+		c.Set(RateLimitLimitHeader, strconv.Itoa(config.Limit.Capacity()))
+		c.Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		c.Set(RateLimitResetHeader, strconv.Itoa(seconds(result.Reset)))
+
+		if !result.Allowed {
+			retryAfter := seconds(result.RetryAfter)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return problem.Write(c, problem.New(fiber.StatusTooManyRequests, problem.CodeRateLimited, "Too many requests, retry in "+strconv.Itoa(retryAfter)+" seconds"))
+		}
+
+		return c.Next()
+	}
+}
+
+// RateLimitByClient buckets requests by the authenticated principal, the API
+// key header when Auth has not run, and the client IP otherwise
+func RateLimitByClient(c *fiber.Ctx) string {
+	if principal := PrincipalFrom(c); principal != nil {
+		return "principal:" + principal.Method + ":" + principal.ID
+	}
+	if apiKey := c.Get(APIKeyHeader); apiKey != "" {
+		return "apikey:" + fingerprint([]byte(apiKey))
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByIP buckets requests by client IP
+func RateLimitByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// seconds rounds d up to whole seconds for the rate limit headers
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
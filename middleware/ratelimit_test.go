@@ -0,0 +1,145 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingStore is a rate limit store that is always down
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func newRateLimitedApp(config middleware.RateLimitConfig) *fiber.App {
+	app := fiber.New()
+	app.Get("/bookings", middleware.RateLimit(config), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestRateLimit_DeniesWhenBucketIsEmpty(t *testing.T) {
+	// Setup
+	app := newRateLimitedApp(middleware.RateLimitConfig{
+		Store: ratelimit.NewMemoryStore(),
+		Name:  "reads",
+		Limit: ratelimit.Limit{Requests: 2, Per: time.Minute},
+	})
+
+	// Execute
+	first, err := app.Test(httptest.NewRequest("GET", "/bookings", nil))
+	require.NoError(t, err)
+	second, err := app.Test(httptest.NewRequest("GET", "/bookings", nil))
+	require.NoError(t, err)
+	denied, err := app.Test(httptest.NewRequest("GET", "/bookings", nil))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, first.StatusCode)
+	assert.Equal(t, "2", first.Header.Get(middleware.RateLimitLimitHeader))
+	assert.Equal(t, "1", first.Header.Get(middleware.RateLimitRemainingHeader))
+	assert.Equal(t, "30", first.Header.Get(middleware.RateLimitResetHeader))
+	assert.Empty(t, first.Header.Get(fiber.HeaderRetryAfter))
+
+	assert.Equal(t, fiber.StatusOK, second.StatusCode)
+	assert.Equal(t, "0", second.Header.Get(middleware.RateLimitRemainingHeader))
+
+	assert.Equal(t, fiber.StatusTooManyRequests, denied.StatusCode)
+	assert.Equal(t, "0", denied.Header.Get(middleware.RateLimitRemainingHeader))
+	assert.Equal(t, "30", denied.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func TestRateLimit_SeparatesClients(t *testing.T) {
+	// Setup - one request per key
+	app := newRateLimitedApp(middleware.RateLimitConfig{
+		Store: ratelimit.NewMemoryStore(),
+		Limit: ratelimit.Limit{Requests: 1, Per: time.Hour},
+	})
+
+	// Execute
+	first := getWithKey(app, "/bookings", "key-a")
+	other := getWithKey(app, "/bookings", "key-b")
+	again := getWithKey(app, "/bookings", "key-a")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, first)
+	assert.Equal(t, fiber.StatusOK, other)
+	assert.Equal(t, fiber.StatusTooManyRequests, again)
+}
+
+func TestRateLimit_GroupsHaveSeparateBuckets(t *testing.T) {
+	// Setup - two groups sharing one store
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Hour}
+	app := fiber.New()
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/reads", middleware.RateLimit(middleware.RateLimitConfig{Store: store, Name: "reads", Limit: limit}), ok)
+	app.Get("/writes", middleware.RateLimit(middleware.RateLimitConfig{Store: store, Name: "writes", Limit: limit}), ok)
+
+	// Execute
+	reads := getWithKey(app, "/reads", "key-a")
+	writes := getWithKey(app, "/writes", "key-a")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, reads)
+	assert.Equal(t, fiber.StatusOK, writes)
+}
+
+func TestRateLimit_KeysByPrincipal(t *testing.T) {
+	// Setup - the principal is what counts, not the header it came from
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.PrincipalLocalsKey, readerPrincipal)
+		return c.Next()
+	})
+	app.Get("/bookings", middleware.RateLimit(middleware.RateLimitConfig{
+		Store: ratelimit.NewMemoryStore(),
+		Limit: ratelimit.Limit{Requests: 1, Per: time.Hour},
+	}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Execute
+	first := getWithKey(app, "/bookings", "key-a")
+	second := getWithKey(app, "/bookings", "key-b")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, first)
+	assert.Equal(t, fiber.StatusTooManyRequests, second)
+}
+
+func TestRateLimit_DisabledAndFailingStore(t *testing.T) {
+	tests := []struct {
+		name   string
+		config middleware.RateLimitConfig
+	}{
+		{"disabled", middleware.RateLimitConfig{}},
+		{"store down", middleware.RateLimitConfig{Store: failingStore{}, Limit: ratelimit.Limit{Requests: 1, Per: time.Hour}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			app := newRateLimitedApp(tt.config)
+
+			// Execute
+			first := getWithKey(app, "/bookings", "key-a")
+			second := getWithKey(app, "/bookings", "key-a")
+
+			// Assert - requests are let through
+			assert.Equal(t, fiber.StatusOK, first)
+			assert.Equal(t, fiber.StatusOK, second)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+)
+
+const (
+	// RequestIDHeader carries the request ID in both directions
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDLocalsKey is the fiber.Ctx locals key holding the request ID
+	RequestIDLocalsKey = "request_id"
+	// maxRequestIDLength bounds the request ID a client may send
+	maxRequestIDLength = 128
+)
+
+// RequestID gives every request an ID: the X-Request-ID sent by the client
+// when it is well-formed, a random one otherwise. The ID is echoed in the
+// response and carried by the request context, so every log record written
+// for the request includes it.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDHeader, requestID)
+		c.Locals(RequestIDLocalsKey, requestID)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), requestID))
+
+		return c.Next()
+	}
+}
+
+// RequestIDFrom returns the ID assigned by RequestID, or "" when it has not run
+func RequestIDFrom(c *fiber.Ctx) string {
+	requestID, _ := c.Locals(RequestIDLocalsKey).(string)
+	return requestID
+}
+
+// validRequestID accepts IDs made of letters, digits and -_.: so a client
+// cannot inject arbitrary text into logs and headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random bytes, hex-encoded
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
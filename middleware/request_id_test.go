@@ -0,0 +1,84 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRequestIDApp answers with the request ID carried by the user context
+func newRequestIDApp() *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(logging.RequestIDFromContext(c.UserContext()))
+	})
+	return app
+}
+
+func requestWithID(t *testing.T, app *fiber.App, requestID string) (string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", "/", nil)
+	if requestID != "" {
+		req.Header.Set(middleware.RequestIDHeader, requestID)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.Header.Get(middleware.RequestIDHeader), string(body)
+}
+
+func TestRequestID_PropagatesClientID(t *testing.T) {
+	// Execute
+	header, fromContext := requestWithID(t, newRequestIDApp(), "client-id-123")
+
+	// Assert
+	assert.Equal(t, "client-id-123", header)
+	assert.Equal(t, "client-id-123", fromContext)
+}
+
+func TestRequestID_GeneratesID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+	}{
+		{"missing", ""},
+		{"invalid characters", "id with spaces\n"},
+		{"too long", strings.Repeat("a", 129)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			header, fromContext := requestWithID(t, newRequestIDApp(), tt.requestID)
+
+			// Assert
+			assert.Len(t, header, 32)
+			assert.NotEqual(t, tt.requestID, header)
+			assert.Equal(t, header, fromContext)
+		})
+	}
+}
+
+func TestRequestID_IsUniquePerRequest(t *testing.T) {
+	// Setup
+	app := newRequestIDApp()
+
+	// Execute
+	first, _ := requestWithID(t, app, "")
+	second, _ := requestWithID(t, app, "")
+
+	// Assert
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider that keeps the spans ended during the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	tracing.Install(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func newTracedApp(handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	app.Get("/bookings/:id", handler)
+	return app
+}
+
+// attributeValue returns the value of the span attribute named key
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing_ContinuesIncomingTrace(t *testing.T) {
+	// Setup
+	recorder := recordSpans(t)
+	var handlerSpan trace.SpanContext
+	app := newTracedApp(func(c *fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+	req := httptest.NewRequest("GET", "/bookings/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// Execute
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /bookings/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, "/bookings/:id", attributeValue(span, "http.route").AsString())
+	assert.Equal(t, int64(fiber.StatusOK), attributeValue(span, "http.response.status_code").AsInt64())
+	assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+}
+
+func TestTracing_StartsNewTraceAndMarksServerErrors(t *testing.T) {
+	// Setup
+	recorder := recordSpans(t)
+	app := newTracedApp(func(c *fiber.Ctx) error {
+		return fiber.ErrServiceUnavailable
+	})
+
+	// Execute
+	resp, err := app.Test(httptest.NewRequest("GET", "/bookings/7", nil))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.False(t, spans[0].Parent().IsValid())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, int64(fiber.StatusServiceUnavailable), attributeValue(spans[0], "http.response.status_code").AsInt64())
+}
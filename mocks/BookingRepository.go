@@ -7,6 +7,8 @@ import (
 
 	models "github.com/hydr0g3nz/spd-fiber-booking-system/models"
 	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/hydr0g3nz/spd-fiber-booking-system/repository"
 )
 
 // BookingRepository is an autogenerated mock type for the BookingRepository type
@@ -104,6 +106,36 @@ func (_m *BookingRepository) GetByID(ctx context.Context, id int64) (*models.Boo
 	return r0, r1
 }
 
+// Query provides a mock function with given fields: ctx, query
+func (_m *BookingRepository) Query(ctx context.Context, query repository.BookingQuery) (*repository.BookingPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Query")
+	}
+
+	var r0 *repository.BookingPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.BookingQuery) (*repository.BookingPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.BookingQuery) *repository.BookingPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.BookingPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.BookingQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, booking
 func (_m *BookingRepository) Update(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
 	ret := _m.Called(ctx, booking)
@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/hydr0g3nz/spd-fiber-booking-system/models"
+
+	time "time"
 )
 
 // BookingUseCase is an autogenerated mock type for the BookingUseCase type
@@ -16,9 +18,9 @@ type BookingUseCase struct {
 	mock.Mock
 }
 
-// CancelBooking provides a mock function with given fields: ctx, id
-func (_m *BookingUseCase) CancelBooking(ctx context.Context, id int64) (*models.Booking, error) {
-	ret := _m.Called(ctx, id)
+// CancelBooking provides a mock function with given fields: ctx, id, expectedVersion
+func (_m *BookingUseCase) CancelBooking(ctx context.Context, id int64, expectedVersion int64) (*models.Booking, error) {
+	ret := _m.Called(ctx, id, expectedVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CancelBooking")
@@ -26,19 +28,19 @@ func (_m *BookingUseCase) CancelBooking(ctx context.Context, id int64) (*models.
 
 	var r0 *models.Booking
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) (*models.Booking, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*models.Booking, error)); ok {
+		return rf(ctx, id, expectedVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Booking); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *models.Booking); ok {
+		r0 = rf(ctx, id, expectedVersion)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Booking)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, expectedVersion)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -76,24 +78,52 @@ func (_m *BookingUseCase) CreateBooking(ctx context.Context, req *dto.CreateBook
 	return r0, r1
 }
 
+// ExpireBookings provides a mock function with given fields: ctx
+func (_m *BookingUseCase) ExpireBookings(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireBookings")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllBookings provides a mock function with given fields: ctx, params
-func (_m *BookingUseCase) GetAllBookings(ctx context.Context, params *dto.BookingsQueryParams) ([]*models.Booking, error) {
+func (_m *BookingUseCase) GetAllBookings(ctx context.Context, params *dto.BookingsQueryParams) (*dto.BookingListResponse, error) {
 	ret := _m.Called(ctx, params)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllBookings")
 	}
 
-	var r0 []*models.Booking
+	var r0 *dto.BookingListResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *dto.BookingsQueryParams) ([]*models.Booking, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.BookingsQueryParams) (*dto.BookingListResponse, error)); ok {
 		return rf(ctx, params)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *dto.BookingsQueryParams) []*models.Booking); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.BookingsQueryParams) *dto.BookingListResponse); ok {
 		r0 = rf(ctx, params)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Booking)
+			r0 = ret.Get(0).(*dto.BookingListResponse)
 		}
 	}
 
@@ -136,6 +166,42 @@ func (_m *BookingUseCase) GetBookingByID(ctx context.Context, id int64) (*models
 	return r0, r1
 }
 
+// LastExpiryRun provides a mock function with no fields
+func (_m *BookingUseCase) LastExpiryRun() time.Time {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastExpiryRun")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// QueueExpiryCheck provides a mock function with given fields: ctx
+func (_m *BookingUseCase) QueueExpiryCheck(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueExpiryCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewBookingUseCase creates a new instance of BookingUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBookingUseCase(t interface {
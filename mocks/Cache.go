@@ -2,40 +2,43 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	utils "github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Cache is an autogenerated mock type for the Cache type
-type Cache struct {
+type Cache[K comparable, V interface{}] struct {
 	mock.Mock
 }
 
 // Delete provides a mock function with given fields: key
-func (_m *Cache) Delete(key string) {
+func (_m *Cache[K, V]) Delete(key K) {
 	_m.Called(key)
 }
 
 // Get provides a mock function with given fields: key
-func (_m *Cache) Get(key string) (interface{}, bool) {
+func (_m *Cache[K, V]) Get(key K) (V, bool) {
 	ret := _m.Called(key)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
 	}
 
-	var r0 interface{}
+	var r0 V
 	var r1 bool
-	if rf, ok := ret.Get(0).(func(string) (interface{}, bool)); ok {
+	if rf, ok := ret.Get(0).(func(K) (V, bool)); ok {
 		return rf(key)
 	}
-	if rf, ok := ret.Get(0).(func(string) interface{}); ok {
+	if rf, ok := ret.Get(0).(func(K) V); ok {
 		r0 = rf(key)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(interface{})
+			r0 = ret.Get(0).(V)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) bool); ok {
+	if rf, ok := ret.Get(1).(func(K) bool); ok {
 		r1 = rf(key)
 	} else {
 		r1 = ret.Get(1).(bool)
@@ -45,19 +48,19 @@ func (_m *Cache) Get(key string) (interface{}, bool) {
 }
 
 // GetAll provides a mock function with no fields
-func (_m *Cache) GetAll() map[string]interface{} {
+func (_m *Cache[K, V]) GetAll() map[K]V {
 	ret := _m.Called()
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
 	}
 
-	var r0 map[string]interface{}
-	if rf, ok := ret.Get(0).(func() map[string]interface{}); ok {
+	var r0 map[K]V
+	if rf, ok := ret.Get(0).(func() map[K]V); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]interface{})
+			r0 = ret.Get(0).(map[K]V)
 		}
 	}
 
@@ -65,17 +68,35 @@ func (_m *Cache) GetAll() map[string]interface{} {
 }
 
 // Set provides a mock function with given fields: key, value
-func (_m *Cache) Set(key string, value interface{}) {
+func (_m *Cache[K, V]) Set(key K, value V) {
 	_m.Called(key, value)
 }
 
+// Stats provides a mock function with no fields
+func (_m *Cache[K, V]) Stats() utils.CacheStats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 utils.CacheStats
+	if rf, ok := ret.Get(0).(func() utils.CacheStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(utils.CacheStats)
+	}
+
+	return r0
+}
+
 // NewCache creates a new instance of Cache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewCache(t interface {
+func NewCache[K comparable, V interface{}](t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *Cache {
-	mock := &Cache{}
+}) *Cache[K, V] {
+	mock := &Cache[K, V]{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// APIKey is an issued API key. Only a hash of the secret is stored; the
+// secret itself is shown once, when the key is issued.
+// @Description API key metadata; the secret is never returned after issue
+type APIKey struct {
+	ID   string `json:"id" example:"3f2a9c1e7b6d4a58" description:"Unique identifier of the key"`
+	Name string `json:"name" example:"mobile app" description:"Human-readable label"`
+	// Prefix is the start of the secret, shown so a key can be recognised
+	Prefix    string     `json:"prefix" example:"bk_Xy3k9QaZ" description:"First characters of the secret"`
+	Hash      string     `json:"-"`
+	OwnerID   int64      `json:"owner_id" example:"123" description:"User the key acts for; 0 for service keys"`
+	Roles     []string   `json:"roles" example:"customer" description:"Roles deciding which bookings the key may access"`
+	Scopes    []string   `json:"scopes" example:"bookings:read,bookings:write" description:"Granted scopes"`
+	CreatedAt time.Time  `json:"created_at" description:"Time the key was issued"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" description:"Time after which the key is no longer accepted"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" description:"Time the key was revoked"`
+}
+
+// Active reports whether the key may be used at now
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// Clone returns a deep copy of the key
+func (k *APIKey) Clone() *APIKey {
+	clone := *k
+	clone.Roles = append([]string(nil), k.Roles...)
+	clone.Scopes = append([]string(nil), k.Scopes...)
+	if k.ExpiresAt != nil {
+		expiresAt := *k.ExpiresAt
+		clone.ExpiresAt = &expiresAt
+	}
+	if k.RevokedAt != nil {
+		revokedAt := *k.RevokedAt
+		clone.RevokedAt = &revokedAt
+	}
+	return &clone
+}
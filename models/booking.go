@@ -14,6 +14,20 @@ type Booking struct {
 	Status    BookingStatus `json:"status"  example:"pending" description:"Booking status"`
 	CreatedAt time.Time     `json:"created_at" format:"date-time" example:"2024-03-11T12:00:00Z" description:"Creation timestamp"`
 	UpdatedAt time.Time     `json:"updated_at" format:"date-time" example:"2024-03-11T12:00:00Z" description:"Last update timestamp"`
+	Version   int64         `json:"version" example:"1" description:"Version number, incremented on every update"`
+	ExpiresAt time.Time     `json:"expires_at" format:"date-time" example:"2024-03-11T12:05:00Z" description:"Time after which a pending booking is canceled"`
+	// CreditCheck is set once the credit check of a high-value booking has run
+	CreditCheck *CreditCheck `json:"credit_check,omitempty" description:"Credit check result for high-value bookings"`
+}
+
+// Clone returns a deep copy of the booking, or nil for a nil receiver
+func (b *Booking) Clone() *Booking {
+	if b == nil {
+		return nil
+	}
+	clone := *b
+	clone.CreditCheck = b.CreditCheck.Clone()
+	return &clone
 }
 
 // BookingStatus represents the status of a booking as a string type
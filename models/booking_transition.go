@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TransitionActor identifies who triggers a booking status change
+type TransitionActor string
+
+// TransitionActor constants
+const (
+	ActorCustomer    TransitionActor = "customer"
+	ActorOperator    TransitionActor = "operator"
+	ActorAdmin       TransitionActor = "admin"
+	ActorCreditCheck TransitionActor = "credit_check"
+	ActorExpiry      TransitionActor = "expiry"
+)
+
+// ErrInvalidTransition is returned when a booking status change is not allowed
+var ErrInvalidTransition = errors.New("invalid booking status transition")
+
+// bookingTransitions is the booking state machine: for every status it lists the
+// statuses a booking may move to and the actors allowed to trigger each move.
+// Statuses without an entry are terminal.
+var bookingTransitions = map[BookingStatus]map[BookingStatus][]TransitionActor{
+	BookingStatusPending: {
+		BookingStatusConfirmed: {ActorCreditCheck},
+		BookingStatusRejected:  {ActorCreditCheck},
+		BookingStatusCanceled:  {ActorCustomer, ActorOperator, ActorAdmin, ActorExpiry},
+	},
+	// Only admins may force-cancel a booking that already passed its credit check
+	BookingStatusConfirmed: {
+		BookingStatusCanceled: {ActorAdmin},
+	},
+}
+
+// transitionVerbs names the action that leads to each status, for error messages
+var transitionVerbs = map[BookingStatus]string{
+	BookingStatusPending:   "reopen",
+	BookingStatusConfirmed: "confirm",
+	BookingStatusRejected:  "reject",
+	BookingStatusCanceled:  "cancel",
+}
+
+// TransitionError describes a rejected booking status change.
+// It matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	From  BookingStatus
+	To    BookingStatus
+	Actor TransitionActor
+	// Forbidden is true when the move is legal but not for this actor
+	Forbidden bool
+}
+
+// Error implements the error interface
+func (e *TransitionError) Error() string {
+	if e.Forbidden {
+		return fmt.Sprintf("%s cannot %s a %s booking", e.Actor, transitionVerbs[e.To], e.From)
+	}
+	return fmt.Sprintf("cannot %s a %s booking", transitionVerbs[e.To], e.From)
+}
+
+// Is reports whether target is ErrInvalidTransition
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// ValidateTransition checks a status change against the state machine
+func ValidateTransition(from, to BookingStatus, actor TransitionActor) error {
+	actors, legal := bookingTransitions[from][to]
+	if !legal {
+		return &TransitionError{From: from, To: to, Actor: actor}
+	}
+
+	for _, allowed := range actors {
+		if allowed == actor {
+			return nil
+		}
+	}
+
+	return &TransitionError{From: from, To: to, Actor: actor, Forbidden: true}
+}
+
+// CanTransition reports whether actor may move a booking from s to the given status
+func (s BookingStatus) CanTransition(to BookingStatus, actor TransitionActor) bool {
+	return ValidateTransition(s, to, actor) == nil
+}
+
+// IsTerminal reports whether no further status changes are possible
+func (s BookingStatus) IsTerminal() bool {
+	return len(bookingTransitions[s]) == 0
+}
+
+// TransitionTo moves the booking to a new status on behalf of actor and stamps UpdatedAt.
+// The booking is left untouched when the transition is not allowed.
+func (b *Booking) TransitionTo(to BookingStatus, actor TransitionActor, at time.Time) error {
+	if err := ValidateTransition(b.Status, to, actor); err != nil {
+		return err
+	}
+
+	b.Status = to
+	b.UpdatedAt = at
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      models.BookingStatus
+		to        models.BookingStatus
+		actor     models.TransitionActor
+		allowed   bool
+		forbidden bool
+	}{
+		{"credit check confirms pending", models.BookingStatusPending, models.BookingStatusConfirmed, models.ActorCreditCheck, true, false},
+		{"credit check rejects pending", models.BookingStatusPending, models.BookingStatusRejected, models.ActorCreditCheck, true, false},
+		{"customer cancels pending", models.BookingStatusPending, models.BookingStatusCanceled, models.ActorCustomer, true, false},
+		{"expiry cancels pending", models.BookingStatusPending, models.BookingStatusCanceled, models.ActorExpiry, true, false},
+		{"customer cannot confirm", models.BookingStatusPending, models.BookingStatusConfirmed, models.ActorCustomer, false, true},
+		{"expiry cannot reject", models.BookingStatusPending, models.BookingStatusRejected, models.ActorExpiry, false, true},
+		{"operator cancels pending", models.BookingStatusPending, models.BookingStatusCanceled, models.ActorOperator, true, false},
+		{"admin force-cancels confirmed", models.BookingStatusConfirmed, models.BookingStatusCanceled, models.ActorAdmin, true, false},
+		{"customer cannot cancel confirmed", models.BookingStatusConfirmed, models.BookingStatusCanceled, models.ActorCustomer, false, true},
+		{"operator cannot cancel confirmed", models.BookingStatusConfirmed, models.BookingStatusCanceled, models.ActorOperator, false, true},
+		{"admin cannot cancel rejected", models.BookingStatusRejected, models.BookingStatusCanceled, models.ActorAdmin, false, false},
+		{"canceled cannot be confirmed", models.BookingStatusCanceled, models.BookingStatusConfirmed, models.ActorCreditCheck, false, false},
+		{"rejected cannot be canceled", models.BookingStatusRejected, models.BookingStatusCanceled, models.ActorExpiry, false, false},
+		{"canceled cannot be canceled again", models.BookingStatusCanceled, models.BookingStatusCanceled, models.ActorCustomer, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := models.ValidateTransition(tt.from, tt.to, tt.actor)
+
+			// Assert
+			assert.Equal(t, tt.allowed, tt.from.CanTransition(tt.to, tt.actor))
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, models.ErrInvalidTransition)
+			var transitionErr *models.TransitionError
+			if assert.True(t, errors.As(err, &transitionErr)) {
+				assert.Equal(t, tt.forbidden, transitionErr.Forbidden)
+			}
+		})
+	}
+}
+
+func TestTransitionError_Message(t *testing.T) {
+	// Act
+	illegal := models.ValidateTransition(models.BookingStatusRejected, models.BookingStatusCanceled, models.ActorCustomer)
+	forbidden := models.ValidateTransition(models.BookingStatusPending, models.BookingStatusConfirmed, models.ActorCustomer)
+	adminOnly := models.ValidateTransition(models.BookingStatusConfirmed, models.BookingStatusCanceled, models.ActorCustomer)
+
+	// Assert
+	assert.Equal(t, "cannot cancel a rejected booking", illegal.Error())
+	assert.Equal(t, "customer cannot confirm a pending booking", forbidden.Error())
+	assert.Equal(t, "customer cannot cancel a confirmed booking", adminOnly.Error())
+}
+
+func TestBookingTransitionTo(t *testing.T) {
+	// Arrange
+	at := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	booking := &models.Booking{Status: models.BookingStatusPending}
+
+	// Act
+	err := booking.TransitionTo(models.BookingStatusConfirmed, models.ActorCreditCheck, at)
+	again := booking.TransitionTo(models.BookingStatusCanceled, models.ActorCustomer, at.Add(time.Minute))
+
+	// Assert - the second, illegal transition leaves the booking untouched
+	assert.NoError(t, err)
+	assert.ErrorIs(t, again, models.ErrInvalidTransition)
+	assert.Equal(t, models.BookingStatusConfirmed, booking.Status)
+	assert.Equal(t, at, booking.UpdatedAt)
+}
+
+func TestBookingStatusIsTerminal(t *testing.T) {
+	assert.False(t, models.BookingStatusPending.IsTerminal())
+	// Admins can still force-cancel a confirmed booking
+	assert.False(t, models.BookingStatusConfirmed.IsTerminal())
+	assert.True(t, models.BookingStatusRejected.IsTerminal())
+	assert.True(t, models.BookingStatusCanceled.IsTerminal())
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// CreditCheckOutcome represents the result of a credit check as a string type
+type CreditCheckOutcome string
+
+// CreditCheckOutcome constants
+const (
+	CreditCheckApproved CreditCheckOutcome = "approved"
+	CreditCheckRejected CreditCheckOutcome = "rejected"
+	// CreditCheckFailed means no decision could be obtained, e.g. every attempt timed out
+	CreditCheckFailed CreditCheckOutcome = "failed"
+)
+
+// CreditDecision is a credit checker's verdict on a booking
+type CreditDecision struct {
+	Approved bool
+	Reason   string
+}
+
+// CreditCheck records how the credit check of a booking was decided
+// @Description Outcome of the credit check run for a high-value booking
+type CreditCheck struct {
+	Outcome    CreditCheckOutcome `json:"outcome" example:"approved" description:"Credit check outcome (approved, rejected, failed)"`
+	Reason     string             `json:"reason" example:"price within limit" description:"Reason given for the decision"`
+	Checker    string             `json:"checker" example:"rules" description:"Credit checker that made the decision"`
+	Attempts   int                `json:"attempts" example:"1" description:"Number of attempts made"`
+	TimedOut   bool               `json:"timed_out" example:"false" description:"Whether any attempt timed out"`
+	DurationMs int64              `json:"duration_ms" example:"120" description:"Total time spent checking, in milliseconds"`
+	CheckedAt  time.Time          `json:"checked_at" format:"date-time" example:"2024-03-11T12:00:02Z" description:"When the check finished"`
+}
+
+// Clone returns a copy of the credit check, or nil for a nil receiver
+func (c *CreditCheck) Clone() *CreditCheck {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
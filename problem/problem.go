@@ -0,0 +1,114 @@
+// Package problem writes error responses as RFC 7807 problem details
+// (application/problem+json). Every problem carries a stable Code that
+// clients can switch on; the title and detail are meant for people.
+package problem
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// typePrefix turns a code into the problem type URI
+const typePrefix = "urn:problem-type:booking-system:"
+
+// Code identifies a kind of problem. Codes are part of the API and do not change.
+type Code string
+
+// Code constants
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeValidation           Code = "validation_failed"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeConflict             Code = "conflict"
+	CodeInvalidTransition    Code = "invalid_transition"
+	CodePreconditionFailed   Code = "precondition_failed"
+	CodeIdempotencyKeyReused Code = "idempotency_key_reused"
+	CodeIdempotencyKeyInUse  Code = "idempotency_key_in_use"
+	CodeRateLimited          Code = "rate_limited"
+	CodeUnavailable          Code = "unavailable"
+	CodeInternal             Code = "internal_error"
+)
+
+// statusCodes are the codes of problems known only by their HTTP status
+var statusCodes = map[int]Code{
+	fiber.StatusBadRequest:          CodeBadRequest,
+	fiber.StatusUnauthorized:        CodeUnauthorized,
+	fiber.StatusForbidden:           CodeForbidden,
+	fiber.StatusNotFound:            CodeNotFound,
+	fiber.StatusMethodNotAllowed:    CodeMethodNotAllowed,
+	fiber.StatusConflict:            CodeConflict,
+	fiber.StatusPreconditionFailed:  CodePreconditionFailed,
+	fiber.StatusUnprocessableEntity: CodeValidation,
+	fiber.StatusTooManyRequests:     CodeRateLimited,
+	fiber.StatusServiceUnavailable:  CodeUnavailable,
+}
+
+// CodeForStatus returns the generic code of an HTTP status
+func CodeForStatus(status int) Code {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= fiber.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// InvalidParam describes a request parameter that failed validation
+type InvalidParam struct {
+	Name   string `json:"name" example:"price"`
+	Reason string `json:"reason" example:"must be positive"`
+}
+
+// Problem is an RFC 7807 problem details object. It implements error so
+// handlers can return it as is.
+type Problem struct {
+	// Type is a URI identifying the problem type, derived from Code
+	Type   string `json:"type" example:"urn:problem-type:booking-system:not_found"`
+	Title  string `json:"title" example:"Not Found"`
+	Status int    `json:"status" example:"404"`
+	Detail string `json:"detail,omitempty" example:"booking not found"`
+	// Instance is the path of the request that failed
+	Instance      string         `json:"instance,omitempty" example:"/api/bookings/42"`
+	Code          Code           `json:"code" example:"not_found"`
+	RequestID     string         `json:"request_id,omitempty" example:"3f2a9c1e8b7d4a60"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// New creates a problem titled after its HTTP status
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   typePrefix + string(code),
+		Title:  utils.StatusMessage(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Error implements the error interface
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// Write sends p as the response, filling in the request path and ID
+func Write(c *fiber.Ctx, p *Problem) error {
+	response := *p
+	if response.Instance == "" {
+		response.Instance = c.Path()
+	}
+	if response.RequestID == "" {
+		response.RequestID = logging.RequestIDFromContext(c.UserContext())
+	}
+	return c.Status(response.Status).JSON(response, ContentType)
+}
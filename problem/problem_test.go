@@ -0,0 +1,65 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_SendsProblemDetails(t *testing.T) {
+	// Setup
+	app := fiber.New()
+	app.Get("/bookings/:id", func(c *fiber.Ctx) error {
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), "req-1"))
+		return problem.Write(c, problem.New(fiber.StatusNotFound, problem.CodeNotFound, "booking not found"))
+	})
+
+	// Execute
+	resp, err := app.Test(httptest.NewRequest("GET", "/bookings/9", nil))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{
+		"type":       "urn:problem-type:booking-system:not_found",
+		"title":      "Not Found",
+		"status":     float64(404),
+		"detail":     "booking not found",
+		"instance":   "/bookings/9",
+		"code":       "not_found",
+		"request_id": "req-1",
+	}, body)
+}
+
+func TestWrite_DoesNotChangeSharedProblem(t *testing.T) {
+	// Setup - handlers may return a package-level problem for every request
+	shared := problem.New(fiber.StatusPreconditionFailed, problem.CodePreconditionFailed, "stale")
+	app := fiber.New()
+	app.Get("/*", func(c *fiber.Ctx) error {
+		return problem.Write(c, shared)
+	})
+
+	// Execute
+	_, err := app.Test(httptest.NewRequest("GET", "/first", nil))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, shared.Instance)
+}
+
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, problem.CodeNotFound, problem.CodeForStatus(fiber.StatusNotFound))
+	assert.Equal(t, problem.CodeRateLimited, problem.CodeForStatus(fiber.StatusTooManyRequests))
+	assert.Equal(t, problem.CodeBadRequest, problem.CodeForStatus(fiber.StatusRequestEntityTooLarge))
+	assert.Equal(t, problem.CodeInternal, problem.CodeForStatus(fiber.StatusBadGateway))
+}
@@ -0,0 +1,121 @@
+// Package ratelimit implements token-bucket rate limiting. Every client key
+// owns a bucket that refills at a steady rate up to a burst capacity; each
+// request takes one token and is denied when the bucket is empty.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket: Requests tokens are added every Per, and the
+// bucket holds at most Burst tokens
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	// Burst is the bucket capacity; Requests when zero
+	Burst int
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// Capacity returns the number of tokens a full bucket holds
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// rate returns the tokens added per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// durationFor returns how long it takes to refill tokens
+func (l Limit) durationFor(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens / l.rate() * float64(time.Second)))
+}
+
+// String formats the limit the way ParseLimit reads it
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	s := fmt.Sprintf("%d/%s", l.Requests, l.Per)
+	if l.Burst > 0 {
+		s += fmt.Sprintf(",burst=%d", l.Burst)
+	}
+	return s
+}
+
+// ParseLimit reads a limit such as "60/1m" or "10/1s,burst=20".
+// "off" and "0" disable limiting.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "off" || value == "0" {
+		return Limit{}, nil
+	}
+
+	rate, burst, hasBurst := strings.Cut(value, ",")
+	requests, per, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected requests/duration", value)
+	}
+
+	var limit Limit
+	var err error
+	if limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil || limit.Requests <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", value)
+	}
+	if limit.Per, err = time.ParseDuration(strings.TrimSpace(per)); err != nil || limit.Per <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", value)
+	}
+
+	if hasBurst {
+		name, size, ok := strings.Cut(strings.TrimSpace(burst), "=")
+		if !ok || name != "burst" {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: expected burst=<n>", value)
+		}
+		if limit.Burst, err = strconv.Atoi(size); err != nil || limit.Burst <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", value)
+		}
+	}
+
+	return limit, nil
+}
+
+// MarshalText formats the limit like String, so limits can be written to configuration files
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText reads a limit with ParseLimit
+func (l *Limit) UnmarshalText(text []byte) error {
+	limit, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = limit
+	return nil
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket
+	Remaining int
+	// RetryAfter is how long a denied client should wait for the next token
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
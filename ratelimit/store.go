@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval bounds how often idle buckets are purged
+const sweepInterval = time.Minute
+
+// Store keeps the token buckets. Implementations shared between instances,
+// e.g. backed by Redis, let several servers enforce one limit.
+type Store interface {
+	// Take removes a token from the bucket for key, creating a full bucket
+	// for unknown keys
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket is the state of one token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled completely
+	full time.Time
+}
+
+// MemoryStore keeps buckets in memory, so every instance enforces its own limit
+type MemoryStore struct {
+	buckets   map[string]*bucket
+	nextSweep time.Time
+	mu        sync.Mutex
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Take removes a token from the bucket for key
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	capacity := float64(limit.Capacity())
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+
+	// Refill for the time since the last request
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(capacity, b.tokens+elapsed*limit.rate())
+	b.updated = now
+
+	result := Result{Allowed: b.tokens >= 1}
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = limit.durationFor(1 - b.tokens)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = limit.durationFor(capacity - b.tokens)
+	b.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep drops buckets that have refilled completely, since a missing bucket
+// starts full, at most once per sweepInterval
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(sweepInterval)
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ratelimit.Limit
+	}{
+		{"60/1m", ratelimit.Limit{Requests: 60, Per: time.Minute}},
+		{"10/1s,burst=20", ratelimit.Limit{Requests: 10, Per: time.Second, Burst: 20}},
+		{"off", ratelimit.Limit{}},
+		{"0", ratelimit.Limit{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Execute
+			limit, err := ratelimit.ParseLimit(tt.value)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, limit)
+		})
+	}
+}
+
+func TestParseLimit_Invalid(t *testing.T) {
+	for _, value := range []string{"", "60", "x/1m", "-1/1m", "60/soon", "60/0s", "60/1m,size=2", "60/1m,burst=0"} {
+		t.Run(value, func(t *testing.T) {
+			// Execute
+			_, err := ratelimit.ParseLimit(value)
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLimit_TextRoundTrip(t *testing.T) {
+	for _, limit := range []ratelimit.Limit{
+		{Requests: 60, Per: time.Minute},
+		{Requests: 10, Per: time.Second, Burst: 20},
+		{},
+	} {
+		t.Run(limit.String(), func(t *testing.T) {
+			// Execute
+			text, err := limit.MarshalText()
+			require.NoError(t, err)
+			var parsed ratelimit.Limit
+			err = parsed.UnmarshalText(text)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, limit, parsed)
+		})
+	}
+}
+
+func TestMemoryStore_TakesTokensUntilEmpty(t *testing.T) {
+	// Setup - three tokens that refill one per hour
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Hour, Burst: 3}
+
+	// Execute
+	var results []ratelimit.Result
+	for i := 0; i < 4; i++ {
+		result, err := store.Take(context.Background(), "client", limit)
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	// Assert
+	for i, remaining := range []int{2, 1, 0} {
+		assert.True(t, results[i].Allowed)
+		assert.Equal(t, remaining, results[i].Remaining)
+		assert.Zero(t, results[i].RetryAfter)
+	}
+	denied := results[3]
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 0, denied.Remaining)
+	assert.InDelta(t, time.Hour.Seconds(), denied.RetryAfter.Seconds(), 1)
+	assert.InDelta(t, (3 * time.Hour).Seconds(), denied.Reset.Seconds(), 1)
+}
+
+func TestMemoryStore_Refills(t *testing.T) {
+	// Setup - one token every 20ms
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 1, Per: 20 * time.Millisecond}
+	ctx := context.Background()
+
+	first, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	denied, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+
+	// Execute
+	time.Sleep(30 * time.Millisecond)
+	refilled, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, first.Allowed)
+	assert.False(t, denied.Allowed)
+	assert.True(t, refilled.Allowed)
+}
+
+func TestMemoryStore_KeysHaveSeparateBuckets(t *testing.T) {
+	// Setup
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Hour}
+	ctx := context.Background()
+
+	// Execute
+	a, err := store.Take(ctx, "a", limit)
+	require.NoError(t, err)
+	b, err := store.Take(ctx, "b", limit)
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, a.Allowed)
+	assert.True(t, b.Allowed)
+}
+
+func TestMemoryStore_ConcurrentTakesNeverExceedCapacity(t *testing.T) {
+	// Setup
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 10, Per: time.Hour}
+
+	// Execute
+	var allowed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := store.Take(context.Background(), "client", limit)
+			if err == nil && result.Allowed {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, int32(10), allowed)
+}
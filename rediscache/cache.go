@@ -0,0 +1,418 @@
+package rediscache
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+)
+
+// scanBatch is the COUNT hint of SCAN and the size of MGET batches
+const scanBatch = 100
+
+// invalidateSuffix turns a cache's prefix into its invalidation channel
+const invalidateSuffix = "__invalidate"
+
+// Codec serializes cached values
+type Codec[V any] interface {
+	Marshal(value V) ([]byte, error)
+	Unmarshal(data []byte) (V, error)
+}
+
+// JSONCodec serializes values as JSON, e.g. *models.Booking with its JSON tags
+type JSONCodec[V any] struct{}
+
+// Marshal encodes value as JSON
+func (JSONCodec[V]) Marshal(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal decodes a JSON value
+func (JSONCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// CacheOptions configures a Cache
+type CacheOptions struct {
+	// Prefix starts every key of the cache, e.g. "booking-system:booking:",
+	// so caches sharing a server keep apart
+	Prefix string
+	// TTL is how long entries live on the server; zero keeps them until deleted
+	TTL time.Duration
+	// NearTTL enables a local copy of entries read from the server, kept for
+	// at most NearTTL. Writes on any instance evict the copies of the others.
+	NearTTL time.Duration
+	// NearMaxEntries bounds the local copies; zero means unbounded
+	NearMaxEntries int
+}
+
+// Cache implements utils.Cache on a RESP server. Keys are stored as Prefix
+// followed by the key printed with fmt, so K must print and scan as a single
+// token, such as an integer.
+//
+// The Cache interface cannot report failures, so an unreachable server
+// makes Get miss and writes get lost; both are logged. Every Set and Delete
+// is published to the other instances, which drop their local copy of the
+// entry. Close stops listening for their changes.
+type Cache[K comparable, V any] struct {
+	client  *Client
+	codec   Codec[V]
+	options CacheOptions
+	// near holds local copies of entries; nil without NearTTL
+	near *utils.InMemoryCache[K, V]
+	// node identifies this instance in invalidation messages so it ignores its own
+	node string
+
+	// fillMu orders copying server reads into near against invalidations
+	fillMu sync.Mutex
+	// fills holds the server reads of keys in progress
+	fills map[K]*fill
+
+	hits, misses atomic.Uint64
+	stop         context.CancelFunc
+	subscribed   chan struct{}
+	once         sync.Once
+}
+
+// NewCache creates a cache stored by client
+func NewCache[K comparable, V any](client *Client, codec Codec[V], options CacheOptions) *Cache[K, V] {
+	ctx, stop := context.WithCancel(context.Background())
+	c := &Cache[K, V]{
+		client:     client,
+		codec:      codec,
+		options:    options,
+		node:       newNodeID(),
+		fills:      make(map[K]*fill),
+		stop:       stop,
+		subscribed: make(chan struct{}),
+	}
+
+	if options.NearTTL > 0 {
+		c.near = utils.NewInMemoryCache[K, V](
+			utils.WithCacheTTL(options.NearTTL),
+			utils.WithMaxEntries(options.NearMaxEntries))
+		go client.Subscribe(ctx, c.channel(), c.resubscribed, c.invalidated)
+	} else {
+		close(c.subscribed)
+	}
+	return c
+}
+
+// fill tracks the server reads of a key whose result may be copied into near
+type fill struct {
+	readers int
+	// generation counts the invalidations of the key since the first reader started
+	generation uint64
+}
+
+// newNodeID returns a random identifier for this instance
+func newNodeID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Subscribed is closed once the cache listens for other instances' changes
+func (c *Cache[K, V]) Subscribed() <-chan struct{} {
+	return c.subscribed
+}
+
+// Get returns the value stored under key, from the local copy if there is one
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	if c.near == nil {
+		return c.count(c.get(key))
+	}
+	if value, found := c.near.Get(key); found {
+		c.hits.Add(1)
+		return value, true
+	}
+
+	generation := c.startFill(key)
+	value, found := c.get(key)
+	c.finishFill(key, generation, value, found)
+	return c.count(value, found)
+}
+
+// count records a lookup of the server as a hit or a miss
+func (c *Cache[K, V]) count(value V, found bool) (V, bool) {
+	if found {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return value, found
+}
+
+// startFill registers a server read of key and returns the key's generation
+func (c *Cache[K, V]) startFill(key K) uint64 {
+	c.fillMu.Lock()
+	defer c.fillMu.Unlock()
+
+	f, ok := c.fills[key]
+	if !ok {
+		f = &fill{}
+		c.fills[key] = f
+	}
+	f.readers++
+	return f.generation
+}
+
+// finishFill unregisters a server read of key and copies a found value into
+// near, unless the key was invalidated since the read started: the value may
+// then predate the change and would stay stale until NearTTL passes.
+func (c *Cache[K, V]) finishFill(key K, generation uint64, value V, found bool) {
+	c.fillMu.Lock()
+	defer c.fillMu.Unlock()
+
+	f := c.fills[key]
+	if found && f.generation == generation {
+		c.near.Set(key, value)
+	}
+	f.readers--
+	if f.readers == 0 {
+		delete(c.fills, key)
+	}
+}
+
+// invalidate drops the local copy of key and keeps reads in progress from
+// copying it back
+func (c *Cache[K, V]) invalidate(key K) {
+	c.fillMu.Lock()
+	defer c.fillMu.Unlock()
+
+	if f, ok := c.fills[key]; ok {
+		f.generation++
+	}
+	c.near.Delete(key)
+}
+
+func (c *Cache[K, V]) get(key K) (V, bool) {
+	var zero V
+	ctx := context.Background()
+	reply, err := c.client.Do(ctx, "GET", c.key(key))
+	if err != nil {
+		slog.WarnContext(ctx, "Cache read failed", "key", c.key(key), "error", err)
+		return zero, false
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return zero, false
+	}
+
+	value, err := c.codec.Unmarshal(data)
+	if err != nil {
+		slog.WarnContext(ctx, "Dropping cache entry that cannot be decoded", "key", c.key(key), "error", err)
+		return zero, false
+	}
+	return value, true
+}
+
+// Set stores value under key and tells the other instances to drop their copy
+func (c *Cache[K, V]) Set(key K, value V) {
+	ctx := context.Background()
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot encode cache entry", "key", c.key(key), "error", err)
+		c.Delete(key)
+		return
+	}
+
+	args := []string{"SET", c.key(key), string(data)}
+	if c.options.TTL > 0 {
+		args = append(args, "PX", strconv.FormatInt(c.options.TTL.Milliseconds(), 10))
+	}
+	if _, err := c.client.Do(ctx, args...); err != nil {
+		slog.WarnContext(ctx, "Cache write failed", "key", c.key(key), "error", err)
+	}
+	c.changed(ctx, key)
+}
+
+// Delete removes key and tells the other instances to drop their copy
+func (c *Cache[K, V]) Delete(key K) {
+	ctx := context.Background()
+	if _, err := c.client.Do(ctx, "DEL", c.key(key)); err != nil {
+		slog.WarnContext(ctx, "Cache delete failed", "key", c.key(key), "error", err)
+	}
+	c.changed(ctx, key)
+}
+
+// GetAll returns the entries stored on the server under the cache's prefix
+func (c *Cache[K, V]) GetAll() map[K]V {
+	ctx := context.Background()
+	all := make(map[K]V)
+	err := c.scan(ctx, func(keys []string) error {
+		reply, err := c.client.Do(ctx, append([]string{"MGET"}, keys...)...)
+		if err != nil {
+			return err
+		}
+		values, _ := reply.([]any)
+		for i, raw := range values {
+			data, ok := raw.([]byte)
+			if !ok || i >= len(keys) {
+				continue
+			}
+			key, err := c.parseKey(keys[i])
+			if err != nil {
+				continue
+			}
+			if value, err := c.codec.Unmarshal(data); err == nil {
+				all[key] = value
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "Cache scan failed", "prefix", c.options.Prefix, "error", err)
+	}
+	return all
+}
+
+// Stats returns this instance's hits and misses; it does not ask the server.
+// Entries, evictions and expirations are those of the local copies, and zero
+// without NearTTL, since the server's are not counted.
+func (c *Cache[K, V]) Stats() utils.CacheStats {
+	var stats utils.CacheStats
+	if c.near != nil {
+		stats = c.near.Stats()
+	}
+	stats.Hits = c.hits.Load()
+	stats.Misses = c.misses.Load()
+	return stats
+}
+
+// Ping checks that the server answers
+func (c *Cache[K, V]) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx)
+}
+
+// Close stops listening for other instances' changes. The client is not closed.
+func (c *Cache[K, V]) Close() {
+	c.once.Do(func() {
+		c.stop()
+		if c.near != nil {
+			c.near.Close()
+		}
+	})
+}
+
+// key returns the server key of key
+func (c *Cache[K, V]) key(key K) string {
+	return c.options.Prefix + fmt.Sprint(key)
+}
+
+// parseKey reverses key
+func (c *Cache[K, V]) parseKey(serverKey string) (K, error) {
+	var key K
+	_, err := fmt.Sscan(strings.TrimPrefix(serverKey, c.options.Prefix), &key)
+	return key, err
+}
+
+// scan passes the server keys under the prefix to visit in batches
+func (c *Cache[K, V]) scan(ctx context.Context, visit func(keys []string) error) error {
+	cursor := "0"
+	for {
+		reply, err := c.client.Do(ctx, "SCAN", cursor, "MATCH", escapePattern(c.options.Prefix)+"*", "COUNT", strconv.Itoa(scanBatch))
+		if err != nil {
+			return err
+		}
+		array, ok := reply.([]any)
+		if !ok || len(array) != 2 {
+			return fmt.Errorf("unexpected reply to SCAN: %v", reply)
+		}
+		next, _ := array[0].([]byte)
+		items, _ := array[1].([]any)
+
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			if key, ok := item.([]byte); ok && !bytes.HasSuffix(key, []byte(invalidateSuffix)) {
+				keys = append(keys, string(key))
+			}
+		}
+		if len(keys) > 0 {
+			if err := visit(keys); err != nil {
+				return err
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// channel is where the instances sharing the prefix announce changed keys
+func (c *Cache[K, V]) channel() string {
+	return c.options.Prefix + invalidateSuffix
+}
+
+// changed drops the local copy of key once the server has the change and
+// tells the other instances to do the same. Messages are the sending node
+// and the key, separated by a space.
+func (c *Cache[K, V]) changed(ctx context.Context, key K) {
+	if c.near == nil {
+		return
+	}
+	c.invalidate(key)
+	message := c.node + " " + fmt.Sprint(key)
+	if _, err := c.client.Publish(ctx, c.channel(), []byte(message)); err != nil {
+		slog.WarnContext(ctx, "Cache invalidation not sent", "key", c.key(key), "error", err)
+	}
+}
+
+// invalidated drops the local copy of a key another instance changed
+func (c *Cache[K, V]) invalidated(message []byte) {
+	node, rawKey, ok := strings.Cut(string(message), " ")
+	if !ok || node == c.node {
+		return
+	}
+	var key K
+	if _, err := fmt.Sscan(rawKey, &key); err != nil {
+		return
+	}
+	c.invalidate(key)
+}
+
+// resubscribed drops every local copy and invalidates the reads in progress,
+// since changes may have been missed while the subscription was down
+func (c *Cache[K, V]) resubscribed() {
+	c.fillMu.Lock()
+	for _, f := range c.fills {
+		f.generation++
+	}
+	for key := range c.near.GetAll() {
+		c.near.Delete(key)
+	}
+	c.fillMu.Unlock()
+
+	select {
+	case <-c.subscribed:
+	default:
+		close(c.subscribed)
+	}
+}
+
+// escapePattern escapes the glob characters of a SCAN MATCH pattern
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
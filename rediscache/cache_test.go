@@ -0,0 +1,262 @@
+package rediscache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/rediscache"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/rediscache/redistest"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newBookingCache creates a booking cache on server, closed with the test
+func newBookingCache(t *testing.T, server *redistest.Server, options rediscache.CacheOptions) *rediscache.Cache[int64, *models.Booking] {
+	t.Helper()
+	client := rediscache.NewClient(rediscache.Options{Address: server.Addr()})
+	cache := rediscache.NewCache[int64, *models.Booking](client, rediscache.JSONCodec[*models.Booking]{}, options)
+	t.Cleanup(func() {
+		cache.Close()
+		client.Close()
+	})
+
+	select {
+	case <-cache.Subscribed():
+	case <-time.After(time.Second):
+		t.Fatal("cache did not subscribe to invalidations")
+	}
+	return cache
+}
+
+// newBooking returns a booking with every field set
+func newBooking(id int64) *models.Booking {
+	created := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	return &models.Booking{
+		ID:        id,
+		UserID:    101,
+		ServiceID: 201,
+		Price:     75000,
+		Status:    models.BookingStatusConfirmed,
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Minute),
+		Version:   3,
+		ExpiresAt: created.Add(5 * time.Minute),
+		CreditCheck: &models.CreditCheck{
+			Outcome:    models.CreditCheckApproved,
+			Reason:     "price within limit",
+			Checker:    "rules",
+			Attempts:   2,
+			TimedOut:   true,
+			DurationMs: 120,
+			CheckedAt:  created.Add(2 * time.Second),
+		},
+	}
+}
+
+func TestCache_SetGetDelete(t *testing.T) {
+	// Setup
+	server := redistest.NewServer()
+	defer server.Close()
+	cache := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "test:booking:"})
+	booking := newBooking(1)
+
+	// Execute
+	cache.Set(1, booking)
+	cached, found := cache.Get(1)
+	cache.Delete(1)
+	_, foundAfterDelete := cache.Get(1)
+
+	// Assert - the booking survives serialization, and keys carry the prefix
+	require.True(t, found)
+	assert.Equal(t, booking, cached)
+	assert.NotSame(t, booking, cached)
+	assert.False(t, foundAfterDelete)
+	assert.Empty(t, server.Keys())
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestCache_TTL(t *testing.T) {
+	// Setup
+	server := redistest.NewServer()
+	defer server.Close()
+	cache := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "test:booking:", TTL: 20 * time.Millisecond})
+
+	// Execute
+	cache.Set(1, newBooking(1))
+
+	// Assert
+	assert.Equal(t, []string{"test:booking:1"}, server.Keys())
+	assert.Eventually(t, func() bool {
+		_, found := cache.Get(1)
+		return !found
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCache_GetAllKeepsToPrefix(t *testing.T) {
+	// Setup - another cache with a prefix containing glob characters shares the server
+	server := redistest.NewServer()
+	defer server.Close()
+	cache := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "a*:booking:"})
+	other := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "ab:booking:"})
+	for id := int64(1); id <= 250; id++ {
+		cache.Set(id, newBooking(id))
+	}
+	other.Set(1000, newBooking(1000))
+
+	// Execute
+	all := cache.GetAll()
+
+	// Assert
+	assert.Len(t, all, 250)
+	assert.Equal(t, newBooking(42), all[42])
+	assert.NotContains(t, all, int64(1000))
+}
+
+func TestCache_NearCacheServesRepeatedReads(t *testing.T) {
+	// Setup
+	server := redistest.NewServer()
+	defer server.Close()
+	cache := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "test:booking:", NearTTL: time.Minute})
+	cache.Set(1, newBooking(1))
+
+	// Execute
+	for i := 0; i < 3; i++ {
+		_, found := cache.Get(1)
+		require.True(t, found)
+	}
+
+	// Assert - the statistics count the local copy without asking the server
+	assert.Equal(t, 1, server.CommandCount("GET"))
+	stats := cache.Stats()
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Zero(t, server.CommandCount("SCAN"))
+}
+
+func TestCache_ReadRacingAWriteIsNotCopied(t *testing.T) {
+	// Setup - the booking changes after the server answered a read and
+	// before the reply arrives
+	server := redistest.NewServer()
+	defer server.Close()
+	cache := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "test:booking:", NearTTL: time.Minute})
+	cache.Set(1, newBooking(1))
+	updated := newBooking(1)
+	updated.Version = 4
+	server.BeforeReply("GET", func() {
+		server.BeforeReply("GET", nil)
+		cache.Set(1, updated)
+	})
+
+	// Execute
+	stale, foundStale := cache.Get(1)
+	cached, found := cache.Get(1)
+
+	// Assert - the outdated reply is returned once but not kept as the local copy
+	require.True(t, foundStale)
+	assert.Equal(t, int64(3), stale.Version)
+	require.True(t, found)
+	assert.Equal(t, int64(4), cached.Version)
+	assert.Equal(t, 2, server.CommandCount("GET"))
+}
+
+func TestCache_WriteOnOneInstanceEvictsTheOthers(t *testing.T) {
+	// Setup - both instances hold a local copy of the booking
+	server := redistest.NewServer()
+	defer server.Close()
+	options := rediscache.CacheOptions{Prefix: "test:booking:", NearTTL: time.Minute}
+	first := newBookingCache(t, server, options)
+	second := newBookingCache(t, server, options)
+	first.Set(1, newBooking(1))
+	first.Get(1)
+	second.Get(1)
+
+	// Execute
+	updated := newBooking(1)
+	updated.Version = 4
+	first.Set(1, updated)
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		cached, found := second.Get(1)
+		return found && cached.Version == 4
+	}, time.Second, 10*time.Millisecond)
+	cached, found := first.Get(1)
+	require.True(t, found)
+	assert.Equal(t, int64(4), cached.Version)
+}
+
+func TestCache_ResubscribingDropsLocalCopies(t *testing.T) {
+	// Setup
+	server := redistest.NewServer()
+	defer server.Close()
+	cache := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "test:booking:", NearTTL: time.Minute})
+	cache.Set(1, newBooking(1))
+	cache.Get(1)
+
+	// Execute - the server restarts, and the booking changes while no invalidation can arrive
+	server.CloseClientConnections()
+	client := rediscache.NewClient(rediscache.Options{Address: server.Addr()})
+	defer client.Close()
+	_, err := client.Do(context.Background(), "DEL", "test:booking:1")
+	require.NoError(t, err)
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		_, found := cache.Get(1)
+		return !found
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestCache_UnreachableServerMisses(t *testing.T) {
+	// Setup
+	server := redistest.NewServer()
+	cache := newBookingCache(t, server, rediscache.CacheOptions{Prefix: "test:booking:"})
+	cache.Set(1, newBooking(1))
+	server.Close()
+
+	// Execute
+	_, found := cache.Get(1)
+	cache.Set(2, newBooking(2))
+
+	// Assert
+	assert.False(t, found)
+	assert.Empty(t, cache.GetAll())
+	assert.Error(t, cache.Ping(context.Background()))
+}
+
+func TestCache_CancelOnOneNodeEvictsTheOthers(t *testing.T) {
+	// Setup - two nodes share the repository and the cache server, and both have read the booking
+	server := redistest.NewServer()
+	defer server.Close()
+	repo := repository.NewBookingRepositoryMock()
+	options := rediscache.CacheOptions{Prefix: "booking-system:booking:", NearTTL: time.Minute}
+	newNode := func() usecase.BookingUseCase {
+		jobQueue := new(mocks.JobQueue)
+		jobQueue.On("Register", mock.Anything, mock.Anything).Return()
+		return usecase.NewBookingUseCase(repo, newBookingCache(t, server, options), new(mocks.CreditChecker), jobQueue)
+	}
+	first, second := newNode(), newNode()
+	ctx := usecase.WithInternalCaller(context.Background())
+	_, err := first.GetBookingByID(ctx, 1)
+	require.NoError(t, err)
+	_, err = second.GetBookingByID(ctx, 1)
+	require.NoError(t, err)
+
+	// Execute
+	_, err = first.CancelBooking(ctx, 1, 0)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		booking, err := second.GetBookingByID(ctx, 1)
+		return err == nil && booking.Status == models.BookingStatusCanceled
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,229 @@
+// Package rediscache keeps cached values in a server speaking the Redis
+// protocol (RESP), so several instances of the service share one cache.
+// Client is a small pooled RESP client; Cache implements utils.Cache on
+// top of it and tells the other instances which entries changed.
+package rediscache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by a closed Client
+var ErrClosed = errors.New("rediscache: client is closed")
+
+// Options configures a Client
+type Options struct {
+	// Address is the host:port of the server
+	Address string
+	// Password is sent with AUTH on every new connection when set
+	Password string
+	// DB is selected on every new connection when not zero
+	DB int
+	// PoolSize bounds the open connections; callers wait for a free one
+	PoolSize int
+	// DialTimeout bounds connecting, including AUTH and SELECT
+	DialTimeout time.Duration
+	// Timeout bounds each command unless the context ends earlier
+	Timeout time.Duration
+}
+
+// DefaultOptions returns the options used for unset fields
+func DefaultOptions() Options {
+	return Options{
+		PoolSize:    10,
+		DialTimeout: 2 * time.Second,
+		Timeout:     500 * time.Millisecond,
+	}
+}
+
+// conn is a connection to the server
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// Client sends commands to a RESP server over a pool of connections.
+// It is safe for concurrent use.
+type Client struct {
+	options Options
+	// slots holds a token for every connection that may be opened
+	slots chan struct{}
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// NewClient creates a client; connections are opened when first needed
+func NewClient(options Options) *Client {
+	defaults := DefaultOptions()
+	if options.PoolSize <= 0 {
+		options.PoolSize = defaults.PoolSize
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = defaults.DialTimeout
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaults.Timeout
+	}
+
+	return &Client{
+		options: options,
+		slots:   make(chan struct{}, options.PoolSize),
+	}
+}
+
+// Do sends a command and returns its reply. Error replies are returned as
+// an Error; network failures close the connection they happened on.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.options.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	cn.SetDeadline(deadline)
+
+	reply, err := cn.do(args...)
+	if err != nil {
+		c.discard(cn)
+		return nil, fmt.Errorf("rediscache: %s: %w", args[0], err)
+	}
+	c.put(cn)
+
+	if replyErr, ok := reply.(Error); ok {
+		return nil, replyErr
+	}
+	return reply, nil
+}
+
+// Ping checks that the server answers
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections; connections in use are closed when
+// they are returned. Commands sent after Close fail with ErrClosed.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, cn := range c.idle {
+		cn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+// get takes an idle connection or opens one, waiting while the pool is full
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("rediscache: waiting for a connection: %w", ctx.Err())
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		<-c.slots
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	cn, err := c.dial(ctx)
+	if err != nil {
+		<-c.slots
+		return nil, err
+	}
+	return cn, nil
+}
+
+// put returns a healthy connection to the pool
+func (c *Client) put(cn *conn) {
+	c.mu.Lock()
+	if c.closed {
+		cn.Close()
+	} else {
+		c.idle = append(c.idle, cn)
+	}
+	c.mu.Unlock()
+	<-c.slots
+}
+
+// discard closes a connection whose state is unknown after a failure
+func (c *Client) discard(cn *conn) {
+	cn.Close()
+	<-c.slots
+}
+
+// dial opens a connection, authenticates and selects the database
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.options.DialTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", c.options.Address)
+	if err != nil {
+		return nil, fmt.Errorf("rediscache: connect: %w", err)
+	}
+	cn := &conn{Conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	deadline, _ := ctx.Deadline()
+	cn.SetDeadline(deadline)
+	if c.options.Password != "" {
+		if err := cn.expectOK("AUTH", c.options.Password); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("rediscache: AUTH: %w", err)
+		}
+	}
+	if c.options.DB != 0 {
+		if err := cn.expectOK("SELECT", strconv.Itoa(c.options.DB)); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("rediscache: SELECT: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+// do writes a command and reads its reply
+func (cn *conn) do(args ...string) (any, error) {
+	if err := WriteCommand(cn.w, args...); err != nil {
+		return nil, err
+	}
+	return ReadReply(cn.r)
+}
+
+// expectOK sends a command that must be answered with OK
+func (cn *conn) expectOK(args ...string) error {
+	reply, err := cn.do(args...)
+	if err != nil {
+		return err
+	}
+	if replyErr, ok := reply.(Error); ok {
+		return replyErr
+	}
+	if reply != "OK" {
+		return fmt.Errorf("unexpected reply %v", reply)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package rediscache_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/rediscache"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/rediscache/redistest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newClient connects to a new stub server; both are closed with the test
+func newClient(t *testing.T, options rediscache.Options) (*rediscache.Client, *redistest.Server) {
+	t.Helper()
+	server := redistest.NewServer()
+	t.Cleanup(server.Close)
+	options.Address = server.Addr()
+	client := rediscache.NewClient(options)
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+// silentAddr returns the address of a server that accepts connections and never replies
+func silentAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var conns []net.Conn
+	t.Cleanup(func() {
+		listener.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestClient_Do(t *testing.T) {
+	// Setup
+	client, server := newClient(t, rediscache.Options{Password: "secret", DB: 2})
+	ctx := context.Background()
+
+	// Execute
+	setReply, err := client.Do(ctx, "SET", "greeting", "hello\r\nworld")
+	require.NoError(t, err)
+	getReply, err := client.Do(ctx, "GET", "greeting")
+	require.NoError(t, err)
+	missingReply, err := client.Do(ctx, "GET", "missing")
+	require.NoError(t, err)
+	deleted, err := client.Do(ctx, "DEL", "greeting", "missing")
+	require.NoError(t, err)
+
+	// Assert - values are binary safe, and AUTH and SELECT are sent once per connection
+	assert.Equal(t, "OK", setReply)
+	assert.Equal(t, []byte("hello\r\nworld"), getReply)
+	assert.Nil(t, missingReply)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, 1, server.CommandCount("AUTH"))
+	assert.Equal(t, 1, server.CommandCount("SELECT"))
+}
+
+func TestClient_ErrorReplyKeepsConnection(t *testing.T) {
+	// Setup
+	client, _ := newClient(t, rediscache.Options{PoolSize: 1})
+	ctx := context.Background()
+
+	// Execute
+	_, err := client.Do(ctx, "NOSUCHCOMMAND")
+
+	// Assert
+	var replyErr rediscache.Error
+	require.ErrorAs(t, err, &replyErr)
+	assert.Contains(t, replyErr.Error(), "unknown command")
+	assert.NoError(t, client.Ping(ctx))
+}
+
+func TestClient_Timeout(t *testing.T) {
+	// Setup
+	client := rediscache.NewClient(rediscache.Options{Address: silentAddr(t), Timeout: 50 * time.Millisecond})
+	defer client.Close()
+
+	// Execute
+	start := time.Now()
+	err := client.Ping(context.Background())
+
+	// Assert
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_ContextDeadlineShortensTimeout(t *testing.T) {
+	// Setup
+	client := rediscache.NewClient(rediscache.Options{Address: silentAddr(t), Timeout: time.Minute})
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Execute
+	start := time.Now()
+	err := client.Ping(ctx)
+
+	// Assert
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient_WaitsForFreeConnection(t *testing.T) {
+	// Setup - the only connection is busy with a command that is never answered
+	client := rediscache.NewClient(rediscache.Options{Address: silentAddr(t), PoolSize: 1, Timeout: time.Second})
+	defer client.Close()
+	busy := make(chan error)
+	go func() { busy <- client.Ping(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+
+	// Execute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.Ping(ctx)
+
+	// Assert - the second command gave up waiting; the slot is freed once the first times out
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Error(t, <-busy)
+}
+
+func TestClient_ReconnectsAfterServerDropsConnections(t *testing.T) {
+	// Setup
+	client, server := newClient(t, rediscache.Options{PoolSize: 1})
+	ctx := context.Background()
+	require.NoError(t, client.Ping(ctx))
+	server.CloseClientConnections()
+
+	// Execute - the pooled connection is dead; the failure discards it
+	firstErr := client.Ping(ctx)
+	secondErr := client.Ping(ctx)
+
+	// Assert
+	assert.Error(t, firstErr)
+	assert.NoError(t, secondErr)
+}
+
+func TestClient_Closed(t *testing.T) {
+	// Setup
+	client, _ := newClient(t, rediscache.Options{})
+	require.NoError(t, client.Ping(context.Background()))
+
+	// Execute
+	require.NoError(t, client.Close())
+	err := client.Ping(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, rediscache.ErrClosed)
+}
+
+func TestClient_PublishSubscribe(t *testing.T) {
+	// Setup
+	client, _ := newClient(t, rediscache.Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subscribed := make(chan struct{}, 1)
+	messages := make(chan string, 1)
+	go client.Subscribe(ctx, "news",
+		func() { subscribed <- struct{}{} },
+		func(message []byte) { messages <- string(message) })
+	<-subscribed
+
+	// Execute
+	receivers, err := client.Publish(ctx, "news", []byte("hello"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), receivers)
+	select {
+	case message := <-messages:
+		assert.Equal(t, "hello", message)
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	// Setup
+	input := "+OK\r\n-ERR bad\r\n:42\r\n$5\r\nhello\r\n$-1\r\n*2\r\n$1\r\na\r\n:1\r\n*-1\r\n"
+	r := bufio.NewReader(strings.NewReader(input))
+
+	// Execute
+	var replies []any
+	for {
+		reply, err := rediscache.ReadReply(r)
+		if err != nil {
+			break
+		}
+		replies = append(replies, reply)
+	}
+
+	// Assert
+	assert.Equal(t, []any{
+		"OK",
+		rediscache.Error("ERR bad"),
+		int64(42),
+		[]byte("hello"),
+		nil,
+		[]any{[]byte("a"), int64(1)},
+		nil,
+	}, replies)
+}
+
+func TestReadReply_RejectsMalformedInput(t *testing.T) {
+	for _, input := range []string{"?\r\n", "$3\r\nabcd\r\n", ":x\r\n", "+OK\n", "$-5\r\n"} {
+		_, err := rediscache.ReadReply(bufio.NewReader(strings.NewReader(input)))
+		assert.Error(t, err, "input %q", input)
+	}
+}
@@ -0,0 +1,115 @@
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// Subscription reconnect backoff and keepalive
+const (
+	minResubscribeDelay = 100 * time.Millisecond
+	maxResubscribeDelay = 10 * time.Second
+	// subscriptionPingInterval is how long a quiet subscription waits before
+	// checking with PING that the connection is still alive
+	subscriptionPingInterval = 30 * time.Second
+)
+
+// Publish sends message to the subscribers of channel and returns how many received it
+func (c *Client) Publish(ctx context.Context, channel string, message []byte) (int64, error) {
+	reply, err := c.Do(ctx, "PUBLISH", channel, string(message))
+	if err != nil {
+		return 0, err
+	}
+	receivers, _ := reply.(int64)
+	return receivers, nil
+}
+
+// Subscribe passes the messages published on channel to handle until ctx
+// ends. The subscription has a connection of its own outside the pool and
+// reconnects after failures. Messages published while it is down are lost,
+// so subscribed is called every time the subscription is established.
+func (c *Client) Subscribe(ctx context.Context, channel string, subscribed func(), handle func(message []byte)) {
+	delay := minResubscribeDelay
+	for {
+		established, err := c.subscribeOnce(ctx, channel, subscribed, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		if established {
+			delay = minResubscribeDelay
+		}
+		slog.WarnContext(ctx, "Cache subscription lost, reconnecting", "channel", channel, "error", err, "retry_in", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = min(2*delay, maxResubscribeDelay)
+	}
+}
+
+// subscribeOnce subscribes on a new connection and reads messages until the
+// connection fails or ctx ends. It reports whether the subscription was established.
+func (c *Client) subscribeOnce(ctx context.Context, channel string, subscribed func(), handle func(message []byte)) (bool, error) {
+	cn, err := c.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer cn.Close()
+
+	// Closing the connection interrupts the blocking read below
+	stop := context.AfterFunc(ctx, func() { cn.Close() })
+	defer stop()
+
+	cn.SetDeadline(time.Now().Add(c.options.Timeout))
+	reply, err := cn.do("SUBSCRIBE", channel)
+	if err != nil {
+		return false, err
+	}
+	if kind, _ := pushKind(reply); kind != "subscribe" {
+		return false, fmt.Errorf("unexpected reply to SUBSCRIBE: %v", reply)
+	}
+	cn.SetDeadline(time.Time{})
+	subscribed()
+
+	pinged := false
+	for {
+		cn.SetReadDeadline(time.Now().Add(subscriptionPingInterval))
+		reply, err := ReadReply(cn.r)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() && !pinged {
+			cn.SetWriteDeadline(time.Now().Add(c.options.Timeout))
+			if err := WriteCommand(cn.w, "PING"); err != nil {
+				return true, err
+			}
+			pinged = true
+			continue
+		}
+		if err != nil {
+			return true, err
+		}
+		pinged = false
+
+		if kind, array := pushKind(reply); kind == "message" && len(array) == 3 {
+			if message, ok := array[2].([]byte); ok {
+				handle(message)
+			}
+		}
+	}
+}
+
+// pushKind returns the kind of a message pushed to a subscribed connection,
+// e.g. "subscribe" or "message", with the whole push
+func pushKind(reply any) (string, []any) {
+	array, ok := reply.([]any)
+	if !ok || len(array) == 0 {
+		return "", nil
+	}
+	kind, _ := array[0].([]byte)
+	return string(kind), array
+}
@@ -0,0 +1,480 @@
+// Package redistest provides an in-process server speaking the Redis
+// protocol (RESP) for tests, in the spirit of net/http/httptest. It keeps
+// strings in memory and implements the commands rediscache sends: PING,
+// AUTH, SELECT, GET, SET (with EX and PX), DEL, MGET, SCAN, PUBLISH,
+// SUBSCRIBE, FLUSHALL and QUIT.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/rediscache"
+)
+
+// entry is a stored string with its optional expiry
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// expired reports whether the entry has expired at now
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Server is a RESP server listening on a loopback address
+type Server struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	data        map[string]entry
+	subscribers map[string]map[*client]struct{}
+	clients     map[*client]struct{}
+	commands    map[string]int
+	hooks       map[string]func()
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+// client is a connection to the server
+type client struct {
+	conn net.Conn
+	w    *bufio.Writer
+	// mu serializes replies and published messages
+	mu sync.Mutex
+}
+
+// NewServer starts a server on a free loopback port. Close it when done.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("redistest: failed to listen: %v", err))
+	}
+
+	s := &Server{
+		listener:    listener,
+		data:        make(map[string]entry),
+		subscribers: make(map[string]map[*client]struct{}),
+		clients:     make(map[*client]struct{}),
+		commands:    make(map[string]int),
+		hooks:       make(map[string]func()),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Addr returns the host:port the server listens on
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server and closes every connection
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.clients {
+		c.conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+// CloseClientConnections closes every open connection, as a restarting
+// server would, while still accepting new ones
+func (s *Server) CloseClientConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.conn.Close()
+	}
+}
+
+// Keys returns the stored keys that have not expired, sorted
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	keys := make([]string, 0, len(s.data))
+	for key, e := range s.data {
+		if !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CommandCount returns how many times a command, e.g. "GET", was received
+func (s *Server) CommandCount(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commands[strings.ToUpper(name)]
+}
+
+// BeforeReply makes every later command named name, e.g. "GET", run hook
+// after the command and before its reply is sent, so a test can change the
+// data while a client waits for an outdated reply. A nil hook removes it.
+func (s *Server) BeforeReply(name string, hook func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hook == nil {
+		delete(s.hooks, strings.ToUpper(name))
+		return
+	}
+	s.hooks[strings.ToUpper(name)] = hook
+}
+
+// serve accepts connections until the listener is closed
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &client{conn: conn, w: bufio.NewWriter(conn)}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.clients[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(c)
+	}
+}
+
+// handle reads commands from a connection and answers them
+func (s *Server) handle(c *client) {
+	defer s.wg.Done()
+	defer s.disconnect(c)
+
+	r := bufio.NewReader(c.conn)
+	for {
+		request, err := rediscache.ReadReply(r)
+		if err != nil {
+			return
+		}
+		args, ok := commandArgs(request)
+		if !ok {
+			c.reply(rediscache.Error("ERR Protocol error: expected an array of bulk strings"))
+			return
+		}
+		if !s.exec(c, args) {
+			return
+		}
+	}
+}
+
+// disconnect forgets a closed connection and its subscriptions
+func (s *Server) disconnect(c *client) {
+	c.conn.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, c)
+	for channel, subscribers := range s.subscribers {
+		delete(subscribers, c)
+		if len(subscribers) == 0 {
+			delete(s.subscribers, channel)
+		}
+	}
+}
+
+// commandArgs converts a request into its arguments
+func commandArgs(request any) ([]string, bool) {
+	array, ok := request.([]any)
+	if !ok || len(array) == 0 {
+		return nil, false
+	}
+	args := make([]string, len(array))
+	for i, arg := range array {
+		b, ok := arg.([]byte)
+		if !ok {
+			return nil, false
+		}
+		args[i] = string(b)
+	}
+	return args, true
+}
+
+// exec runs a command and reports whether the connection stays open
+func (s *Server) exec(c *client, args []string) bool {
+	name := strings.ToUpper(args[0])
+	s.mu.Lock()
+	s.commands[name]++
+	hook := s.hooks[name]
+	s.mu.Unlock()
+
+	reply := func(value any) {
+		if hook != nil {
+			hook()
+		}
+		c.reply(value)
+	}
+
+	switch name {
+	case "PING":
+		if len(args) > 1 {
+			reply([]byte(args[1]))
+		} else {
+			reply("PONG")
+		}
+	case "AUTH", "SELECT":
+		if len(args) != 2 {
+			reply(wrongArgs(name))
+		} else {
+			reply("OK")
+		}
+	case "GET":
+		if len(args) != 2 {
+			reply(wrongArgs(name))
+			break
+		}
+		reply(s.get(args[1]))
+	case "SET":
+		reply(s.set(args[1:]))
+	case "DEL":
+		if len(args) < 2 {
+			reply(wrongArgs(name))
+			break
+		}
+		reply(s.del(args[1:]))
+	case "MGET":
+		if len(args) < 2 {
+			reply(wrongArgs(name))
+			break
+		}
+		values := make([]any, len(args)-1)
+		for i, key := range args[1:] {
+			values[i] = s.get(key)
+		}
+		reply(values)
+	case "SCAN":
+		reply(s.scan(args[1:]))
+	case "FLUSHALL":
+		s.mu.Lock()
+		s.data = make(map[string]entry)
+		s.mu.Unlock()
+		reply("OK")
+	case "PUBLISH":
+		if len(args) != 3 {
+			reply(wrongArgs(name))
+			break
+		}
+		reply(s.publish(args[1], args[2]))
+	case "SUBSCRIBE":
+		if len(args) < 2 {
+			reply(wrongArgs(name))
+			break
+		}
+		s.subscribe(c, args[1:])
+	case "QUIT":
+		reply("OK")
+		return false
+	default:
+		reply(rediscache.Error(fmt.Sprintf("ERR unknown command '%s'", args[0])))
+	}
+	return true
+}
+
+// wrongArgs is the error reply to a command with the wrong number of arguments
+func wrongArgs(name string) rediscache.Error {
+	return rediscache.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+}
+
+// get returns the value of key, or nil
+func (s *Server) get(key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, found := s.data[key]
+	if !found {
+		return nil
+	}
+	if e.expired(time.Now()) {
+		delete(s.data, key)
+		return nil
+	}
+	return []byte(e.value)
+}
+
+// set handles SET key value [EX seconds | PX milliseconds]
+func (s *Server) set(args []string) any {
+	if len(args) != 2 && len(args) != 4 {
+		return wrongArgs("SET")
+	}
+
+	e := entry{value: args[1]}
+	if len(args) == 4 {
+		n, err := strconv.ParseInt(args[3], 10, 64)
+		if err != nil || n <= 0 {
+			return rediscache.Error("ERR invalid expire time in 'set' command")
+		}
+		switch strings.ToUpper(args[2]) {
+		case "EX":
+			e.expiresAt = time.Now().Add(time.Duration(n) * time.Second)
+		case "PX":
+			e.expiresAt = time.Now().Add(time.Duration(n) * time.Millisecond)
+		default:
+			return rediscache.Error("ERR syntax error")
+		}
+	}
+
+	s.mu.Lock()
+	s.data[args[0]] = e
+	s.mu.Unlock()
+	return "OK"
+}
+
+// del deletes keys and returns how many existed
+func (s *Server) del(keys []string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for _, key := range keys {
+		if e, found := s.data[key]; found {
+			if !e.expired(now) {
+				deleted++
+			}
+			delete(s.data, key)
+		}
+	}
+	return deleted
+}
+
+// scan handles SCAN cursor [MATCH pattern] [COUNT count]. The cursor is an
+// offset into the sorted keys.
+func (s *Server) scan(args []string) any {
+	if len(args) == 0 {
+		return wrongArgs("SCAN")
+	}
+	cursor, err := strconv.Atoi(args[0])
+	if err != nil || cursor < 0 {
+		return rediscache.Error("ERR invalid cursor")
+	}
+
+	pattern, count := "*", 10
+	for i := 1; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return rediscache.Error("ERR syntax error")
+		}
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			if count, err = strconv.Atoi(args[i+1]); err != nil || count <= 0 {
+				return rediscache.Error("ERR syntax error")
+			}
+		default:
+			return rediscache.Error("ERR syntax error")
+		}
+	}
+
+	keys := s.Keys()
+	end := min(cursor+count, len(keys))
+	matched := []any{}
+	for _, key := range keys[min(cursor, len(keys)):end] {
+		// path.Match understands the same escapes and wildcards for keys
+		// without a slash, which is all the tests use
+		if ok, _ := path.Match(pattern, key); ok {
+			matched = append(matched, []byte(key))
+		}
+	}
+	next := end
+	if next >= len(keys) {
+		next = 0
+	}
+	return []any{[]byte(strconv.Itoa(next)), matched}
+}
+
+// publish sends message to the subscribers of channel
+func (s *Server) publish(channel, message string) int64 {
+	s.mu.Lock()
+	subscribers := make([]*client, 0, len(s.subscribers[channel]))
+	for c := range s.subscribers[channel] {
+		subscribers = append(subscribers, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range subscribers {
+		c.reply([]any{[]byte("message"), []byte(channel), []byte(message)})
+	}
+	return int64(len(subscribers))
+}
+
+// subscribe adds c to the subscribers of channels and confirms each one
+func (s *Server) subscribe(c *client, channels []string) {
+	s.mu.Lock()
+	count := 0
+	for _, subscribers := range s.subscribers {
+		if _, ok := subscribers[c]; ok {
+			count++
+		}
+	}
+	for _, channel := range channels {
+		if s.subscribers[channel] == nil {
+			s.subscribers[channel] = make(map[*client]struct{})
+		}
+		if _, ok := s.subscribers[channel][c]; !ok {
+			s.subscribers[channel][c] = struct{}{}
+			count++
+		}
+	}
+	s.mu.Unlock()
+
+	for _, channel := range channels {
+		c.reply([]any{[]byte("subscribe"), []byte(channel), int64(count)})
+	}
+}
+
+// reply writes a value as RESP
+func (c *client) reply(value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeValue(c.w, value)
+	c.w.Flush()
+}
+
+// writeValue encodes strings as simple strings, []byte as bulk strings and
+// nil as a null bulk string
+func writeValue(w *bufio.Writer, value any) {
+	switch v := value.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case string:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case rediscache.Error:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case []byte:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []any:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeValue(w, item)
+		}
+	default:
+		panic(errors.New("redistest: cannot encode reply"))
+	}
+}
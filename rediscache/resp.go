@@ -0,0 +1,111 @@
+package rediscache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxBulkLength bounds the size of a single reply so a corrupt stream cannot
+// make the reader allocate without limit
+const maxBulkLength = 512 << 20
+
+// errProtocol is returned for replies that are not valid RESP
+var errProtocol = errors.New("resp: protocol error")
+
+// Error is an error reply sent by the server, e.g. for an unknown command.
+// The connection stays usable after an Error.
+type Error string
+
+// Error implements the error interface
+func (e Error) Error() string {
+	return string(e)
+}
+
+// WriteCommand writes args as a RESP array of bulk strings
+func WriteCommand(w *bufio.Writer, args ...string) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return w.Flush()
+}
+
+// ReadReply reads one RESP value. Simple strings are returned as string,
+// integers as int64, bulk strings as []byte, arrays as []any and error
+// replies as Error; null bulk strings and arrays are returned as nil.
+func ReadReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errProtocol
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return nil, errProtocol
+		}
+		return n, nil
+	case '$':
+		n, err := parseLength(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		bulk := make([]byte, n+2)
+		if _, err := io.ReadFull(r, bulk); err != nil {
+			return nil, err
+		}
+		if bulk[n] != '\r' || bulk[n+1] != '\n' {
+			return nil, errProtocol
+		}
+		return bulk[:n], nil
+	case '*':
+		n, err := parseLength(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		array := make([]any, n)
+		for i := range array {
+			if array[i], err = ReadReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	default:
+		return nil, errProtocol
+	}
+}
+
+// readLine reads a line without its CRLF terminator
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return nil, errProtocol
+		}
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+	return line[:len(line)-2], nil
+}
+
+// parseLength parses the length of a bulk string or array; -1 means null
+func parseLength(b []byte) (int, error) {
+	n, err := strconv.Atoi(string(b))
+	if err != nil || n < -1 || n > maxBulkLength {
+		return 0, errProtocol
+	}
+	return n, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jsonfile"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// ErrAPIKeyNotFound is returned when an API key does not exist; it matches ErrNotFound
+var ErrAPIKeyNotFound = fmt.Errorf("api key %w", ErrNotFound)
+
+// APIKeyRepository defines the interface for API key storage.
+// Keys are looked up by the hash of their secret; the secret is never stored.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id string) (*models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	// List returns every key ordered by creation time
+	List(ctx context.Context) ([]*models.APIKey, error)
+	// Revoke marks the key as revoked at the given time; revoking a revoked key keeps the first time
+	Revoke(ctx context.Context, id string, at time.Time) (*models.APIKey, error)
+}
+
+// APIKeyRepositoryMemory keeps API keys in memory. When opened with
+// OpenAPIKeyRepositoryFile every change is also written to a JSON file.
+type APIKeyRepositoryMemory struct {
+	keys map[string]*models.APIKey
+	path string
+	mu   sync.RWMutex
+}
+
+// storedAPIKey is the on-disk form of a key; unlike the API form it includes the hash
+type storedAPIKey struct {
+	*models.APIKey
+	Hash string `json:"hash"`
+}
+
+// apiKeyFileData is the on-disk layout of the API key file
+type apiKeyFileData struct {
+	Keys []storedAPIKey `json:"keys"`
+}
+
+// NewAPIKeyRepositoryMemory creates an empty in-memory API key repository
+func NewAPIKeyRepositoryMemory() *APIKeyRepositoryMemory {
+	return &APIKeyRepositoryMemory{
+		keys: make(map[string]*models.APIKey),
+	}
+}
+
+// OpenAPIKeyRepositoryFile loads the API keys stored at path, creating its directory if needed
+func OpenAPIKeyRepositoryFile(path string) (*APIKeyRepositoryMemory, error) {
+	var stored apiKeyFileData
+	if err := jsonfile.Load(path, &stored); err != nil {
+		return nil, fmt.Errorf("load api key file: %w", err)
+	}
+
+	repo := NewAPIKeyRepositoryMemory()
+	repo.path = path
+	for _, s := range stored.Keys {
+		if s.APIKey == nil {
+			continue
+		}
+		s.APIKey.Hash = s.Hash
+		repo.keys[s.ID] = s.APIKey
+	}
+
+	return repo, nil
+}
+
+// Create stores a new key
+func (r *APIKeyRepositoryMemory) Create(ctx context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return fmt.Errorf("api key %s already exists", key.ID)
+	}
+	return jsonfile.Put(r.keys, key.ID, key.Clone(), r.persist)
+}
+
+// GetByID retrieves a key by ID
+func (r *APIKeyRepositoryMemory) GetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key.Clone(), nil
+}
+
+// GetByHash retrieves a key by the hash of its secret
+func (r *APIKeyRepositoryMemory) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return key.Clone(), nil
+		}
+	}
+	return nil, ErrAPIKeyNotFound
+}
+
+// List returns all keys ordered by creation time
+func (r *APIKeyRepositoryMemory) List(ctx context.Context) ([]*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*models.APIKey, 0, len(r.keys))
+	for _, key := range r.sortedKeys() {
+		keys = append(keys, key.Clone())
+	}
+	return keys, nil
+}
+
+// Revoke marks a key as revoked
+func (r *APIKeyRepositoryMemory) Revoke(ctx context.Context, id string, at time.Time) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, ErrAPIKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return key.Clone(), nil
+	}
+
+	revoked := key.Clone()
+	revoked.RevokedAt = &at
+	if err := jsonfile.Put(r.keys, id, revoked, r.persist); err != nil {
+		return nil, err
+	}
+
+	return revoked.Clone(), nil
+}
+
+// sortedKeys returns the stored keys ordered by creation time, then ID
+func (r *APIKeyRepositoryMemory) sortedKeys() []*models.APIKey {
+	keys := make([]*models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// persist writes all keys to the file, if any; the caller must hold the write lock
+func (r *APIKeyRepositoryMemory) persist() error {
+	if r.path == "" {
+		return nil
+	}
+
+	stored := apiKeyFileData{Keys: make([]storedAPIKey, 0, len(r.keys))}
+	for _, key := range r.sortedKeys() {
+		stored.Keys = append(stored.Keys, storedAPIKey{APIKey: key, Hash: key.Hash})
+	}
+	return jsonfile.Save(r.path, stored)
+}
@@ -0,0 +1,136 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAPIKey(id string, createdAt time.Time) *models.APIKey {
+	return &models.APIKey{
+		ID:        id,
+		Name:      "key " + id,
+		Prefix:    "bk_" + id,
+		Hash:      "hash-" + id,
+		OwnerID:   123,
+		Roles:     []string{"customer"},
+		Scopes:    []string{"bookings:read", "bookings:write"},
+		CreatedAt: createdAt,
+	}
+}
+
+// testAPIKeyRepository checks the behaviour shared by every APIKeyRepository implementation
+func testAPIKeyRepository(t *testing.T, repo repository.APIKeyRepository) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+	expiresAt := now.Add(time.Hour)
+
+	second := newAPIKey("b", now.Add(time.Second))
+	second.ExpiresAt = &expiresAt
+	require.NoError(t, repo.Create(ctx, second))
+	require.NoError(t, repo.Create(ctx, newAPIKey("a", now)))
+
+	t.Run("get by hash", func(t *testing.T) {
+		// Execute
+		key, err := repo.GetByHash(ctx, "hash-b")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "b", key.ID)
+		assert.Equal(t, "hash-b", key.Hash)
+		assert.Equal(t, int64(123), key.OwnerID)
+		assert.Equal(t, []string{"customer"}, key.Roles)
+		assert.Equal(t, []string{"bookings:read", "bookings:write"}, key.Scopes)
+		require.NotNil(t, key.ExpiresAt)
+		assert.True(t, expiresAt.Equal(*key.ExpiresAt))
+		assert.Nil(t, key.RevokedAt)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		// Execute
+		_, hashErr := repo.GetByHash(ctx, "missing")
+		_, idErr := repo.GetByID(ctx, "missing")
+		_, revokeErr := repo.Revoke(ctx, "missing", now)
+
+		// Assert
+		assert.ErrorIs(t, hashErr, repository.ErrAPIKeyNotFound)
+		assert.ErrorIs(t, idErr, repository.ErrAPIKeyNotFound)
+		assert.ErrorIs(t, revokeErr, repository.ErrAPIKeyNotFound)
+	})
+
+	t.Run("list in creation order", func(t *testing.T) {
+		// Execute
+		keys, err := repo.List(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "a", keys[0].ID)
+		assert.Equal(t, "b", keys[1].ID)
+	})
+
+	t.Run("revoke keeps the first time", func(t *testing.T) {
+		// Execute
+		revoked, err := repo.Revoke(ctx, "a", now)
+		require.NoError(t, err)
+		again, err := repo.Revoke(ctx, "a", now.Add(time.Minute))
+		require.NoError(t, err)
+		stored, err := repo.GetByID(ctx, "a")
+		require.NoError(t, err)
+
+		// Assert
+		require.NotNil(t, revoked.RevokedAt)
+		assert.True(t, now.Equal(*again.RevokedAt))
+		assert.True(t, now.Equal(*stored.RevokedAt))
+		assert.False(t, stored.Active(now))
+	})
+}
+
+func TestAPIKeyRepository_Memory(t *testing.T) {
+	testAPIKeyRepository(t, repository.NewAPIKeyRepositoryMemory())
+}
+
+func TestAPIKeyRepository_File(t *testing.T) {
+	repo, err := repository.OpenAPIKeyRepositoryFile(filepath.Join(t.TempDir(), "api_keys.json"))
+	require.NoError(t, err)
+
+	testAPIKeyRepository(t, repo)
+}
+
+func TestAPIKeyRepository_SQL(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "bookings.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	require.NoError(t, repository.Migrate(context.Background(), db))
+
+	testAPIKeyRepository(t, repository.NewAPIKeyRepositorySQL(db))
+}
+
+func TestAPIKeyRepositoryFile_PersistsAcrossReopen(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "api_keys.json")
+	repo, err := repository.OpenAPIKeyRepositoryFile(path)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, newAPIKey("a", time.Now())))
+	_, err = repo.Revoke(ctx, "a", time.Now())
+	require.NoError(t, err)
+
+	// Act
+	reopened, err := repository.OpenAPIKeyRepositoryFile(path)
+	require.NoError(t, err)
+	key, err := reopened.GetByHash(ctx, "hash-a")
+
+	// Assert - the hash is kept on disk even though it is hidden from the API
+	require.NoError(t, err)
+	assert.Equal(t, "a", key.ID)
+	assert.NotNil(t, key.RevokedAt)
+}
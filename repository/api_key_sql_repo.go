@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// apiKeyColumns lists the columns read by every API key query, in scan order
+const apiKeyColumns = `id, name, prefix, hash, owner_id, roles, scopes, created_at, expires_at, revoked_at`
+
+// APIKeyRepositorySQL is a database/sql implementation of APIKeyRepository
+type APIKeyRepositorySQL struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepositorySQL creates a new instance of APIKeyRepositorySQL.
+// The schema must already be up to date; call Migrate before using the repository.
+func NewAPIKeyRepositorySQL(db *sql.DB) *APIKeyRepositorySQL {
+	return &APIKeyRepositorySQL{
+		db: db,
+	}
+}
+
+// Create stores a new key
+func (r *APIKeyRepositorySQL) Create(ctx context.Context, key *models.APIKey) error {
+	roles, err := encodeStrings(key.Roles)
+	if err != nil {
+		return err
+	}
+	scopes, err := encodeStrings(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO api_keys (id, name, prefix, hash, owner_id, roles, scopes, created_at, expires_at, revoked_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.ID,
+		key.Name,
+		key.Prefix,
+		key.Hash,
+		key.OwnerID,
+		roles,
+		scopes,
+		key.CreatedAt.UnixNano(),
+		encodeTimePtr(key.ExpiresAt),
+		encodeTimePtr(key.RevokedAt),
+	)
+	return sqlError(err)
+}
+
+// GetByID retrieves a key by ID
+func (r *APIKeyRepositorySQL) GetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	return r.getOne(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = ?`, id)
+}
+
+// GetByHash retrieves a key by the hash of its secret
+func (r *APIKeyRepositorySQL) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	return r.getOne(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE hash = ?`, hash)
+}
+
+// List returns all keys ordered by creation time
+func (r *APIKeyRepositorySQL) List(ctx context.Context) ([]*models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, sqlError(err)
+	}
+	defer rows.Close()
+
+	keys := make([]*models.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, sqlError(err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sqlError(err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks a key as revoked
+func (r *APIKeyRepositorySQL) Revoke(ctx context.Context, id string, at time.Time) (*models.APIKey, error) {
+	var key *models.APIKey
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+			at.UnixNano(), id,
+		); err != nil {
+			return err
+		}
+
+		var err error
+		key, err = scanAPIKey(tx.QueryRowContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = ?`, id))
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, sqlError(err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepositorySQL) getOne(ctx context.Context, query string, arg interface{}) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, sqlError(err)
+	}
+
+	return key, nil
+}
+
+// scanAPIKey reads a key from a row selected with apiKeyColumns
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var (
+		key       models.APIKey
+		roles     string
+		scopes    string
+		createdAt int64
+		expiresAt sql.NullInt64
+		revokedAt sql.NullInt64
+	)
+
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &key.OwnerID, &roles, &scopes,
+		&createdAt, &expiresAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(roles), &key.Roles); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, err
+	}
+	key.CreatedAt = time.Unix(0, createdAt)
+	key.ExpiresAt = decodeTimePtr(expiresAt)
+	key.RevokedAt = decodeTimePtr(revokedAt)
+
+	return &key, nil
+}
+
+// encodeStrings stores a list as a JSON array; nil is stored as an empty array
+func encodeStrings(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+// encodeTimePtr stores an optional time as Unix nanoseconds, or NULL when unset
+func encodeTimePtr(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+// decodeTimePtr reverses encodeTimePtr
+func decodeTimePtr(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.Unix(0, value.Int64)
+	return &t
+}
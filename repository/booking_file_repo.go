@@ -0,0 +1,480 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/atomicfile"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+const (
+	walFileName      = "bookings.wal"
+	snapshotFileName = "bookings.snapshot"
+
+	// walHeaderSize is the size of the length + CRC32 prefix written before every record
+	walHeaderSize = 8
+
+	// maxWALRecordSize guards replay against allocating huge buffers for a corrupt length
+	maxWALRecordSize = 1 << 20
+
+	defaultSnapshotEvery = 1000
+
+	// dataFileMode is the permission of the log and the snapshot, which hold the same data
+	dataFileMode = 0o600
+)
+
+const (
+	walOpCreate = "create"
+	walOpUpdate = "update"
+)
+
+// walRecord is a single entry of the write-ahead log.
+// Records carry the full booking so replaying one twice is harmless.
+type walRecord struct {
+	Op      string          `json:"op"`
+	Booking *models.Booking `json:"booking"`
+}
+
+// bookingSnapshot is the on-disk representation of a compacted store
+type bookingSnapshot struct {
+	NextID   int64             `json:"next_id"`
+	Bookings []*models.Booking `json:"bookings"`
+}
+
+// FileRepositoryOptions configures BookingRepositoryFile
+type FileRepositoryOptions struct {
+	// SnapshotEvery is the number of log records after which the log is compacted into a snapshot
+	SnapshotEvery int
+	// NoSync skips fsync after each write. Faster, but recent writes may be lost on power failure.
+	NoSync bool
+}
+
+// walFile is the part of *os.File the write-ahead log uses
+type walFile interface {
+	io.ReadWriteSeeker
+	Sync() error
+	Truncate(size int64) error
+	Stat() (os.FileInfo, error)
+	Name() string
+	Close() error
+}
+
+// BookingRepositoryFile is an in-memory BookingRepository made durable by an
+// append-only write-ahead log and periodic snapshots stored in a directory
+type BookingRepositoryFile struct {
+	dir      string
+	options  FileRepositoryOptions
+	bookings map[int64]*models.Booking
+	mutex    sync.RWMutex
+	nextID   int64
+	wal      walFile
+	// walRecords counts records written since the last snapshot
+	walRecords int
+	// broken is set when a failed write could not be removed from the log;
+	// appending after it would put records where replay never reaches them
+	broken error
+}
+
+// OpenBookingRepositoryFile opens (or creates) a file-backed repository in dir.
+// The latest snapshot is loaded and the write-ahead log replayed on top of it.
+// A truncated or corrupt tail of the log, as left by a crash mid-write, is discarded.
+func OpenBookingRepositoryFile(dir string, options FileRepositoryOptions) (*BookingRepositoryFile, error) {
+	if options.SnapshotEvery <= 0 {
+		options.SnapshotEvery = defaultSnapshotEvery
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create data directory: %w", err)
+	}
+
+	repo := &BookingRepositoryFile{
+		dir:      dir,
+		options:  options,
+		bookings: make(map[int64]*models.Booking),
+		nextID:   1,
+	}
+
+	if err := repo.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_RDWR|os.O_CREATE, dataFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("open write-ahead log: %w", err)
+	}
+	// Logs created before the modes matched are tightened too
+	if err := wal.Chmod(dataFileMode); err != nil {
+		wal.Close()
+		return nil, fmt.Errorf("set write-ahead log permissions: %w", err)
+	}
+	repo.wal = wal
+
+	if err := repo.replayWAL(); err != nil {
+		wal.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Create creates a new booking
+func (r *BookingRepositoryFile) Create(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	newBooking := &models.Booking{
+		ID:        r.nextID,
+		UserID:    booking.UserID,
+		ServiceID: booking.ServiceID,
+		Price:     booking.Price,
+		Status:    models.BookingStatusPending,
+		CreatedAt: booking.CreatedAt,
+		UpdatedAt: booking.UpdatedAt,
+		Version:   1,
+		ExpiresAt: booking.ExpiresAt,
+	}
+
+	if err := r.appendLocked(ctx, walRecord{Op: walOpCreate, Booking: newBooking}); err != nil {
+		return nil, err
+	}
+
+	booking.ID = newBooking.ID
+	booking.Status = newBooking.Status
+	booking.Version = newBooking.Version
+
+	return newBooking.Clone(), nil
+}
+
+// GetByID retrieves a booking by ID
+func (r *BookingRepositoryFile) GetByID(ctx context.Context, id int64) (*models.Booking, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	booking, exists := r.bookings[id]
+	if !exists {
+		return nil, ErrBookingNotFound
+	}
+
+	return booking.Clone(), nil
+}
+
+// GetAll retrieves all bookings
+func (r *BookingRepositoryFile) GetAll(ctx context.Context) ([]*models.Booking, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	bookings := make([]*models.Booking, 0, len(r.bookings))
+	for _, booking := range r.bookings {
+		bookings = append(bookings, booking.Clone())
+	}
+
+	return bookings, nil
+}
+
+// Query returns a page of bookings matching the query
+func (r *BookingRepositoryFile) Query(ctx context.Context, query BookingQuery) (*BookingPage, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	page := queryBookings(r.bookings, query)
+	for i, booking := range page.Bookings {
+		page.Bookings[i] = booking.Clone()
+	}
+
+	return page, nil
+}
+
+// Update updates a booking
+func (r *BookingRepositoryFile) Update(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.bookings[booking.ID]
+	if !exists {
+		return nil, ErrBookingNotFound
+	}
+
+	// Reject updates based on a stale read
+	if booking.Version != existing.Version {
+		return nil, &VersionConflictError{
+			ID:              booking.ID,
+			ExpectedVersion: booking.Version,
+			CurrentVersion:  existing.Version,
+		}
+	}
+
+	// Update the booking while preserving creation time
+	booking.CreatedAt = existing.CreatedAt
+	updatedBooking := booking.Clone()
+	updatedBooking.Version = existing.Version + 1
+
+	if err := r.appendLocked(ctx, walRecord{Op: walOpUpdate, Booking: updatedBooking}); err != nil {
+		return nil, err
+	}
+
+	return updatedBooking.Clone(), nil
+}
+
+// Snapshot compacts the write-ahead log into a new snapshot
+func (r *BookingRepositoryFile) Snapshot() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.snapshotLocked()
+}
+
+// Ping checks that the write-ahead log is open and still on disk
+func (r *BookingRepositoryFile) Ping(ctx context.Context) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.wal == nil {
+		return errClosed
+	}
+	if r.broken != nil {
+		return r.broken
+	}
+	if _, err := os.Stat(r.wal.Name()); err != nil {
+		return fmt.Errorf("write-ahead log: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the write-ahead log
+func (r *BookingRepositoryFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.wal == nil {
+		return nil
+	}
+
+	err := r.wal.Sync()
+	if closeErr := r.wal.Close(); err == nil {
+		err = closeErr
+	}
+	r.wal = nil
+
+	return err
+}
+
+// appendLocked durably logs a record and then applies it to the in-memory map.
+// A record that fails to be written or synced is cut from the log again, so
+// it is neither replayed after being reported as failed nor followed by
+// records that replay would never reach. The caller must hold the write lock.
+func (r *BookingRepositoryFile) appendLocked(ctx context.Context, record walRecord) error {
+	if r.wal == nil {
+		return errClosed
+	}
+	if r.broken != nil {
+		return r.broken
+	}
+
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, walHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload))
+	copy(frame[walHeaderSize:], payload)
+
+	offset, err := r.wal.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("locate end of write-ahead log: %w", err)
+	}
+	if err := r.writeFrame(frame); err != nil {
+		r.rollbackLocked(ctx, offset)
+		return err
+	}
+
+	r.apply(record)
+	r.walRecords++
+
+	if r.walRecords >= r.options.SnapshotEvery {
+		// The record is already durable in the log, so a failed compaction only delays it
+		if err := r.snapshotLocked(); err != nil {
+			slog.ErrorContext(ctx, "Error compacting booking write-ahead log", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// writeFrame appends a framed record to the log and syncs it
+func (r *BookingRepositoryFile) writeFrame(frame []byte) error {
+	if _, err := r.wal.Write(frame); err != nil {
+		return fmt.Errorf("write to write-ahead log: %w", err)
+	}
+	if !r.options.NoSync {
+		if err := r.wal.Sync(); err != nil {
+			return fmt.Errorf("sync write-ahead log: %w", err)
+		}
+	}
+	return nil
+}
+
+// rollbackLocked cuts the log back to offset after a failed append. If that
+// fails too, the repository refuses further writes.
+func (r *BookingRepositoryFile) rollbackLocked(ctx context.Context, offset int64) {
+	err := r.wal.Truncate(offset)
+	if err == nil {
+		_, err = r.wal.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		r.broken = fmt.Errorf("write-ahead log unusable after a failed write: %w", err)
+		slog.ErrorContext(ctx, "Booking repository stops accepting writes", "error", r.broken)
+	}
+}
+
+// apply mutates the in-memory state according to a log record
+func (r *BookingRepositoryFile) apply(record walRecord) {
+	booking := record.Booking.Clone()
+	r.bookings[booking.ID] = booking
+	if booking.ID >= r.nextID {
+		r.nextID = booking.ID + 1
+	}
+}
+
+// snapshotLocked writes the current state to a snapshot file and truncates the log.
+// The snapshot is written to a temporary file and renamed into place so a crash never
+// leaves a partial snapshot. If the process dies between the rename and the truncate,
+// the old log is replayed over the new snapshot, which is safe because records are idempotent.
+func (r *BookingRepositoryFile) snapshotLocked() error {
+	snapshot := bookingSnapshot{
+		NextID:   r.nextID,
+		Bookings: make([]*models.Booking, 0, len(r.bookings)),
+	}
+	for _, booking := range r.bookings {
+		snapshot.Bookings = append(snapshot.Bookings, booking)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err := atomicfile.Write(filepath.Join(r.dir, snapshotFileName), data, dataFileMode); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+
+	if err := r.wal.Truncate(0); err != nil {
+		return fmt.Errorf("truncate write-ahead log: %w", err)
+	}
+	if _, err := r.wal.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind write-ahead log: %w", err)
+	}
+	r.walRecords = 0
+
+	return nil
+}
+
+// loadSnapshot restores state from the snapshot file if one exists
+func (r *BookingRepositoryFile) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(r.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var snapshot bookingSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	for _, booking := range snapshot.Bookings {
+		r.bookings[booking.ID] = booking
+	}
+	if snapshot.NextID > r.nextID {
+		r.nextID = snapshot.NextID
+	}
+
+	return nil
+}
+
+// replayWAL applies every intact record in the log. Replay stops at the first
+// truncated or corrupt record and the log is cut back to the last good offset,
+// so new records are never appended after garbage.
+func (r *BookingRepositoryFile) replayWAL() error {
+	if _, err := r.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var offset int64
+	header := make([]byte, walHeaderSize)
+	for {
+		record, size, err := readWALRecord(r.wal, header)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			info, statErr := r.wal.Stat()
+			if statErr != nil {
+				return statErr
+			}
+			slog.Warn("Discarding damaged booking write-ahead log",
+				"bytes", info.Size()-offset, "offset", offset, "error", err)
+
+			if err := r.wal.Truncate(offset); err != nil {
+				return fmt.Errorf("truncate damaged write-ahead log: %w", err)
+			}
+			break
+		}
+
+		r.apply(record)
+		r.walRecords++
+		offset += size
+	}
+
+	_, err := r.wal.Seek(offset, io.SeekStart)
+	return err
+}
+
+// readWALRecord reads a single framed record, returning its total size on disk.
+// io.EOF is only returned at a clean record boundary.
+func readWALRecord(reader io.Reader, header []byte) (walRecord, int64, error) {
+	var record walRecord
+
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if err == io.EOF {
+			return record, 0, io.EOF
+		}
+		return record, 0, fmt.Errorf("truncated record header: %w", err)
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	checksum := binary.BigEndian.Uint32(header[4:8])
+	if length == 0 || length > maxWALRecordSize {
+		return record, 0, fmt.Errorf("invalid record length %d", length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return record, 0, fmt.Errorf("truncated record payload: %w", err)
+	}
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return record, 0, errors.New("record checksum mismatch")
+	}
+
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return record, 0, fmt.Errorf("decode record: %w", err)
+	}
+	if record.Booking == nil {
+		return record, 0, errors.New("record has no booking")
+	}
+
+	return record, int64(walHeaderSize + len(payload)), nil
+}
@@ -0,0 +1,358 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BookingFileRepositoryTestSuite struct {
+	suite.Suite
+	dir  string
+	repo *repository.BookingRepositoryFile
+}
+
+func (suite *BookingFileRepositoryTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+	suite.repo = suite.open(repository.FileRepositoryOptions{})
+}
+
+func (suite *BookingFileRepositoryTestSuite) TearDownTest() {
+	suite.repo.Close()
+}
+
+func (suite *BookingFileRepositoryTestSuite) open(options repository.FileRepositoryOptions) *repository.BookingRepositoryFile {
+	repo, err := repository.OpenBookingRepositoryFile(suite.dir, options)
+	require.NoError(suite.T(), err)
+	return repo
+}
+
+// reopen simulates a process restart
+func (suite *BookingFileRepositoryTestSuite) reopen(options repository.FileRepositoryOptions) {
+	require.NoError(suite.T(), suite.repo.Close())
+	suite.repo = suite.open(options)
+}
+
+func (suite *BookingFileRepositoryTestSuite) createBooking(price float64) *models.Booking {
+	now := time.Now()
+	booking, err := suite.repo.Create(context.Background(), &models.Booking{
+		UserID:    123,
+		ServiceID: 456,
+		Price:     price,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	require.NoError(suite.T(), err)
+	return booking
+}
+
+func (suite *BookingFileRepositoryTestSuite) walPath() string {
+	return filepath.Join(suite.dir, "bookings.wal")
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestCreateAndGet() {
+	// Execute
+	created := suite.createBooking(25000)
+	result, err := suite.repo.GetByID(context.Background(), created.ID)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), created.ID)
+	assert.Equal(suite.T(), 25000.0, result.Price)
+	assert.Equal(suite.T(), models.BookingStatusPending, result.Status)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestGetByID_NonExistingBooking() {
+	// Execute
+	result, err := suite.repo.GetByID(context.Background(), 999)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrBookingNotFound)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestUpdate_NonExistingBooking() {
+	// Execute
+	result, err := suite.repo.Update(context.Background(), &models.Booking{ID: 999})
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrBookingNotFound)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestUpdate_StaleVersionIsNotLogged() {
+	// Setup
+	ctx := context.Background()
+	created := suite.createBooking(1000)
+	stale := *created
+	created.Status = models.BookingStatusCanceled
+	_, err := suite.repo.Update(ctx, created)
+	require.NoError(suite.T(), err)
+
+	// Execute
+	stale.Status = models.BookingStatusConfirmed
+	_, err = suite.repo.Update(ctx, &stale)
+
+	// Assert - the rejected write must not reappear after replay
+	assert.ErrorIs(suite.T(), err, repository.ErrVersionConflict)
+
+	suite.reopen(repository.FileRepositoryOptions{})
+	replayed, err := suite.repo.GetByID(ctx, created.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.BookingStatusCanceled, replayed.Status)
+	assert.Equal(suite.T(), int64(2), replayed.Version)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestReturnedBookingsAreCopies() {
+	// Setup
+	ctx := context.Background()
+	created := suite.createBooking(1000)
+	created.Status = models.BookingStatusConfirmed
+	updated, err := suite.repo.Update(ctx, created)
+	require.NoError(suite.T(), err)
+
+	// Execute - changes to returned bookings must not reach the stored state
+	created.Price = 1
+	updated.Status = models.BookingStatusCanceled
+	updated.Version = 99
+
+	// Assert
+	stored, err := suite.repo.GetByID(ctx, created.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1000.0, stored.Price)
+	assert.Equal(suite.T(), models.BookingStatusConfirmed, stored.Status)
+	assert.Equal(suite.T(), int64(2), stored.Version)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestFailedWriteIsCutFromLog() {
+	// Setup
+	ctx := context.Background()
+	first := suite.createBooking(1000)
+	repository.FailNextWALWrite(suite.repo, 10, nil)
+
+	// Execute - the failed create leaves part of its record behind, then another write succeeds
+	_, err := suite.repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 1, Price: 2000})
+	require.Error(suite.T(), err)
+	second := suite.createBooking(3000)
+
+	// Assert - the later write survives a reopen and the failed one is gone
+	suite.reopen(repository.FileRepositoryOptions{})
+	all, err := suite.repo.GetAll(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), all, 2)
+	for _, id := range []int64{first.ID, second.ID} {
+		_, err := suite.repo.GetByID(ctx, id)
+		assert.NoError(suite.T(), err)
+	}
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestFailedRollbackStopsWrites() {
+	// Setup
+	ctx := context.Background()
+	created := suite.createBooking(1000)
+	repository.FailNextWALWrite(suite.repo, 10, errors.New("disk gone"))
+	_, err := suite.repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 1, Price: 2000})
+	require.Error(suite.T(), err)
+
+	// Execute
+	_, createErr := suite.repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 1, Price: 3000})
+	created.Status = models.BookingStatusCanceled
+	_, updateErr := suite.repo.Update(ctx, created)
+
+	// Assert - nothing is appended after the partial record, and readiness reports it
+	assert.ErrorContains(suite.T(), createErr, "unusable")
+	assert.ErrorContains(suite.T(), updateErr, "unusable")
+	assert.Error(suite.T(), suite.repo.Ping(ctx))
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestDataFilesShareMode() {
+	// Setup
+	suite.createBooking(1000)
+
+	// Execute
+	require.NoError(suite.T(), suite.repo.Snapshot())
+
+	// Assert
+	for _, name := range []string{"bookings.wal", "bookings.snapshot"} {
+		info, err := os.Stat(filepath.Join(suite.dir, name))
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), os.FileMode(0o600), info.Mode().Perm(), name)
+	}
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestReplayAfterRestart() {
+	// Setup
+	ctx := context.Background()
+	first := suite.createBooking(10000)
+	second := suite.createBooking(60000)
+	second.Status = models.BookingStatusConfirmed
+	second.UpdatedAt = time.Now()
+	_, err := suite.repo.Update(ctx, second)
+	require.NoError(suite.T(), err)
+
+	// Execute
+	suite.reopen(repository.FileRepositoryOptions{})
+
+	// Assert
+	bookings, err := suite.repo.GetAll(ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(bookings))
+
+	replayed, err := suite.repo.GetByID(ctx, second.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.BookingStatusConfirmed, replayed.Status)
+	assert.True(suite.T(), first.CreatedAt.Equal(bookings[0].CreatedAt) || first.CreatedAt.Equal(bookings[1].CreatedAt))
+
+	// IDs must continue after the replayed ones
+	third := suite.createBooking(500)
+	assert.Equal(suite.T(), int64(3), third.ID)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestReplayKeepsExpiresAt() {
+	// Setup
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+	created, err := suite.repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 2, Price: 100, ExpiresAt: expiresAt})
+	require.NoError(suite.T(), err)
+
+	// Execute
+	suite.reopen(repository.FileRepositoryOptions{})
+
+	// Assert
+	replayed, err := suite.repo.GetByID(ctx, created.ID)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), expiresAt.Equal(replayed.ExpiresAt))
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestSnapshotCompactsLog() {
+	// Setup - compact after every 3 records
+	options := repository.FileRepositoryOptions{SnapshotEvery: 3}
+	suite.reopen(options)
+
+	// Execute
+	for i := 1; i <= 4; i++ {
+		suite.createBooking(float64(i * 1000))
+	}
+
+	// Assert - three records were compacted, one remains in the log
+	_, err := os.Stat(filepath.Join(suite.dir, "bookings.snapshot"))
+	assert.NoError(suite.T(), err)
+
+	info, err := os.Stat(suite.walPath())
+	assert.NoError(suite.T(), err)
+	assert.Greater(suite.T(), info.Size(), int64(0))
+
+	suite.reopen(options)
+	bookings, err := suite.repo.GetAll(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, len(bookings))
+	assert.Equal(suite.T(), int64(5), suite.createBooking(1).ID)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestSnapshot_ExplicitCompaction() {
+	// Setup
+	suite.createBooking(1000)
+	suite.createBooking(2000)
+
+	// Execute
+	err := suite.repo.Snapshot()
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	info, err := os.Stat(suite.walPath())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), info.Size())
+
+	suite.reopen(repository.FileRepositoryOptions{})
+	bookings, err := suite.repo.GetAll(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(bookings))
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestRecoveryFromTruncatedFinalRecord() {
+	// Setup - write two records, then chop the last one in half as a crash would
+	suite.createBooking(1000)
+	suite.createBooking(2000)
+	require.NoError(suite.T(), suite.repo.Close())
+
+	info, err := os.Stat(suite.walPath())
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), os.Truncate(suite.walPath(), info.Size()-5))
+
+	// Execute
+	suite.repo = suite.open(repository.FileRepositoryOptions{})
+
+	// Assert - the intact record survives and the damaged tail is gone
+	bookings, err := suite.repo.GetAll(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(bookings))
+	assert.Equal(suite.T(), 1000.0, bookings[0].Price)
+
+	// New writes must be readable after another restart
+	created := suite.createBooking(3000)
+	assert.Equal(suite.T(), int64(2), created.ID)
+
+	suite.reopen(repository.FileRepositoryOptions{})
+	bookings, err = suite.repo.GetAll(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(bookings))
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestRecoveryFromCorruptFinalRecord() {
+	// Setup - flip a byte inside the last record's payload
+	suite.createBooking(1000)
+	suite.createBooking(2000)
+	require.NoError(suite.T(), suite.repo.Close())
+
+	data, err := os.ReadFile(suite.walPath())
+	require.NoError(suite.T(), err)
+	data[len(data)-2] ^= 0xFF
+	require.NoError(suite.T(), os.WriteFile(suite.walPath(), data, 0o644))
+
+	// Execute
+	suite.repo = suite.open(repository.FileRepositoryOptions{})
+
+	// Assert
+	bookings, err := suite.repo.GetAll(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(bookings))
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestClosedRepositoryRejectsWrites() {
+	// Setup
+	require.NoError(suite.T(), suite.repo.Close())
+
+	// Execute
+	_, err := suite.repo.Create(context.Background(), &models.Booking{UserID: 1, ServiceID: 1, Price: 1})
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrUnavailable)
+}
+
+func (suite *BookingFileRepositoryTestSuite) TestPing() {
+	// Execute
+	open := suite.repo.Ping(context.Background())
+	require.NoError(suite.T(), os.Remove(suite.walPath()))
+	removed := suite.repo.Ping(context.Background())
+	require.NoError(suite.T(), suite.repo.Close())
+	closed := suite.repo.Ping(context.Background())
+
+	// Assert
+	assert.NoError(suite.T(), open)
+	assert.Error(suite.T(), removed)
+	assert.Error(suite.T(), closed)
+}
+
+// Run the test suite
+func TestBookingFileRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BookingFileRepositoryTestSuite))
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// BookingSortField names a field bookings can be ordered by
+type BookingSortField string
+
+// BookingSortField constants
+const (
+	SortByID        BookingSortField = "id"
+	SortByPrice     BookingSortField = "price"
+	SortByCreatedAt BookingSortField = "created_at"
+)
+
+// BookingQuery selects a page of bookings. Zero-valued filters match every booking.
+// Results are ordered by SortBy and then by ID, so every position is unique.
+type BookingQuery struct {
+	Status    models.BookingStatus
+	UserID    int64
+	ServiceID int64
+	// MinPrice and MaxPrice bound the price, inclusive
+	MinPrice *float64
+	MaxPrice *float64
+	// CreatedFrom is inclusive, CreatedTo exclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+
+	SortBy     BookingSortField
+	Descending bool
+	// Limit caps the page size; zero or less returns every match
+	Limit int
+	// After starts the page after this position, as returned in BookingPage.Next
+	After *BookingCursor
+}
+
+// BookingCursor is a position in the ordering of a BookingQuery
+type BookingCursor struct {
+	ID        int64
+	Price     float64
+	CreatedAt time.Time
+}
+
+// BookingPage is one page of query results
+type BookingPage struct {
+	Bookings []*models.Booking
+	// Next is the position of the last booking, or nil when there are no more pages
+	Next *BookingCursor
+}
+
+// cursorOf returns the position of a booking
+func cursorOf(booking *models.Booking) *BookingCursor {
+	return &BookingCursor{
+		ID:        booking.ID,
+		Price:     booking.Price,
+		CreatedAt: booking.CreatedAt,
+	}
+}
+
+// matches reports whether a booking passes the query filters
+func (q BookingQuery) matches(booking *models.Booking) bool {
+	switch {
+	case q.Status != "" && booking.Status != q.Status:
+		return false
+	case q.UserID != 0 && booking.UserID != q.UserID:
+		return false
+	case q.ServiceID != 0 && booking.ServiceID != q.ServiceID:
+		return false
+	case q.MinPrice != nil && booking.Price < *q.MinPrice:
+		return false
+	case q.MaxPrice != nil && booking.Price > *q.MaxPrice:
+		return false
+	case !q.CreatedFrom.IsZero() && booking.CreatedAt.Before(q.CreatedFrom):
+		return false
+	case !q.CreatedTo.IsZero() && !booking.CreatedAt.Before(q.CreatedTo):
+		return false
+	}
+	return true
+}
+
+// compare orders a booking against a cursor in the query's sort order,
+// returning a negative number when the booking comes first
+func (q BookingQuery) compare(booking *models.Booking, cursor *BookingCursor) int {
+	result := 0
+	switch q.SortBy {
+	case SortByPrice:
+		result = compareValues(booking.Price < cursor.Price, booking.Price > cursor.Price)
+	case SortByCreatedAt:
+		result = compareValues(booking.CreatedAt.Before(cursor.CreatedAt), booking.CreatedAt.After(cursor.CreatedAt))
+	}
+	if result == 0 {
+		result = compareValues(booking.ID < cursor.ID, booking.ID > cursor.ID)
+	}
+
+	if q.Descending {
+		return -result
+	}
+	return result
+}
+
+func compareValues(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// queryBookings runs a query over bookings held in memory. The page holds the
+// stored bookings themselves; callers copy them before releasing their lock.
+func queryBookings(bookings map[int64]*models.Booking, q BookingQuery) *BookingPage {
+	matched := make([]*models.Booking, 0)
+	for _, booking := range bookings {
+		if !q.matches(booking) {
+			continue
+		}
+		if q.After != nil && q.compare(booking, q.After) <= 0 {
+			continue
+		}
+		matched = append(matched, booking)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return q.compare(matched[i], cursorOf(matched[j])) < 0
+	})
+
+	page := &BookingPage{Bookings: matched}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		page.Bookings = matched[:q.Limit]
+		page.Next = cursorOf(page.Bookings[q.Limit-1])
+	}
+
+	return page
+}
@@ -0,0 +1,207 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryServiceID isolates the query fixtures from bookings seeded by the mock repository
+const queryServiceID = 777
+
+// queryFixture describes a booking created for the query tests
+type queryFixture struct {
+	userID  int64
+	price   float64
+	age     time.Duration
+	status  models.BookingStatus
+	booking *models.Booking
+}
+
+// seedQueryFixtures stores the fixtures; prices repeat to exercise the ID tie-breaker
+func seedQueryFixtures(t *testing.T, repo repository.BookingRepository, base time.Time) []*queryFixture {
+	fixtures := []*queryFixture{
+		{userID: 1, price: 30000, age: 5 * time.Hour, status: models.BookingStatusPending},
+		{userID: 2, price: 60000, age: 4 * time.Hour, status: models.BookingStatusConfirmed},
+		{userID: 1, price: 30000, age: 3 * time.Hour, status: models.BookingStatusPending},
+		{userID: 3, price: 90000, age: 2 * time.Hour, status: models.BookingStatusCanceled},
+		{userID: 1, price: 10000, age: time.Hour, status: models.BookingStatusPending},
+		{userID: 2, price: 60000, age: 0, status: models.BookingStatusPending},
+	}
+
+	ctx := context.Background()
+	for _, f := range fixtures {
+		createdAt := base.Add(-f.age)
+		booking, err := repo.Create(ctx, &models.Booking{
+			UserID:    f.userID,
+			ServiceID: queryServiceID,
+			Price:     f.price,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		})
+		require.NoError(t, err)
+
+		if f.status != models.BookingStatusPending {
+			booking.Status = f.status
+			booking, err = repo.Update(ctx, booking)
+			require.NoError(t, err)
+		}
+		f.booking = booking
+	}
+
+	return fixtures
+}
+
+func bookingIDs(bookings []*models.Booking) []int64 {
+	ids := make([]int64, 0, len(bookings))
+	for _, booking := range bookings {
+		ids = append(ids, booking.ID)
+	}
+	return ids
+}
+
+// testBookingQuery checks Query against any BookingRepository implementation
+func testBookingQuery(t *testing.T, repo repository.BookingRepository) {
+	ctx := context.Background()
+	base := time.Now().Truncate(time.Second)
+	fixtures := seedQueryFixtures(t, repo, base)
+	id := func(i int) int64 { return fixtures[i].booking.ID }
+
+	price := func(p float64) *float64 { return &p }
+
+	t.Run("filters", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			query    repository.BookingQuery
+			expected []int64
+		}{
+			{"service only", repository.BookingQuery{}, []int64{id(0), id(1), id(2), id(3), id(4), id(5)}},
+			{"status", repository.BookingQuery{Status: models.BookingStatusPending}, []int64{id(0), id(2), id(4), id(5)}},
+			{"user", repository.BookingQuery{UserID: 2}, []int64{id(1), id(5)}},
+			{"price range is inclusive", repository.BookingQuery{MinPrice: price(30000), MaxPrice: price(60000)}, []int64{id(0), id(1), id(2), id(5)}},
+			{"created range is half-open", repository.BookingQuery{CreatedFrom: base.Add(-4 * time.Hour), CreatedTo: base.Add(-time.Hour)}, []int64{id(1), id(2), id(3)}},
+			{"combined", repository.BookingQuery{Status: models.BookingStatusPending, UserID: 1, MaxPrice: price(20000)}, []int64{id(4)}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Execute
+				tt.query.ServiceID = queryServiceID
+				page, err := repo.Query(ctx, tt.query)
+
+				// Assert
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, bookingIDs(page.Bookings))
+				assert.Nil(t, page.Next)
+			})
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		orders := []struct {
+			sortBy repository.BookingSortField
+			less   func(a, b *models.Booking) bool
+		}{
+			{repository.SortByID, func(a, b *models.Booking) bool { return a.ID < b.ID }},
+			{repository.SortByPrice, func(a, b *models.Booking) bool {
+				if a.Price != b.Price {
+					return a.Price < b.Price
+				}
+				return a.ID < b.ID
+			}},
+			{repository.SortByCreatedAt, func(a, b *models.Booking) bool {
+				if !a.CreatedAt.Equal(b.CreatedAt) {
+					return a.CreatedAt.Before(b.CreatedAt)
+				}
+				return a.ID < b.ID
+			}},
+		}
+
+		for _, order := range orders {
+			for _, descending := range []bool{false, true} {
+				name := string(order.sortBy) + " asc"
+				if descending {
+					name = string(order.sortBy) + " desc"
+				}
+
+				t.Run(name, func(t *testing.T) {
+					// Setup - the expected order, computed independently
+					expected := make([]*models.Booking, 0, len(fixtures))
+					for _, f := range fixtures {
+						expected = append(expected, f.booking)
+					}
+					sort.Slice(expected, func(i, j int) bool {
+						if descending {
+							return order.less(expected[j], expected[i])
+						}
+						return order.less(expected[i], expected[j])
+					})
+
+					// Execute - walk the pages two bookings at a time
+					query := repository.BookingQuery{
+						ServiceID:  queryServiceID,
+						SortBy:     order.sortBy,
+						Descending: descending,
+						Limit:      2,
+					}
+					var seen []*models.Booking
+					pages := 0
+					for {
+						page, err := repo.Query(ctx, query)
+						require.NoError(t, err)
+						pages++
+						seen = append(seen, page.Bookings...)
+						if page.Next == nil {
+							break
+						}
+						query.After = page.Next
+					}
+
+					// Assert
+					assert.Equal(t, 3, pages)
+					assert.Equal(t, bookingIDs(expected), bookingIDs(seen))
+				})
+			}
+		}
+	})
+
+	t.Run("exact last page has no next cursor", func(t *testing.T) {
+		// Execute
+		page, err := repo.Query(ctx, repository.BookingQuery{ServiceID: queryServiceID, Limit: len(fixtures)})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, len(fixtures), len(page.Bookings))
+		assert.Nil(t, page.Next)
+	})
+}
+
+func TestBookingQuery_Mock(t *testing.T) {
+	testBookingQuery(t, repository.NewBookingRepositoryMock())
+}
+
+func TestBookingQuery_File(t *testing.T) {
+	repo, err := repository.OpenBookingRepositoryFile(t.TempDir(), repository.FileRepositoryOptions{NoSync: true})
+	require.NoError(t, err)
+	defer repo.Close()
+
+	testBookingQuery(t, repo)
+}
+
+func TestBookingQuery_SQL(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "bookings.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	require.NoError(t, repository.Migrate(context.Background(), db))
+
+	testBookingQuery(t, repository.NewBookingRepositorySQL(db))
+}
@@ -3,17 +3,47 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
 )
 
-// BookingRepository defines the interface for booking data operations
+// ErrBookingNotFound is returned when a booking does not exist; it matches ErrNotFound
+var ErrBookingNotFound = fmt.Errorf("booking %w", ErrNotFound)
+
+// ErrVersionConflict is returned when an update is based on a stale version of a booking; it matches ErrConflict
+var ErrVersionConflict = fmt.Errorf("booking version %w", ErrConflict)
+
+// VersionConflictError describes a failed optimistic concurrency check.
+// It matches ErrVersionConflict and ErrConflict with errors.Is.
+type VersionConflictError struct {
+	ID              int64
+	ExpectedVersion int64
+	CurrentVersion  int64
+}
+
+// Error implements the error interface
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("booking %d version conflict: expected version %d, current version %d",
+		e.ID, e.ExpectedVersion, e.CurrentVersion)
+}
+
+// Is reports whether target is ErrVersionConflict or ErrConflict
+func (e *VersionConflictError) Is(target error) bool {
+	return errors.Is(ErrVersionConflict, target)
+}
+
+// BookingRepository defines the interface for booking data operations.
+// Create stores version 1 of a booking; Update only succeeds when booking.Version
+// matches the stored version, and returns the booking with its version incremented.
 type BookingRepository interface {
 	Create(ctx context.Context, booking *models.Booking) (*models.Booking, error)
 	GetByID(ctx context.Context, id int64) (*models.Booking, error)
 	GetAll(ctx context.Context) ([]*models.Booking, error)
+	// Query returns one page of the bookings matching the query
+	Query(ctx context.Context, query BookingQuery) (*BookingPage, error)
 	Update(ctx context.Context, booking *models.Booking) (*models.Booking, error)
 }
 
@@ -52,6 +82,7 @@ func NewBookingRepositoryMock() *BookingRepositoryMock {
 			Status:    status,
 			CreatedAt: now.Add(-time.Duration(i) * time.Hour),
 			UpdatedAt: now,
+			Version:   1,
 		}
 		repo.bookings[i] = booking
 	}
@@ -67,21 +98,14 @@ func (r *BookingRepositoryMock) Create(ctx context.Context, booking *models.Book
 	booking.ID = r.nextID
 	r.nextID++
 	booking.Status = models.BookingStatusPending
+	booking.Version = 1
 
 	// Deep copy to avoid reference issues
-	newBooking := &models.Booking{
-		ID:        booking.ID,
-		UserID:    booking.UserID,
-		ServiceID: booking.ServiceID,
-		Price:     booking.Price,
-		Status:    booking.Status,
-		CreatedAt: booking.CreatedAt,
-		UpdatedAt: booking.UpdatedAt,
-	}
+	newBooking := booking.Clone()
 
 	r.bookings[newBooking.ID] = newBooking
 
-	return newBooking, nil
+	return newBooking.Clone(), nil
 }
 
 // GetByID retrieves a booking by ID
@@ -91,19 +115,11 @@ func (r *BookingRepositoryMock) GetByID(ctx context.Context, id int64) (*models.
 
 	booking, exists := r.bookings[id]
 	if !exists {
-		return nil, errors.New("booking not found")
+		return nil, ErrBookingNotFound
 	}
 
 	// Return a copy to avoid reference issues
-	return &models.Booking{
-		ID:        booking.ID,
-		UserID:    booking.UserID,
-		ServiceID: booking.ServiceID,
-		Price:     booking.Price,
-		Status:    booking.Status,
-		CreatedAt: booking.CreatedAt,
-		UpdatedAt: booking.UpdatedAt,
-	}, nil
+	return booking.Clone(), nil
 }
 
 // GetAll retrieves all bookings
@@ -114,20 +130,27 @@ func (r *BookingRepositoryMock) GetAll(ctx context.Context) ([]*models.Booking,
 	bookings := make([]*models.Booking, 0, len(r.bookings))
 	for _, booking := range r.bookings {
 		// Return copies to avoid reference issues
-		bookings = append(bookings, &models.Booking{
-			ID:        booking.ID,
-			UserID:    booking.UserID,
-			ServiceID: booking.ServiceID,
-			Price:     booking.Price,
-			Status:    booking.Status,
-			CreatedAt: booking.CreatedAt,
-			UpdatedAt: booking.UpdatedAt,
-		})
+		bookings = append(bookings, booking.Clone())
 	}
 
 	return bookings, nil
 }
 
+// Query returns a page of bookings matching the query
+func (r *BookingRepositoryMock) Query(ctx context.Context, query BookingQuery) (*BookingPage, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	page := queryBookings(r.bookings, query)
+
+	// Return copies to avoid reference issues
+	for i, booking := range page.Bookings {
+		page.Bookings[i] = booking.Clone()
+	}
+
+	return page, nil
+}
+
 // Update updates a booking
 func (r *BookingRepositoryMock) Update(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
 	r.mutex.Lock()
@@ -135,24 +158,26 @@ func (r *BookingRepositoryMock) Update(ctx context.Context, booking *models.Book
 
 	existing, exists := r.bookings[booking.ID]
 	if !exists {
-		return nil, errors.New("booking not found")
+		return nil, ErrBookingNotFound
+	}
+
+	// Reject updates based on a stale read
+	if booking.Version != existing.Version {
+		return nil, &VersionConflictError{
+			ID:              booking.ID,
+			ExpectedVersion: booking.Version,
+			CurrentVersion:  existing.Version,
+		}
 	}
 
 	// Update the booking while preserving creation time
 	booking.CreatedAt = existing.CreatedAt
 
 	// Store a copy to avoid reference issues
-	updatedBooking := &models.Booking{
-		ID:        booking.ID,
-		UserID:    booking.UserID,
-		ServiceID: booking.ServiceID,
-		Price:     booking.Price,
-		Status:    booking.Status,
-		CreatedAt: booking.CreatedAt,
-		UpdatedAt: booking.UpdatedAt,
-	}
+	updatedBooking := booking.Clone()
+	updatedBooking.Version = existing.Version + 1
 
 	r.bookings[booking.ID] = updatedBooking
 
-	return updatedBooking, nil
+	return updatedBooking.Clone(), nil
 }
@@ -94,6 +94,7 @@ func (suite *BookingRepositoryTestSuite) TestUpdate_ExistingBooking() {
 		Status:    models.BookingStatusCanceled, // Change status
 		CreatedAt: existingBooking.CreatedAt,
 		UpdatedAt: time.Now(),
+		Version:   existingBooking.Version,
 	}
 
 	// Execute
@@ -103,12 +104,53 @@ func (suite *BookingRepositoryTestSuite) TestUpdate_ExistingBooking() {
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
 	assert.Equal(suite.T(), models.BookingStatusCanceled, result.Status)
+	assert.Equal(suite.T(), existingBooking.Version+1, result.Version)
 
 	// Verify update was persisted
 	retrievedBooking, _ := suite.repo.GetByID(ctx, 1)
 	assert.Equal(suite.T(), models.BookingStatusCanceled, retrievedBooking.Status)
 }
 
+func (suite *BookingRepositoryTestSuite) TestUpdate_ReturnsCopy() {
+	// Setup
+	ctx := context.Background()
+	existingBooking, _ := suite.repo.GetByID(ctx, 1)
+	existingBooking.Status = models.BookingStatusCanceled
+	result, err := suite.repo.Update(ctx, existingBooking)
+	assert.NoError(suite.T(), err)
+
+	// Execute
+	result.Status = models.BookingStatusConfirmed
+	result.Version = 99
+
+	// Assert
+	retrievedBooking, _ := suite.repo.GetByID(ctx, 1)
+	assert.Equal(suite.T(), models.BookingStatusCanceled, retrievedBooking.Status)
+	assert.Equal(suite.T(), existingBooking.Version+1, retrievedBooking.Version)
+}
+
+func (suite *BookingRepositoryTestSuite) TestUpdate_StaleVersion() {
+	// Setup - cancel the booking, then try to confirm it from the version read before
+	ctx := context.Background()
+	staleBooking, _ := suite.repo.GetByID(ctx, 1)
+	current, _ := suite.repo.GetByID(ctx, 1)
+	current.Status = models.BookingStatusCanceled
+	_, err := suite.repo.Update(ctx, current)
+	assert.NoError(suite.T(), err)
+
+	staleBooking.Status = models.BookingStatusConfirmed
+
+	// Execute
+	result, err := suite.repo.Update(ctx, staleBooking)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrVersionConflict)
+	assert.Nil(suite.T(), result)
+
+	retrievedBooking, _ := suite.repo.GetByID(ctx, 1)
+	assert.Equal(suite.T(), models.BookingStatusCanceled, retrievedBooking.Status)
+}
+
 func (suite *BookingRepositoryTestSuite) TestUpdate_NonExistingBooking() {
 	// Setup
 	ctx := context.Background()
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// bookingColumns lists the columns read by every booking query, in scan order
+const bookingColumns = `id, user_id, service_id, price, status, created_at, updated_at, version, credit_check, expires_at`
+
+// BookingRepositorySQL is a database/sql implementation of BookingRepository.
+// Timestamps are stored as Unix nanoseconds so ordering and range queries stay portable.
+type BookingRepositorySQL struct {
+	db *sql.DB
+}
+
+// NewBookingRepositorySQL creates a new instance of BookingRepositorySQL.
+// The schema must already be up to date; call Migrate before using the repository.
+func NewBookingRepositorySQL(db *sql.DB) *BookingRepositorySQL {
+	return &BookingRepositorySQL{
+		db: db,
+	}
+}
+
+// Ping checks that the database is reachable
+func (r *BookingRepositorySQL) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// Create creates a new booking
+func (r *BookingRepositorySQL) Create(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	newBooking := &models.Booking{
+		UserID:    booking.UserID,
+		ServiceID: booking.ServiceID,
+		Price:     booking.Price,
+		Status:    models.BookingStatusPending,
+		CreatedAt: booking.CreatedAt,
+		UpdatedAt: booking.UpdatedAt,
+		Version:   1,
+		ExpiresAt: booking.ExpiresAt,
+	}
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO bookings (user_id, service_id, price, status, created_at, updated_at, version, expires_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			newBooking.UserID,
+			newBooking.ServiceID,
+			newBooking.Price,
+			newBooking.Status.String(),
+			newBooking.CreatedAt.UnixNano(),
+			newBooking.UpdatedAt.UnixNano(),
+			newBooking.Version,
+			encodeTime(newBooking.ExpiresAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		newBooking.ID, err = result.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return nil, sqlError(err)
+	}
+
+	booking.ID = newBooking.ID
+	booking.Status = newBooking.Status
+	booking.Version = newBooking.Version
+
+	return newBooking, nil
+}
+
+// GetByID retrieves a booking by ID
+func (r *BookingRepositorySQL) GetByID(ctx context.Context, id int64) (*models.Booking, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE id = ?`, id)
+
+	booking, err := scanBooking(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, sqlError(err)
+	}
+
+	return booking, nil
+}
+
+// GetAll retrieves all bookings
+func (r *BookingRepositorySQL) GetAll(ctx context.Context) ([]*models.Booking, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+bookingColumns+` FROM bookings ORDER BY id`)
+	if err != nil {
+		return nil, sqlError(err)
+	}
+	defer rows.Close()
+
+	bookings := make([]*models.Booking, 0)
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, sqlError(err)
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sqlError(err)
+	}
+
+	return bookings, nil
+}
+
+// Query returns a page of bookings matching the query; filtering, ordering and
+// limiting all happen in the database
+func (r *BookingRepositorySQL) Query(ctx context.Context, query BookingQuery) (*BookingPage, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	where := func(condition string, values ...interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, values...)
+	}
+
+	if query.Status != "" {
+		where(`status = ?`, query.Status.String())
+	}
+	if query.UserID != 0 {
+		where(`user_id = ?`, query.UserID)
+	}
+	if query.ServiceID != 0 {
+		where(`service_id = ?`, query.ServiceID)
+	}
+	if query.MinPrice != nil {
+		where(`price >= ?`, *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		where(`price <= ?`, *query.MaxPrice)
+	}
+	if !query.CreatedFrom.IsZero() {
+		where(`created_at >= ?`, query.CreatedFrom.UnixNano())
+	}
+	if !query.CreatedTo.IsZero() {
+		where(`created_at < ?`, query.CreatedTo.UnixNano())
+	}
+
+	column, direction, comparison := `id`, `ASC`, `>`
+	if query.Descending {
+		direction, comparison = `DESC`, `<`
+	}
+
+	// Keyset pagination: continue strictly after the cursor's (sort value, id)
+	var cursorValue interface{}
+	switch query.SortBy {
+	case SortByPrice:
+		column = `price`
+		if query.After != nil {
+			cursorValue = query.After.Price
+		}
+	case SortByCreatedAt:
+		column = `created_at`
+		if query.After != nil {
+			cursorValue = query.After.CreatedAt.UnixNano()
+		}
+	}
+	if query.After != nil {
+		if cursorValue == nil {
+			where(`id `+comparison+` ?`, query.After.ID)
+		} else {
+			where(`(`+column+` `+comparison+` ? OR (`+column+` = ? AND id `+comparison+` ?))`,
+				cursorValue, cursorValue, query.After.ID)
+		}
+	}
+
+	statement := `SELECT ` + bookingColumns + ` FROM bookings`
+	if len(conditions) > 0 {
+		statement += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	statement += ` ORDER BY ` + column + ` ` + direction
+	if column != `id` {
+		statement += `, id ` + direction
+	}
+	if query.Limit > 0 {
+		// Fetch one extra row to learn whether another page follows
+		statement += ` LIMIT ?`
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, sqlError(err)
+	}
+	defer rows.Close()
+
+	bookings := make([]*models.Booking, 0)
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, sqlError(err)
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, sqlError(err)
+	}
+
+	page := &BookingPage{Bookings: bookings}
+	if query.Limit > 0 && len(bookings) > query.Limit {
+		page.Bookings = bookings[:query.Limit]
+		page.Next = cursorOf(page.Bookings[query.Limit-1])
+	}
+
+	return page, nil
+}
+
+// Update updates a booking
+func (r *BookingRepositorySQL) Update(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	var updatedBooking *models.Booking
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE id = ?`, booking.ID)
+		existing, err := scanBooking(row)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrBookingNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// Reject updates based on a stale read
+		if booking.Version != existing.Version {
+			return &VersionConflictError{
+				ID:              booking.ID,
+				ExpectedVersion: booking.Version,
+				CurrentVersion:  existing.Version,
+			}
+		}
+
+		// Update the booking while preserving creation time
+		updatedBooking = &models.Booking{
+			ID:          booking.ID,
+			UserID:      booking.UserID,
+			ServiceID:   booking.ServiceID,
+			Price:       booking.Price,
+			Status:      booking.Status,
+			CreatedAt:   existing.CreatedAt,
+			UpdatedAt:   booking.UpdatedAt,
+			Version:     existing.Version + 1,
+			ExpiresAt:   booking.ExpiresAt,
+			CreditCheck: booking.CreditCheck.Clone(),
+		}
+
+		creditCheck, err := encodeCreditCheck(updatedBooking.CreditCheck)
+		if err != nil {
+			return err
+		}
+
+		// The version predicate keeps the update safe even without serializable isolation
+		result, err := tx.ExecContext(ctx,
+			`UPDATE bookings
+			 SET user_id = ?, service_id = ?, price = ?, status = ?, updated_at = ?, version = ?, credit_check = ?, expires_at = ?
+			 WHERE id = ? AND version = ?`,
+			updatedBooking.UserID,
+			updatedBooking.ServiceID,
+			updatedBooking.Price,
+			updatedBooking.Status.String(),
+			updatedBooking.UpdatedAt.UnixNano(),
+			updatedBooking.Version,
+			creditCheck,
+			encodeTime(updatedBooking.ExpiresAt),
+			updatedBooking.ID,
+			existing.Version,
+		)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected != 1 {
+			return &VersionConflictError{
+				ID:              booking.ID,
+				ExpectedVersion: booking.Version,
+				CurrentVersion:  existing.Version,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, sqlError(err)
+	}
+
+	booking.CreatedAt = updatedBooking.CreatedAt
+
+	return updatedBooking, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBooking reads a booking from a row selected with bookingColumns
+func scanBooking(row rowScanner) (*models.Booking, error) {
+	var (
+		booking     models.Booking
+		status      string
+		createdAt   int64
+		updatedAt   int64
+		creditCheck sql.NullString
+		expiresAt   sql.NullInt64
+	)
+
+	err := row.Scan(
+		&booking.ID,
+		&booking.UserID,
+		&booking.ServiceID,
+		&booking.Price,
+		&status,
+		&createdAt,
+		&updatedAt,
+		&booking.Version,
+		&creditCheck,
+		&expiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if creditCheck.Valid {
+		booking.CreditCheck = new(models.CreditCheck)
+		if err := json.Unmarshal([]byte(creditCheck.String), booking.CreditCheck); err != nil {
+			return nil, err
+		}
+	}
+
+	booking.Status = models.BookingStatus(status)
+	booking.CreatedAt = time.Unix(0, createdAt)
+	booking.UpdatedAt = time.Unix(0, updatedAt)
+	if expiresAt.Valid {
+		booking.ExpiresAt = time.Unix(0, expiresAt.Int64)
+	}
+
+	return &booking, nil
+}
+
+// encodeCreditCheck serializes a credit check for the credit_check column; nil maps to NULL
+func encodeCreditCheck(check *models.CreditCheck) (sql.NullString, error) {
+	if check == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(check)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// encodeTime converts an optional timestamp to Unix nanoseconds; the zero time maps to NULL
+func encodeTime(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
@@ -0,0 +1,348 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	// Embedded SQLite driver
+	_ "modernc.org/sqlite"
+)
+
+type BookingSQLRepositoryTestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	repo repository.BookingRepository
+}
+
+func (suite *BookingSQLRepositoryTestSuite) SetupTest() {
+	// Create a fresh database file for each test
+	dsn := "file:" + filepath.Join(suite.T().TempDir(), "bookings.db") + "?_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(suite.T(), err)
+	db.SetMaxOpenConns(1)
+
+	require.NoError(suite.T(), repository.Migrate(context.Background(), db))
+
+	suite.db = db
+	suite.repo = repository.NewBookingRepositorySQL(db)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *BookingSQLRepositoryTestSuite) createBooking(price float64) *models.Booking {
+	now := time.Now()
+	booking, err := suite.repo.Create(context.Background(), &models.Booking{
+		UserID:    123,
+		ServiceID: 456,
+		Price:     price,
+		Status:    models.BookingStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	require.NoError(suite.T(), err)
+	return booking
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestMigrate_IsIdempotent() {
+	// Execute - running migrations again must be a no-op
+	err := repository.Migrate(context.Background(), suite.db)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+
+	var applied int
+	err = suite.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied)
+	assert.NoError(suite.T(), err)
+	assert.Greater(suite.T(), applied, 0)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestCreate() {
+	// Create test data
+	ctx := context.Background()
+	now := time.Now()
+	booking := &models.Booking{
+		UserID:    999,
+		ServiceID: 888,
+		Price:     25000.0,
+		Status:    models.BookingStatusConfirmed, // Must be reset to pending
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Execute
+	result, err := suite.repo.Create(ctx, booking)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), result)
+	assert.Greater(suite.T(), result.ID, int64(0))
+	assert.Equal(suite.T(), booking.UserID, result.UserID)
+	assert.Equal(suite.T(), booking.ServiceID, result.ServiceID)
+	assert.Equal(suite.T(), booking.Price, result.Price)
+	assert.Equal(suite.T(), models.BookingStatusPending, result.Status)
+	assert.True(suite.T(), now.Equal(result.CreatedAt))
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestCreate_StoresExpiresAt() {
+	// Setup
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	// Execute
+	withExpiry, err := suite.repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 2, Price: 100, ExpiresAt: expiresAt})
+	require.NoError(suite.T(), err)
+	withoutExpiry := suite.createBooking(100)
+
+	// Assert - a zero expiry time round-trips as zero
+	stored, err := suite.repo.GetByID(ctx, withExpiry.ID)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), expiresAt.Equal(stored.ExpiresAt))
+
+	stored, err = suite.repo.GetByID(ctx, withoutExpiry.ID)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), stored.ExpiresAt.IsZero())
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestCreate_ConcurrentInsertsGetUniqueIDs() {
+	// Setup
+	const goroutines = 20
+	ids := make(chan int64, goroutines)
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	// Execute
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			booking, err := suite.repo.Create(context.Background(), &models.Booking{
+				UserID:    1,
+				ServiceID: 2,
+				Price:     1000,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			})
+			if assert.NoError(suite.T(), err) {
+				ids <- booking.ID
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	// Assert
+	seen := make(map[int64]bool)
+	for id := range ids {
+		assert.False(suite.T(), seen[id], "duplicate booking ID %d", id)
+		seen[id] = true
+	}
+	assert.Equal(suite.T(), goroutines, len(seen))
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestGetByID_ExistingBooking() {
+	// Setup
+	created := suite.createBooking(30000)
+
+	// Execute
+	result, err := suite.repo.GetByID(context.Background(), created.ID)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), created.ID, result.ID)
+	assert.Equal(suite.T(), created.Price, result.Price)
+	assert.Equal(suite.T(), models.BookingStatusPending, result.Status)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestGetByID_NonExistingBooking() {
+	// Execute
+	result, err := suite.repo.GetByID(context.Background(), 999)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrBookingNotFound)
+	assert.ErrorIs(suite.T(), err, repository.ErrNotFound)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), "booking not found", err.Error())
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestGetAll() {
+	// Setup
+	suite.createBooking(10000)
+	suite.createBooking(20000)
+
+	// Execute
+	results, err := suite.repo.GetAll(context.Background())
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(results))
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestUpdate_ExistingBooking() {
+	// Setup
+	ctx := context.Background()
+	existing := suite.createBooking(30000)
+
+	// Modify booking, trying to move its creation time
+	updatedBooking := &models.Booking{
+		ID:        existing.ID,
+		UserID:    existing.UserID,
+		ServiceID: existing.ServiceID,
+		Price:     existing.Price,
+		Status:    models.BookingStatusCanceled,
+		CreatedAt: existing.CreatedAt.Add(time.Hour),
+		UpdatedAt: time.Now(),
+		Version:   existing.Version,
+	}
+
+	// Execute
+	result, err := suite.repo.Update(ctx, updatedBooking)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.BookingStatusCanceled, result.Status)
+	assert.Equal(suite.T(), existing.Version+1, result.Version)
+	assert.True(suite.T(), existing.CreatedAt.Equal(result.CreatedAt), "creation time must be preserved")
+
+	// Verify update was persisted
+	retrievedBooking, err := suite.repo.GetByID(ctx, existing.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.BookingStatusCanceled, retrievedBooking.Status)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestUpdate_StaleVersion() {
+	// Setup - two writers read the same version
+	ctx := context.Background()
+	existing := suite.createBooking(30000)
+	first, _ := suite.repo.GetByID(ctx, existing.ID)
+	second, _ := suite.repo.GetByID(ctx, existing.ID)
+
+	first.Status = models.BookingStatusCanceled
+	_, err := suite.repo.Update(ctx, first)
+	require.NoError(suite.T(), err)
+
+	// Execute - the second writer is now stale
+	second.Status = models.BookingStatusConfirmed
+	result, err := suite.repo.Update(ctx, second)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrVersionConflict)
+	assert.ErrorIs(suite.T(), err, repository.ErrConflict)
+	assert.Nil(suite.T(), result)
+
+	var conflict *repository.VersionConflictError
+	if assert.ErrorAs(suite.T(), err, &conflict) {
+		assert.Equal(suite.T(), int64(1), conflict.ExpectedVersion)
+		assert.Equal(suite.T(), int64(2), conflict.CurrentVersion)
+	}
+
+	stored, _ := suite.repo.GetByID(ctx, existing.ID)
+	assert.Equal(suite.T(), models.BookingStatusCanceled, stored.Status)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestUpdate_StoresCreditCheck() {
+	// Setup
+	ctx := context.Background()
+	existing := suite.createBooking(60000)
+	existing.Status = models.BookingStatusConfirmed
+	existing.CreditCheck = &models.CreditCheck{
+		Outcome:   models.CreditCheckApproved,
+		Reason:    "price within credit limit",
+		Checker:   "rules",
+		Attempts:  2,
+		CheckedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	// Execute
+	_, err := suite.repo.Update(ctx, existing)
+	require.NoError(suite.T(), err)
+	result, err := suite.repo.GetByID(ctx, existing.ID)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), result.CreditCheck) {
+		assert.Equal(suite.T(), models.CreditCheckApproved, result.CreditCheck.Outcome)
+		assert.Equal(suite.T(), "rules", result.CreditCheck.Checker)
+		assert.Equal(suite.T(), 2, result.CreditCheck.Attempts)
+		assert.True(suite.T(), existing.CreditCheck.CheckedAt.Equal(result.CreditCheck.CheckedAt))
+	}
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestUpdate_NonExistingBooking() {
+	// Execute
+	result, err := suite.repo.Update(context.Background(), &models.Booking{
+		ID:        999,
+		Status:    models.BookingStatusCanceled,
+		UpdatedAt: time.Now(),
+	})
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrBookingNotFound)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestPing() {
+	// Setup
+	pinger := suite.repo.(*repository.BookingRepositorySQL)
+
+	// Execute
+	reachable := pinger.Ping(context.Background())
+	suite.db.Close()
+	closed := pinger.Ping(context.Background())
+
+	// Assert
+	assert.NoError(suite.T(), reachable)
+	assert.Error(suite.T(), closed)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestGetByID_DatabaseClosed() {
+	// Setup
+	suite.db.Close()
+
+	// Execute
+	result, err := suite.repo.GetByID(context.Background(), 1)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repository.ErrUnavailable)
+	assert.NotErrorIs(suite.T(), err, repository.ErrNotFound)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *BookingSQLRepositoryTestSuite) TestDataSurvivesReopen() {
+	// Setup
+	created := suite.createBooking(42000)
+	path := filepath.Join(suite.T().TempDir(), "reopen.db")
+	db, err := sql.Open("sqlite", "file:"+path)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), repository.Migrate(context.Background(), db))
+	repo := repository.NewBookingRepositorySQL(db)
+	stored, err := repo.Create(context.Background(), created)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.Close())
+
+	// Execute - reopen the same file
+	db, err = sql.Open("sqlite", "file:"+path)
+	require.NoError(suite.T(), err)
+	defer db.Close()
+	require.NoError(suite.T(), repository.Migrate(context.Background(), db))
+	result, err := repository.NewBookingRepositorySQL(db).GetByID(context.Background(), stored.ID)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 42000.0, result.Price)
+}
+
+// Run the test suite
+func TestBookingSQLRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BookingSQLRepositoryTestSuite))
+}
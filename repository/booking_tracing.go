@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedBookingRepository runs every call of the repository it wraps in a span
+type TracedBookingRepository struct {
+	repo BookingRepository
+}
+
+// NewTracedBookingRepository wraps repo with tracing
+func NewTracedBookingRepository(repo BookingRepository) *TracedBookingRepository {
+	return &TracedBookingRepository{repo: repo}
+}
+
+// startSpan starts a client span named after the repository method
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "BookingRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// Create stores a new booking
+func (r *TracedBookingRepository) Create(ctx context.Context, booking *models.Booking) (created *models.Booking, err error) {
+	ctx, span := startSpan(ctx, "Create")
+	defer func() { tracing.End(span, err) }()
+
+	created, err = r.repo.Create(ctx, booking)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("booking.id", created.ID))
+	}
+	return created, err
+}
+
+// GetByID retrieves a booking by its ID
+func (r *TracedBookingRepository) GetByID(ctx context.Context, id int64) (booking *models.Booking, err error) {
+	ctx, span := startSpan(ctx, "GetByID", attribute.Int64("booking.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return r.repo.GetByID(ctx, id)
+}
+
+// GetAll retrieves all bookings
+func (r *TracedBookingRepository) GetAll(ctx context.Context) (bookings []*models.Booking, err error) {
+	ctx, span := startSpan(ctx, "GetAll")
+	defer func() { tracing.End(span, err) }()
+
+	bookings, err = r.repo.GetAll(ctx)
+	span.SetAttributes(attribute.Int("bookings.count", len(bookings)))
+	return bookings, err
+}
+
+// Query returns one page of the bookings matching the query
+func (r *TracedBookingRepository) Query(ctx context.Context, query BookingQuery) (page *BookingPage, err error) {
+	ctx, span := startSpan(ctx, "Query", attribute.Int("query.limit", query.Limit))
+	defer func() { tracing.End(span, err) }()
+
+	page, err = r.repo.Query(ctx, query)
+	if err == nil {
+		span.SetAttributes(attribute.Int("bookings.count", len(page.Bookings)))
+	}
+	return page, err
+}
+
+// Update stores a new version of a booking
+func (r *TracedBookingRepository) Update(ctx context.Context, booking *models.Booking) (updated *models.Booking, err error) {
+	ctx, span := startSpan(ctx, "Update",
+		attribute.Int64("booking.id", booking.ID),
+		attribute.Int64("booking.version", booking.Version))
+	defer func() { tracing.End(span, err) }()
+
+	return r.repo.Update(ctx, booking)
+}
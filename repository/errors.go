@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error kinds shared by all repositories. Specific errors such as
+// ErrBookingNotFound wrap one of them, so callers can handle a whole kind
+// with errors.Is.
+var (
+	// ErrNotFound matches errors for records that do not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict matches errors for writes that conflict with the stored state
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable matches errors for a store that cannot be reached right now
+	ErrUnavailable = errors.New("repository unavailable")
+)
+
+// errClosed is returned by a file repository after Close
+var errClosed = fmt.Errorf("booking repository is closed: %w", ErrUnavailable)
+
+// UnavailableError wraps a failure to reach the underlying store.
+// It matches ErrUnavailable with errors.Is.
+type UnavailableError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *UnavailableError) Error() string {
+	return "repository unavailable: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrUnavailable
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// sqlError marks database errors that mean the database cannot be reached,
+// as opposed to a failing statement, as unavailable
+func sqlError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// database/sql does not export the error for a closed database
+	unavailable := errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		strings.Contains(err.Error(), "sql: database is closed") ||
+		strings.Contains(err.Error(), "database is locked")
+	if unavailable {
+		return &UnavailableError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"errors"
+	"io"
+)
+
+// FailNextWALWrite makes the next append to repo's write-ahead log write only
+// the first n bytes of its record and fail. With truncateErr set, cutting the
+// partial record from the log fails as well.
+func FailNextWALWrite(repo *BookingRepositoryFile, n int, truncateErr error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	repo.wal = &faultyWAL{walFile: repo.wal, n: n, truncateErr: truncateErr}
+}
+
+// faultyWAL fails its first write after writing part of it
+type faultyWAL struct {
+	walFile
+	n           int
+	failed      bool
+	truncateErr error
+}
+
+func (f *faultyWAL) Write(p []byte) (int, error) {
+	if f.failed {
+		return f.walFile.Write(p)
+	}
+	f.failed = true
+	written, err := f.walFile.Write(p[:min(f.n, len(p))])
+	if err != nil {
+		return written, err
+	}
+	return written, io.ErrShortWrite
+}
+
+func (f *faultyWAL) Truncate(size int64) error {
+	if f.truncateErr != nil {
+		return errors.Join(f.truncateErr, f.walFile.Truncate(size))
+	}
+	return f.walFile.Truncate(size)
+}
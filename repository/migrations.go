@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Migration describes a single, ordered schema change
+type Migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// migrations holds the schema history of the SQL repository.
+// Append new migrations to the end; never edit one that has been released.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create bookings table",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS bookings (
+				id         INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id    INTEGER NOT NULL,
+				service_id INTEGER NOT NULL,
+				price      REAL    NOT NULL,
+				status     TEXT    NOT NULL,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_bookings_status_created_at ON bookings (status, created_at)`,
+		},
+	},
+	{
+		Version:     2,
+		Description: "add optimistic concurrency version to bookings",
+		Statements: []string{
+			`ALTER TABLE bookings ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+		},
+	},
+	{
+		Version:     3,
+		Description: "record credit check results on bookings",
+		Statements: []string{
+			`ALTER TABLE bookings ADD COLUMN credit_check TEXT`,
+		},
+	},
+	{
+		Version:     4,
+		Description: "add expiry time to bookings",
+		Statements: []string{
+			`ALTER TABLE bookings ADD COLUMN expires_at INTEGER`,
+			`CREATE INDEX IF NOT EXISTS idx_bookings_status_expires_at ON bookings (status, expires_at)`,
+		},
+	},
+	{
+		Version:     5,
+		Description: "index booking query filters and sort keys",
+		Statements: []string{
+			`CREATE INDEX IF NOT EXISTS idx_bookings_user_id ON bookings (user_id, id)`,
+			`CREATE INDEX IF NOT EXISTS idx_bookings_service_id ON bookings (service_id, id)`,
+			`CREATE INDEX IF NOT EXISTS idx_bookings_price ON bookings (price, id)`,
+			`CREATE INDEX IF NOT EXISTS idx_bookings_created_at ON bookings (created_at, id)`,
+		},
+	},
+	{
+		Version:     6,
+		Description: "create api_keys table",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS api_keys (
+				id         TEXT    PRIMARY KEY,
+				name       TEXT    NOT NULL,
+				prefix     TEXT    NOT NULL,
+				hash       TEXT    NOT NULL UNIQUE,
+				owner_id   INTEGER NOT NULL,
+				scopes     TEXT    NOT NULL,
+				created_at INTEGER NOT NULL,
+				expires_at INTEGER,
+				revoked_at INTEGER
+			)`,
+		},
+	},
+	{
+		Version:     7,
+		Description: "add roles to api_keys",
+		Statements: []string{
+			`ALTER TABLE api_keys ADD COLUMN roles TEXT NOT NULL DEFAULT '[]'`,
+		},
+	},
+}
+
+// Migrate applies all pending migrations to the database.
+// Each migration runs in its own transaction and is recorded in schema_migrations.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+
+	current, err := currentSchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Description, err)
+		}
+		slog.InfoContext(ctx, "Applied migration", "version", m.Version, "description", m.Description)
+	}
+
+	return nil
+}
+
+// currentSchemaVersion returns the highest applied migration version, or 0 for a fresh database
+func currentSchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m Migration) error {
+	return withTx(ctx, db, func(tx *sql.Tx) error {
+		for _, stmt := range m.Statements {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+			m.Version, time.Now().UnixNano(),
+		)
+		return err
+	})
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling back on error
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -2,32 +2,121 @@ package router
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/swagger"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/handler"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/metrics"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/middleware"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/ratelimit"
 )
 
+// Dependencies holds the handlers and middleware configuration the routes are built from
+type Dependencies struct {
+	BookingHandler *handler.BookingHandler
+	JobHandler     *handler.JobHandler
+	APIKeyHandler  *handler.APIKeyHandler
+	// HealthHandler, when set, serves the /healthz and /readyz probes
+	HealthHandler *handler.HealthHandler
+	// AuthMode selects API key, JWT or either authentication; API keys when empty
+	AuthMode      middleware.AuthMode
+	Authenticator middleware.Authenticator
+	JWT           middleware.JWTConfig
+	Idempotency   middleware.IdempotencyConfig
+	RateLimits    RateLimits
+	// Metrics, when set, are collected for the API and served at /metrics
+	Metrics *metrics.Metrics
+}
+
+// RateLimits holds the limit of each route group; a zero limit disables it
+type RateLimits struct {
+	// Store keeps the buckets; an in-memory store when nil
+	Store ratelimit.Store
+	// PerIP applies to every API request, before authentication
+	PerIP         ratelimit.Limit
+	BookingReads  ratelimit.Limit
+	BookingWrites ratelimit.Limit
+	Admin         ratelimit.Limit
+}
+
 // SetupRoutes configures all application routes
-func SetupRoutes(app *fiber.App, bookingHandler *handler.BookingHandler) {
+func SetupRoutes(app *fiber.App, deps Dependencies) {
 	// Swagger documentation
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
+	// Health probes, outside /api so they skip authentication and rate limits
+	if deps.HealthHandler != nil {
+		app.Get("/healthz", deps.HealthHandler.Liveness)
+		app.Get("/readyz", deps.HealthHandler.Readiness)
+	}
+
 	// API group
 	api := app.Group("/api")
 
 	// Apply global middleware
+	limits := deps.RateLimits
+	if limits.Store == nil {
+		limits.Store = ratelimit.NewMemoryStore()
+	}
+	rateLimit := func(name string, limit ratelimit.Limit) fiber.Handler {
+		return middleware.RateLimit(middleware.RateLimitConfig{Store: limits.Store, Name: name, Limit: limit})
+	}
+
+	api.Use(middleware.Tracing())
 	api.Use(middleware.Logging())
-	api.Use(middleware.Auth())
+	if deps.Metrics != nil {
+		app.Get("/metrics", adaptor.HTTPHandler(deps.Metrics.Handler()))
+		api.Use(middleware.Metrics(deps.Metrics))
+	}
+	api.Use(middleware.RateLimit(middleware.RateLimitConfig{
+		Store:   limits.Store,
+		Name:    "ip",
+		Limit:   limits.PerIP,
+		KeyFunc: middleware.RateLimitByIP,
+	}))
+	api.Use(authentication(deps))
+
+	readBookings := []fiber.Handler{middleware.RequireScope(auth.ScopeBookingsRead), rateLimit("bookings:read", limits.BookingReads)}
+	writeBookings := []fiber.Handler{middleware.RequireScope(auth.ScopeBookingsWrite), rateLimit("bookings:write", limits.BookingWrites)}
 
 	// Bookings endpoints
 	bookings := api.Group("/bookings")
-	bookings.Post("/", bookingHandler.CreateBooking)
-	bookings.Get("/", bookingHandler.GetAllBookings)
-	bookings.Get("/:id", bookingHandler.GetBooking)
-	bookings.Delete("/:id", bookingHandler.CancelBooking)
+	bookings.Post("/", chain(writeBookings, middleware.Idempotency(deps.Idempotency), deps.BookingHandler.CreateBooking)...)
+	bookings.Get("/", chain(readBookings, deps.BookingHandler.GetAllBookings)...)
+	bookings.Get("/:id", chain(readBookings, deps.BookingHandler.GetBooking)...)
+	bookings.Delete("/:id", chain(writeBookings, deps.BookingHandler.CancelBooking)...)
+
+	// Admin endpoints
+	admin := api.Group("/admin", middleware.RequireScope(auth.ScopeAdmin), rateLimit("admin", limits.Admin))
+
+	adminJobs := admin.Group("/jobs")
+	adminJobs.Get("/failed", deps.JobHandler.GetFailedJobs)
+	adminJobs.Post("/:id/retry", deps.JobHandler.RetryJob)
+
+	adminKeys := admin.Group("/keys")
+	adminKeys.Post("/", deps.APIKeyHandler.IssueKey)
+	adminKeys.Get("/", deps.APIKeyHandler.ListKeys)
+	adminKeys.Delete("/:id", deps.APIKeyHandler.RevokeKey)
 
 	// Root route for API - redirect to Swagger docs
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.Redirect("/swagger/index.html")
 	})
 }
+
+// authentication builds the authentication middleware for deps.AuthMode
+func authentication(deps Dependencies) fiber.Handler {
+	switch deps.AuthMode {
+	case middleware.AuthModeJWT:
+		return middleware.JWT(deps.JWT)
+	case middleware.AuthModeEither:
+		return middleware.Either(middleware.Auth(deps.Authenticator), middleware.JWT(deps.JWT))
+	default:
+		return middleware.Auth(deps.Authenticator)
+	}
+}
+
+// chain appends handlers to the shared middleware of a route
+func chain(middlewares []fiber.Handler, handlers ...fiber.Handler) []fiber.Handler {
+	return append(append([]fiber.Handler(nil), middlewares...), handlers...)
+}
@@ -0,0 +1,119 @@
+// Package scheduler runs periodic background work with an explicit lifecycle.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultExpiryInterval is how often expiry runs when no interval is configured
+const DefaultExpiryInterval = time.Minute
+
+// Expirer is the part of the booking use case driven by the expiry scheduler
+type Expirer interface {
+	ExpireBookings(ctx context.Context) (int, error)
+	QueueExpiryCheck(ctx context.Context) error
+	// LastExpiryRun returns when ExpireBookings last completed without error
+	LastExpiryRun() time.Time
+}
+
+// ExpiryScheduler periodically queues a check for expired bookings.
+// It does nothing until Start is called and stops with Stop or its context.
+type ExpiryScheduler struct {
+	expirer  Expirer
+	interval time.Duration
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewExpiryScheduler creates a scheduler that queues an expiry check every interval
+func NewExpiryScheduler(expirer Expirer, interval time.Duration) *ExpiryScheduler {
+	if interval <= 0 {
+		interval = DefaultExpiryInterval
+	}
+
+	return &ExpiryScheduler{
+		expirer:  expirer,
+		interval: interval,
+	}
+}
+
+// Start queues an expiry check right away and then once every interval
+// until ctx is canceled or Stop is called
+func (s *ExpiryScheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return errors.New("expiry scheduler already started")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.loop(ctx, s.done)
+
+	return nil
+}
+
+// Stop stops the scheduler and waits for it to exit. It is safe to call more than once.
+func (s *ExpiryScheduler) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// RunNow expires bookings immediately, bypassing the job queue, and returns
+// how many were canceled
+func (s *ExpiryScheduler) RunNow(ctx context.Context) (int, error) {
+	return s.expirer.ExpireBookings(ctx)
+}
+
+// Running reports whether the scheduler goroutine is running
+func (s *ExpiryScheduler) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancel != nil
+}
+
+// LastRun returns when an expiry run last completed without error, whether
+// it was queued by the scheduler or started with RunNow. Queueing a check does
+// not count, so a job queue that stops running checks stops the heartbeat.
+// It is zero before the first run.
+func (s *ExpiryScheduler) LastRun() time.Time {
+	return s.expirer.LastExpiryRun()
+}
+
+// loop queues expiry checks until ctx is canceled
+func (s *ExpiryScheduler) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.expirer.QueueExpiryCheck(ctx); err != nil {
+			slog.ErrorContext(ctx, "Error queueing expired bookings check", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
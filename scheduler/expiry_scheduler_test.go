@@ -0,0 +1,127 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// queueingExpirer returns an expirer mock whose QueueExpiryCheck returns err,
+// and a counter of how often it was called
+func queueingExpirer(err error) (*mocks.BookingUseCase, *int32) {
+	calls := new(int32)
+	expirer := new(mocks.BookingUseCase)
+	expirer.On("QueueExpiryCheck", mock.Anything).
+		Run(func(args mock.Arguments) { atomic.AddInt32(calls, 1) }).
+		Return(err)
+	return expirer, calls
+}
+
+func TestExpiryScheduler_QueuesChecksUntilStopped(t *testing.T) {
+	// Arrange
+	expirer, calls := queueingExpirer(nil)
+	s := scheduler.NewExpiryScheduler(expirer, 5*time.Millisecond)
+
+	// Act
+	require.NoError(t, s.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(calls) >= 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	s.Stop()
+
+	// Assert - no more checks are queued after Stop returns
+	queued := atomic.LoadInt32(calls)
+	assert.GreaterOrEqual(t, queued, int32(2))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, queued, atomic.LoadInt32(calls))
+}
+
+func TestExpiryScheduler_StopsWithContext(t *testing.T) {
+	// Arrange
+	expirer, _ := queueingExpirer(nil)
+	s := scheduler.NewExpiryScheduler(expirer, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Act
+	require.NoError(t, s.Start(ctx))
+	cancel()
+
+	// Assert - Stop returns once the loop has exited
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+}
+
+func TestExpiryScheduler_StartTwice(t *testing.T) {
+	// Arrange
+	expirer, _ := queueingExpirer(nil)
+	s := scheduler.NewExpiryScheduler(expirer, time.Hour)
+	require.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	// Act
+	err := s.Start(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestExpiryScheduler_StopWithoutStart(t *testing.T) {
+	// Arrange
+	s := scheduler.NewExpiryScheduler(new(mocks.BookingUseCase), 0)
+
+	// Act & Assert - must not block or panic
+	s.Stop()
+	s.Stop()
+}
+
+func TestExpiryScheduler_LastRunIsLastCompletedExpiry(t *testing.T) {
+	// Arrange - checks are queued, but none has completed yet
+	expirer, calls := queueingExpirer(nil)
+	completed := time.Time{}
+	expirer.On("LastExpiryRun").Return(func() time.Time { return completed })
+	s := scheduler.NewExpiryScheduler(expirer, time.Hour)
+
+	// Act
+	require.NoError(t, s.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(calls) == 1
+	}, time.Second, time.Millisecond)
+	s.Stop()
+	beforeCompletion := s.LastRun()
+	completed = time.Now()
+
+	// Assert
+	assert.True(t, beforeCompletion.IsZero())
+	assert.Equal(t, completed, s.LastRun())
+}
+
+func TestExpiryScheduler_RunNow(t *testing.T) {
+	// Arrange
+	expirer := new(mocks.BookingUseCase)
+	expirer.On("ExpireBookings", mock.Anything).Return(3, nil)
+	s := scheduler.NewExpiryScheduler(expirer, time.Hour)
+
+	// Act
+	count, err := s.RunNow(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	expirer.AssertNotCalled(t, "QueueExpiryCheck", mock.Anything)
+}
@@ -0,0 +1,75 @@
+package tracing_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectAndExtract(t *testing.T) {
+	// Setup
+	provider := tracing.NewProvider(nil, "test")
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	// Execute
+	traceparent := tracing.Inject(ctx)
+	extracted := tracing.Extract(traceparent)
+
+	// Assert
+	assert.Equal(t, "00-"+span.SpanContext().TraceID().String()+"-"+span.SpanContext().SpanID().String()+"-01", traceparent)
+	assert.True(t, extracted.IsValid())
+	assert.True(t, extracted.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+}
+
+func TestInjectAndExtract_WithoutSpan(t *testing.T) {
+	// Execute
+	traceparent := tracing.Inject(context.Background())
+
+	// Assert
+	assert.Empty(t, traceparent)
+	assert.False(t, tracing.Extract(traceparent).IsValid())
+	assert.False(t, tracing.Extract("not-a-traceparent").IsValid())
+}
+
+func TestNewExporter_File(t *testing.T) {
+	// Setup
+	path := filepath.Join(t.TempDir(), "traces.json")
+	exporter, closeFile, err := tracing.NewExporter(tracing.Config{Exporter: tracing.ExporterFile, File: path})
+	require.NoError(t, err)
+	provider := tracing.NewProvider(exporter, "booking-system")
+
+	// Execute
+	_, span := provider.Tracer("test").Start(context.Background(), "BookingUseCase.CreateBooking",
+		trace.WithSpanKind(trace.SpanKindInternal))
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+	require.NoError(t, closeFile())
+
+	// Assert
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"BookingUseCase.CreateBooking"`)
+	assert.Contains(t, string(data), `"Value":"booking-system"`)
+}
+
+func TestNewExporter_NoneAndInvalid(t *testing.T) {
+	// Execute
+	none, _, noneErr := tracing.NewExporter(tracing.Config{})
+	_, _, missingFileErr := tracing.NewExporter(tracing.Config{Exporter: tracing.ExporterFile})
+	_, _, unknownErr := tracing.NewExporter(tracing.Config{Exporter: "zipkin"})
+
+	// Assert
+	require.NoError(t, noneErr)
+	assert.Nil(t, none)
+	assert.Error(t, missingFileErr)
+	assert.Error(t, unknownErr)
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+)
+
+// apiKeySecretPrefix marks secrets issued by this service
+const apiKeySecretPrefix = "bk_"
+
+// apiKeyDisplayLength is how much of the secret is kept as the key prefix
+const apiKeyDisplayLength = len(apiKeySecretPrefix) + 8
+
+// ErrAPIKeyNotFound is returned when an API key does not exist
+var ErrAPIKeyNotFound = repository.ErrAPIKeyNotFound
+
+// APIKeyUseCase defines the interface for API key management and authentication
+type APIKeyUseCase interface {
+	// IssueKey creates a key and returns its secret; the secret cannot be retrieved later
+	IssueKey(ctx context.Context, req *dto.IssueAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error)
+	ListKeys(ctx context.Context) ([]*models.APIKey, error)
+	RevokeKey(ctx context.Context, id string) (*models.APIKey, error)
+	// Authenticate resolves a secret to its caller; unknown, expired and revoked
+	// keys return auth.ErrInvalidCredentials
+	Authenticate(ctx context.Context, secret string) (*auth.Principal, error)
+	// EnsureAdminKey stores secret as an admin key unless a key with that secret exists
+	EnsureAdminKey(ctx context.Context, secret string) error
+}
+
+// APIKeyUseCaseImpl implements APIKeyUseCase
+type APIKeyUseCaseImpl struct {
+	repo repository.APIKeyRepository
+}
+
+// NewAPIKeyUseCase creates a new instance of APIKeyUseCaseImpl
+func NewAPIKeyUseCase(repo repository.APIKeyRepository) APIKeyUseCase {
+	return &APIKeyUseCaseImpl{
+		repo: repo,
+	}
+}
+
+// IssueKey creates a key with a random secret
+func (uc *APIKeyUseCaseImpl) IssueKey(ctx context.Context, req *dto.IssueAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error) {
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+
+	roles := req.Roles
+	if len(roles) == 0 {
+		roles = []string{auth.RoleCustomer}
+	}
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{auth.ScopeBookingsRead, auth.ScopeBookingsWrite}
+	}
+
+	key, err := uc.storeKey(ctx, req.Name, req.OwnerID, roles, scopes, req.ExpiresAt, secret, secret[:apiKeyDisplayLength])
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.IssuedAPIKeyResponse{
+		Secret: secret,
+		APIKey: key,
+	}, nil
+}
+
+// ListKeys returns every issued key, including revoked and expired ones
+func (uc *APIKeyUseCaseImpl) ListKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return uc.repo.List(ctx)
+}
+
+// RevokeKey revokes a key; requests using it are rejected from then on
+func (uc *APIKeyUseCaseImpl) RevokeKey(ctx context.Context, id string) (*models.APIKey, error) {
+	return uc.repo.Revoke(ctx, id, time.Now())
+}
+
+// Authenticate looks the secret up by its hash and checks that the key is active
+func (uc *APIKeyUseCaseImpl) Authenticate(ctx context.Context, secret string) (*auth.Principal, error) {
+	key, err := uc.repo.GetByHash(ctx, hashAPIKeySecret(secret))
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !key.Active(time.Now()) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	return &auth.Principal{
+		ID:     key.ID,
+		UserID: key.OwnerID,
+		Roles:  keyRoles(key),
+		Scopes: key.Scopes,
+		Method: auth.MethodAPIKey,
+	}, nil
+}
+
+// EnsureAdminKey makes a configured bootstrap secret usable as an admin key.
+// A key that was revoked stays revoked. No prefix is kept: unlike issued
+// secrets, a configured one may be short enough for a prefix to give it away.
+func (uc *APIKeyUseCaseImpl) EnsureAdminKey(ctx context.Context, secret string) error {
+	_, err := uc.repo.GetByHash(ctx, hashAPIKeySecret(secret))
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return err
+	}
+
+	roles := []string{auth.RoleAdmin}
+	scopes := []string{auth.ScopeAdmin, auth.ScopeBookingsRead, auth.ScopeBookingsWrite}
+	_, err = uc.storeKey(ctx, "bootstrap admin", 0, roles, scopes, nil, secret, "")
+	return err
+}
+
+// keyRoles returns the roles of a key. Keys issued before roles existed act
+// as admins when they hold the admin scope and as customers otherwise.
+func keyRoles(key *models.APIKey) []string {
+	if len(key.Roles) > 0 {
+		return key.Roles
+	}
+	for _, scope := range key.Scopes {
+		if scope == auth.ScopeAdmin {
+			return []string{auth.RoleAdmin}
+		}
+	}
+	return []string{auth.RoleCustomer}
+}
+
+// storeKey saves a key for secret; only the hash of the secret and the given
+// display prefix are stored
+func (uc *APIKeyUseCaseImpl) storeKey(ctx context.Context, name string, ownerID int64, roles, scopes []string, expiresAt *time.Time, secret, prefix string) (*models.APIKey, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.APIKey{
+		ID:        id,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hashAPIKeySecret(secret),
+		OwnerID:   ownerID,
+		Roles:     roles,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	if err := uc.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// newAPIKeySecret returns a secret with 256 bits of randomness
+func newAPIKeySecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeySecretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashAPIKeySecret returns the stored form of a secret. Secrets are random,
+// so a fast hash is enough to keep them unrecoverable from the store.
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex returns n random bytes, hex-encoded
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueKey_AuthenticatesWithSecret(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := repository.NewAPIKeyRepositoryMemory()
+	uc := usecase.NewAPIKeyUseCase(repo)
+
+	// Act
+	issued, err := uc.IssueKey(ctx, &dto.IssueAPIKeyRequest{Name: "mobile", OwnerID: 123})
+	require.NoError(t, err)
+	principal, err := uc.Authenticate(ctx, issued.Secret)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(issued.Secret, issued.APIKey.Prefix))
+	assert.Equal(t, issued.APIKey.ID, principal.ID)
+	assert.Equal(t, int64(123), principal.UserID)
+	assert.Equal(t, auth.MethodAPIKey, principal.Method)
+	assert.Equal(t, []string{auth.RoleCustomer}, principal.Roles)
+	assert.Equal(t, []string{auth.ScopeBookingsRead, auth.ScopeBookingsWrite}, principal.Scopes)
+
+	// Only the hash of the secret is stored
+	stored, err := repo.GetByID(ctx, issued.APIKey.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.Hash, issued.Secret)
+}
+
+func TestAuthenticate_RejectsUnknownRevokedAndExpiredKeys(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc := usecase.NewAPIKeyUseCase(repository.NewAPIKeyRepositoryMemory())
+
+	revoked, err := uc.IssueKey(ctx, &dto.IssueAPIKeyRequest{Name: "revoked"})
+	require.NoError(t, err)
+	_, err = uc.RevokeKey(ctx, revoked.APIKey.ID)
+	require.NoError(t, err)
+
+	soon := time.Now().Add(10 * time.Millisecond)
+	expired, err := uc.IssueKey(ctx, &dto.IssueAPIKeyRequest{Name: "expired", ExpiresAt: &soon})
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	for name, secret := range map[string]string{
+		"unknown": "bk_not-a-real-key",
+		"revoked": revoked.Secret,
+		"expired": expired.Secret,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			principal, err := uc.Authenticate(ctx, secret)
+
+			// Assert
+			assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+			assert.Nil(t, principal)
+		})
+	}
+}
+
+func TestRevokeKey_NotFound(t *testing.T) {
+	// Arrange
+	uc := usecase.NewAPIKeyUseCase(repository.NewAPIKeyRepositoryMemory())
+
+	// Act
+	_, err := uc.RevokeKey(context.Background(), "missing")
+
+	// Assert
+	assert.ErrorIs(t, err, usecase.ErrAPIKeyNotFound)
+}
+
+func TestEnsureAdminKey_IsIdempotent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc := usecase.NewAPIKeyUseCase(repository.NewAPIKeyRepositoryMemory())
+
+	// Act
+	require.NoError(t, uc.EnsureAdminKey(ctx, "configured-admin-secret"))
+	require.NoError(t, uc.EnsureAdminKey(ctx, "configured-admin-secret"))
+
+	// Assert
+	keys, err := uc.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Empty(t, keys[0].Prefix, "no part of a configured secret may be listed")
+
+	principal, err := uc.Authenticate(ctx, "configured-admin-secret")
+	require.NoError(t, err)
+	assert.True(t, principal.HasScope(auth.ScopeAdmin))
+	assert.True(t, principal.HasRole(auth.RoleAdmin))
+}
+
+func TestAuthenticate_KeysWithoutRoles(t *testing.T) {
+	// Arrange - keys stored before roles were introduced
+	ctx := context.Background()
+	repo := repository.NewAPIKeyRepositoryMemory()
+	uc := usecase.NewAPIKeyUseCase(repo)
+	legacy := map[string]*models.APIKey{
+		"bk_legacy-admin":    {ID: "admin", Hash: sha256Hex("bk_legacy-admin"), Scopes: []string{auth.ScopeAdmin}},
+		"bk_legacy-customer": {ID: "customer", Hash: sha256Hex("bk_legacy-customer"), OwnerID: 7, Scopes: []string{auth.ScopeBookingsRead}},
+	}
+	for _, key := range legacy {
+		require.NoError(t, repo.Create(ctx, key))
+	}
+
+	// Act
+	admin, adminErr := uc.Authenticate(ctx, "bk_legacy-admin")
+	customer, customerErr := uc.Authenticate(ctx, "bk_legacy-customer")
+
+	// Assert
+	require.NoError(t, adminErr)
+	require.NoError(t, customerErr)
+	assert.Equal(t, []string{auth.RoleAdmin}, admin.Roles)
+	assert.Equal(t, []string{auth.RoleCustomer}, customer.Roles)
+}
+
+// sha256Hex hashes a secret the way stored keys are hashed
+func sha256Hex(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+)
+
+// ErrForbidden is returned when the caller's role does not allow an action
+var ErrForbidden = errors.New("forbidden")
+
+// errUnauthenticated is returned for calls with neither a principal nor the internal caller mark
+var errUnauthenticated = fmt.Errorf("%w: caller is not authenticated", ErrForbidden)
+
+// internalCallerKey marks contexts of calls made by the service itself
+type internalCallerKey struct{}
+
+// WithInternalCaller returns a copy of ctx for calls made by the service
+// itself, such as background jobs and the expiry scheduler, which may access
+// every booking. Calls without a principal or this mark are forbidden.
+func WithInternalCaller(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalCallerKey{}, true)
+}
+
+// caller is the principal behind a use case call, or the service itself when
+// internal is set
+type caller struct {
+	principal *auth.Principal
+	internal  bool
+}
+
+// callerFrom returns the caller carried by ctx. A principal takes precedence
+// over the internal mark.
+func callerFrom(ctx context.Context) caller {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	internal, _ := ctx.Value(internalCallerKey{}).(bool)
+	return caller{principal: principal, internal: internal && principal == nil}
+}
+
+// role returns the role that decides what the caller may do. Principals
+// without a known role are treated as customers.
+func (c caller) role() string {
+	switch {
+	case c.principal == nil:
+		return ""
+	case c.principal.HasRole(auth.RoleAdmin):
+		return auth.RoleAdmin
+	case c.principal.HasRole(auth.RoleOperator):
+		return auth.RoleOperator
+	default:
+		return auth.RoleCustomer
+	}
+}
+
+// restrictedTo returns the user whose bookings a customer is limited to, and
+// false for callers that may access every booking. Unauthenticated callers get
+// ErrForbidden, and so does a customer that is not linked to a user, such as
+// an API key issued without an owner, since a zero user ID would otherwise
+// match every booking.
+func (c caller) restrictedTo() (int64, bool, error) {
+	switch {
+	case c.internal:
+		return 0, false, nil
+	case c.principal == nil:
+		return 0, true, errUnauthenticated
+	case c.role() != auth.RoleCustomer:
+		return 0, false, nil
+	case c.principal.UserID <= 0:
+		return 0, true, fmt.Errorf("%w: customer is not linked to a user", ErrForbidden)
+	}
+	return c.principal.UserID, true, nil
+}
+
+// canAccess reports whether the caller may see and change booking
+func (c caller) canAccess(booking *models.Booking) bool {
+	userID, restricted, err := c.restrictedTo()
+	return err == nil && (!restricted || booking.UserID == userID)
+}
+
+// actor returns the state machine actor for changes made by the caller
+func (c caller) actor() models.TransitionActor {
+	switch c.role() {
+	case auth.RoleAdmin:
+		return models.ActorAdmin
+	case auth.RoleOperator:
+		return models.ActorOperator
+	default:
+		return models.ActorCustomer
+	}
+}
+
+// authorizeBooking hides bookings the caller may not access behind
+// ErrBookingNotFound, so customers cannot probe for other users' bookings.
+// Unauthenticated callers get ErrForbidden.
+func (c caller) authorizeBooking(booking *models.Booking) error {
+	if c.principal == nil && !c.internal {
+		return errUnauthenticated
+	}
+	if !c.canAccess(booking) {
+		return repository.ErrBookingNotFound
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/auth"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The seeded repository holds bookings 1-10 owned by users 101-110;
+// booking 1 is pending and booking 3 is confirmed.
+
+// internalCtx is the context of calls the service makes itself, which may access every booking
+var internalCtx = usecase.WithInternalCaller(context.Background())
+
+// asRole returns a context carrying a principal with a single role
+func asRole(role string, userID int64) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		ID:     "test",
+		UserID: userID,
+		Roles:  []string{role},
+	})
+}
+
+func newSeededUseCase() usecase.BookingUseCase {
+	repo := repository.NewBookingRepositoryMock()
+	return usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+}
+
+func TestGetBookingByID_CustomerSeesOnlyOwnBookings(t *testing.T) {
+	// Arrange
+	uc := newSeededUseCase()
+	ctx := asRole(auth.RoleCustomer, 101)
+
+	// Act
+	own, ownErr := uc.GetBookingByID(ctx, 1)
+	_, otherErr := uc.GetBookingByID(ctx, 2)
+
+	// Assert - other users' bookings look like they do not exist
+	require.NoError(t, ownErr)
+	assert.Equal(t, int64(101), own.UserID)
+	assert.ErrorIs(t, otherErr, repository.ErrBookingNotFound)
+}
+
+func TestGetBookingByID_CachedBookingIsAuthorized(t *testing.T) {
+	// Arrange - an operator read puts booking 2 in the cache
+	uc := newSeededUseCase()
+	_, err := uc.GetBookingByID(asRole(auth.RoleOperator, 0), 2)
+	require.NoError(t, err)
+
+	// Act
+	_, err = uc.GetBookingByID(asRole(auth.RoleCustomer, 101), 2)
+
+	// Assert
+	assert.ErrorIs(t, err, repository.ErrBookingNotFound)
+}
+
+func TestCreateBooking_CustomerCannotBookForAnotherUser(t *testing.T) {
+	// Arrange
+	uc := newSeededUseCase()
+	req := &dto.CreateBookingRequest{UserID: 102, ServiceID: 1, Price: 1000}
+
+	// Act
+	_, err := uc.CreateBooking(asRole(auth.RoleCustomer, 101), req)
+
+	// Assert
+	assert.ErrorIs(t, err, usecase.ErrForbidden)
+}
+
+func TestGetAllBookings_CustomerListIsLimitedToOwnBookings(t *testing.T) {
+	// Arrange
+	uc := newSeededUseCase()
+	ctx := asRole(auth.RoleCustomer, 104)
+
+	// Act
+	page, err := uc.GetAllBookings(ctx, &dto.BookingsQueryParams{})
+	_, otherErr := uc.GetAllBookings(ctx, &dto.BookingsQueryParams{UserID: 105})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, int64(104), page.Data[0].UserID)
+	assert.ErrorIs(t, otherErr, usecase.ErrForbidden)
+}
+
+func TestGetAllBookings_CustomerWithoutUserIsForbidden(t *testing.T) {
+	// Arrange - e.g. an API key issued without an owner, whose zero user ID would match every booking
+	uc := newSeededUseCase()
+	ctx := asRole(auth.RoleCustomer, 0)
+
+	// Act
+	_, listErr := uc.GetAllBookings(ctx, &dto.BookingsQueryParams{})
+	_, getErr := uc.GetBookingByID(ctx, 1)
+	_, createErr := uc.CreateBooking(ctx, &dto.CreateBookingRequest{ServiceID: 1, Price: 1000})
+
+	// Assert
+	assert.ErrorIs(t, listErr, usecase.ErrForbidden)
+	assert.ErrorIs(t, getErr, repository.ErrBookingNotFound)
+	assert.ErrorIs(t, createErr, usecase.ErrForbidden)
+}
+
+func TestBookingUseCase_UnauthenticatedCallerIsForbidden(t *testing.T) {
+	// Arrange - a context with neither a principal nor the internal caller mark
+	uc := newSeededUseCase()
+	ctx := context.Background()
+
+	// Act
+	_, getErr := uc.GetBookingByID(ctx, 1)
+	_, cancelErr := uc.CancelBooking(ctx, 1, 0)
+	_, listErr := uc.GetAllBookings(ctx, &dto.BookingsQueryParams{})
+	_, createErr := uc.CreateBooking(ctx, &dto.CreateBookingRequest{UserID: 101, ServiceID: 1, Price: 1000})
+
+	// Assert - and the booking is left alone
+	assert.ErrorIs(t, getErr, usecase.ErrForbidden)
+	assert.ErrorIs(t, cancelErr, usecase.ErrForbidden)
+	assert.ErrorIs(t, listErr, usecase.ErrForbidden)
+	assert.ErrorIs(t, createErr, usecase.ErrForbidden)
+	booking, err := uc.GetBookingByID(internalCtx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusPending, booking.Status)
+}
+
+func TestGetAllBookings_OperatorSeesAllBookings(t *testing.T) {
+	// Arrange
+	uc := newSeededUseCase()
+
+	// Act
+	page, err := uc.GetAllBookings(asRole(auth.RoleOperator, 0), &dto.BookingsQueryParams{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, page.Data, 10)
+}
+
+func TestCancelBooking_CustomerCannotCancelAnotherUsersBooking(t *testing.T) {
+	// Arrange
+	uc := newSeededUseCase()
+
+	// Act
+	_, err := uc.CancelBooking(asRole(auth.RoleCustomer, 102), 1, 0)
+
+	// Assert
+	assert.ErrorIs(t, err, repository.ErrBookingNotFound)
+}
+
+func TestCancelBooking_ForceCancelConfirmed(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      string
+		forbidden bool
+	}{
+		{"operator cannot force-cancel", auth.RoleOperator, true},
+		{"admin can force-cancel", auth.RoleAdmin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			uc := newSeededUseCase()
+
+			// Act
+			booking, err := uc.CancelBooking(asRole(tt.role, 0), 3, 0)
+
+			// Assert
+			if tt.forbidden {
+				var transitionErr *models.TransitionError
+				require.ErrorAs(t, err, &transitionErr)
+				assert.True(t, transitionErr.Forbidden)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.BookingStatusCanceled, booking.Status)
+		})
+	}
+}
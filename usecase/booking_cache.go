@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+)
+
+// DefaultNotFoundTTL is how long a booking ID that was not found is answered
+// from the cache, so repeated lookups of a missing booking do not reach the repository
+const DefaultNotFoundTTL = 5 * time.Second
+
+// BookingCacheNamespace is the namespace of bookings in a shared cache
+const BookingCacheNamespace = "booking"
+
+// BookingCache caches bookings by ID
+type BookingCache = utils.Cache[int64, *models.Booking]
+
+// NewBookingCache returns the booking namespace of store, so bookings are
+// kept apart from anything else cached there
+func NewBookingCache(store utils.Cache[utils.CacheKey, any]) BookingCache {
+	return utils.NewNamespacedCache[int64, *models.Booking](store, BookingCacheNamespace)
+}
+
+// WithNotFoundTTL sets how long booking IDs that were not found are
+// remembered; zero looks them up every time
+func WithNotFoundTTL(ttl time.Duration) Option {
+	return func(uc *BookingUseCaseImpl) {
+		uc.notFoundTTL = ttl
+	}
+}
+
+// newLoadingBookingCache makes cache read-through, remembering missing bookings
+// for ttl. Bookings are copied in and out of the cache, so a booking the use
+// case changes, e.g. while canceling it, is never the one other callers see.
+func newLoadingBookingCache(cache BookingCache, ttl time.Duration) *utils.LoadingCache[int64, *models.Booking] {
+	return utils.NewLoadingCache[int64, *models.Booking](utils.NewCloningCache(cache), utils.WithNegativeCaching(ttl, func(err error) bool {
+		return errors.Is(err, ErrNotFound)
+	}))
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+)
+
+// ErrInvalidCursor is returned for a cursor that is malformed or was issued for a different sort order.
+// It matches ErrValidation.
+var ErrInvalidCursor error = &ValidationError{Field: "cursor", Reason: "is malformed or was issued for a different sort or order"}
+
+// pageCursor is the JSON form of an opaque pagination cursor. It records the
+// sort order it was issued for so it cannot be replayed against another one.
+type pageCursor struct {
+	Sort       repository.BookingSortField `json:"s"`
+	Descending bool                        `json:"d,omitempty"`
+	ID         int64                       `json:"i"`
+	Price      float64                     `json:"p,omitempty"`
+	CreatedAt  int64                       `json:"c,omitempty"`
+}
+
+// encodeCursor turns the position of the last booking on a page into an opaque cursor
+func encodeCursor(query repository.BookingQuery, position *repository.BookingCursor) string {
+	cursor := pageCursor{
+		Sort:       query.SortBy,
+		Descending: query.Descending,
+		ID:         position.ID,
+	}
+	switch query.SortBy {
+	case repository.SortByPrice:
+		cursor.Price = position.Price
+	case repository.SortByCreatedAt:
+		cursor.CreatedAt = position.CreatedAt.UnixNano()
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor issued by encodeCursor for the same sort order
+func decodeCursor(value string, query repository.BookingQuery) (*repository.BookingCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != query.SortBy || cursor.Descending != query.Descending {
+		return nil, ErrInvalidCursor
+	}
+
+	return &repository.BookingCursor{
+		ID:        cursor.ID,
+		Price:     cursor.Price,
+		CreatedAt: time.Unix(0, cursor.CreatedAt),
+	}, nil
+}
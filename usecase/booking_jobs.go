@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+)
+
+// Job types run by the booking use case
+const (
+	// JobTypeCreditCheck runs the credit check of a high-value booking
+	JobTypeCreditCheck = "credit_check"
+	// JobTypeExpireBookings cancels pending bookings that have expired
+	JobTypeExpireBookings = "expire_bookings"
+)
+
+// JobQueue runs the use case's background work as persisted jobs
+type JobQueue interface {
+	Register(jobType string, handler jobs.Handler)
+	Enqueue(ctx context.Context, jobType string, payload interface{}) (*jobs.Job, error)
+}
+
+// creditCheckPayload is the payload of a credit check job
+type creditCheckPayload struct {
+	BookingID int64 `json:"booking_id"`
+}
+
+// internalJob runs handler as the service itself, since jobs carry no principal
+func internalJob(handler jobs.Handler) jobs.Handler {
+	return func(ctx context.Context, job *jobs.Job) error {
+		return handler(WithInternalCaller(ctx), job)
+	}
+}
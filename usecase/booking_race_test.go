@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests are meant to be run with -race: they drive every path that
+// reads or writes cached bookings at once.
+
+// priceChecker approves bookings up to a limit without delay
+type priceChecker struct {
+	limit float64
+}
+
+func (c priceChecker) Name() string { return "price" }
+
+func (c priceChecker) CheckCredit(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error) {
+	return &models.CreditDecision{Approved: booking.Price <= c.limit, Reason: "price limit"}, nil
+}
+
+// expectedRaceError reports whether err is an outcome of losing a race, as
+// opposed to a bug
+func expectedRaceError(err error) bool {
+	return err == nil ||
+		errors.Is(err, usecase.ErrNotFound) ||
+		errors.Is(err, usecase.ErrConflict) ||
+		errors.Is(err, usecase.ErrInvalidTransition)
+}
+
+func TestBookingUseCase_ConcurrentStress(t *testing.T) {
+	// Arrange - bookings expire quickly so the expiry path competes with cancels and credit checks
+	repo := repository.NewBookingRepositoryMock()
+	queue := jobs.NewQueue(jobs.NewMemoryStore(), jobs.Options{
+		Concurrency:  4,
+		MaxAttempts:  1,
+		PollInterval: time.Millisecond,
+	})
+	require.NoError(t, queue.Start(context.Background()))
+	defer queue.Stop()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), priceChecker{limit: 80000}, queue,
+		usecase.WithExpiryPolicy(usecase.ExpiryPolicy{DefaultTTL: 20 * time.Millisecond}))
+
+	ctx := internalCtx
+	deadline := time.Now().Add(300 * time.Millisecond)
+	var lastID atomic.Int64
+	lastID.Store(10)
+	randomID := func(r *rand.Rand) int64 {
+		return 1 + r.Int63n(lastID.Load()+2) // sometimes an ID that does not exist
+	}
+
+	var wg sync.WaitGroup
+	var failures sync.Map
+	worker := func(name string, seed int64, step func(r *rand.Rand) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for time.Now().Before(deadline) {
+				if err := step(r); !expectedRaceError(err) {
+					failures.Store(name, err)
+					return
+				}
+			}
+		}()
+	}
+
+	// Act
+	for i := 0; i < 2; i++ {
+		worker("create", int64(i), func(r *rand.Rand) error {
+			booking, err := uc.CreateBooking(ctx, &dto.CreateBookingRequest{
+				UserID:    1 + r.Int63n(5),
+				ServiceID: 1,
+				Price:     float64(10000 * (1 + r.Intn(10))),
+			})
+			if err == nil {
+				lastID.Store(booking.ID)
+			}
+			return err
+		})
+	}
+	for i := 0; i < 4; i++ {
+		worker("get", int64(10+i), func(r *rand.Rand) error {
+			booking, err := uc.GetBookingByID(ctx, randomID(r))
+			if err == nil {
+				// Callers own what they get; this must not reach the cache or other readers
+				booking.Status = "corrupted"
+				booking.Version = -1
+			}
+			return err
+		})
+	}
+	for i := 0; i < 2; i++ {
+		worker("cancel", int64(20+i), func(r *rand.Rand) error {
+			_, err := uc.CancelBooking(ctx, randomID(r), 0)
+			return err
+		})
+	}
+	worker("list", 30, func(r *rand.Rand) error {
+		_, err := uc.GetAllBookings(ctx, &dto.BookingsQueryParams{Limit: 10})
+		return err
+	})
+	worker("expire", 31, func(r *rand.Rand) error {
+		_, err := uc.ExpireBookings(ctx)
+		return err
+	})
+	wg.Wait()
+	queue.Stop()
+
+	// Assert - no unexpected errors, and every cached booking matches the repository
+	failures.Range(func(name, err any) bool {
+		t.Errorf("%s: %v", name, err)
+		return true
+	})
+	for id := int64(1); id <= lastID.Load(); id++ {
+		stored, err := repo.GetByID(ctx, id)
+		require.NoError(t, err)
+		cached, err := uc.GetBookingByID(ctx, id)
+		require.NoError(t, err)
+		assert.True(t, cached.Status.IsValid(), "booking %d has status %q", id, cached.Status)
+		assert.Equal(t, stored.Version, cached.Version, "booking %d", id)
+		assert.Equal(t, stored.Status, cached.Status, "booking %d", id)
+	}
+}
+
+func TestGetBookingByID_ReturnsCopy(t *testing.T) {
+	// Arrange
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+	first, err := uc.GetBookingByID(internalCtx, 1)
+	require.NoError(t, err)
+
+	// Act
+	first.Status = models.BookingStatusCanceled
+
+	// Assert
+	second, err := uc.GetBookingByID(internalCtx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusPending, second.Status)
+	assert.NotSame(t, first, second)
+}
+
+func TestCancelBooking_FailedUpdateLeavesCachedBooking(t *testing.T) {
+	// Arrange - the booking is cached by the first read
+	mockRepo := new(mocks.BookingRepository)
+	booking := &models.Booking{ID: 1, UserID: 101, Status: models.BookingStatusPending, Version: 1}
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(booking, nil).Once()
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil, errors.New("database is down"))
+	uc := usecase.NewBookingUseCase(mockRepo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+
+	// Act
+	_, err := uc.CancelBooking(internalCtx, 1, 0)
+
+	// Assert - the cancel changed its own copy, not the cached one
+	require.Error(t, err)
+	cached, err := uc.GetBookingByID(internalCtx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusPending, cached.Status)
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
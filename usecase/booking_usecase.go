@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"math/rand"
+	"log/slog"
+	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/logging"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
@@ -18,38 +21,104 @@ import (
 type BookingUseCase interface {
 	CreateBooking(ctx context.Context, req *dto.CreateBookingRequest) (*models.Booking, error)
 	GetBookingByID(ctx context.Context, id int64) (*models.Booking, error)
-	GetAllBookings(ctx context.Context, params *dto.BookingsQueryParams) ([]*models.Booking, error)
-	CancelBooking(ctx context.Context, id int64) (*models.Booking, error)
+	GetAllBookings(ctx context.Context, params *dto.BookingsQueryParams) (*dto.BookingListResponse, error)
+	CancelBooking(ctx context.Context, id int64, expectedVersion int64) (*models.Booking, error)
+	// ExpireBookings cancels pending bookings past their expiry time and returns how many it canceled
+	ExpireBookings(ctx context.Context) (int, error)
+	// QueueExpiryCheck queues an ExpireBookings run on the job queue
+	QueueExpiryCheck(ctx context.Context) error
+	// LastExpiryRun returns when ExpireBookings last completed without error; it is zero before the first run
+	LastExpiryRun() time.Time
 }
 
+// Page sizes for GetAllBookings
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// DefaultHighValueThreshold is the price above which a booking needs a credit
+// check when no threshold is configured
+const DefaultHighValueThreshold = 50000.0
+
+// ErrVersionConflict is returned when a booking was modified since the caller read it
+var ErrVersionConflict = repository.ErrVersionConflict
+
 // BookingUseCaseImpl implements BookingUseCase
 type BookingUseCaseImpl struct {
-	repo  repository.BookingRepository
-	cache utils.Cache
+	repo          repository.BookingRepository
+	cache         *utils.LoadingCache[int64, *models.Booking]
+	creditChecker CreditChecker
+	creditPolicy  CreditCheckPolicy
+	expiryPolicy  ExpiryPolicy
+	jobQueue      JobQueue
+	metrics       Metrics
+	// highValueThreshold is the price above which a booking needs a credit check
+	highValueThreshold float64
+	// notFoundTTL is how long booking IDs that were not found are remembered
+	notFoundTTL time.Duration
+	// lastExpiryRun is when ExpireBookings last completed, in Unix nanoseconds
+	lastExpiryRun atomic.Int64
 }
 
-// NewBookingUseCase creates a new instance of BookingUseCaseImpl
-func NewBookingUseCase(repo repository.BookingRepository, cache utils.Cache) BookingUseCase {
+// NewBookingUseCase creates a new instance of BookingUseCaseImpl.
+// It registers the credit check and expiry job handlers on the job queue.
+func NewBookingUseCase(repo repository.BookingRepository, cache BookingCache, creditChecker CreditChecker, jobQueue JobQueue, opts ...Option) BookingUseCase {
 	uc := &BookingUseCaseImpl{
-		repo:  repo,
-		cache: cache,
+		repo:          repo,
+		creditChecker: creditChecker,
+		creditPolicy:  DefaultCreditCheckPolicy(),
+		expiryPolicy:  DefaultExpiryPolicy(),
+		jobQueue:      jobQueue,
+		metrics:       noopMetrics{},
+
+		highValueThreshold: DefaultHighValueThreshold,
+		notFoundTTL:        DefaultNotFoundTTL,
+	}
+
+	for _, opt := range opts {
+		opt(uc)
 	}
+	uc.cache = newLoadingBookingCache(cache, uc.notFoundTTL)
 
-	// Start background task to check for expired bookings
-	go uc.checkExpiredBookings()
+	jobQueue.Register(JobTypeCreditCheck, internalJob(uc.runCreditCheckJob))
+	jobQueue.Register(JobTypeExpireBookings, internalJob(uc.runExpireBookingsJob))
 
 	return uc
 }
 
-// CreateBooking creates a new booking
+// WithHighValueThreshold sets the price above which bookings need a credit
+// check and count as high-value in listings
+func WithHighValueThreshold(threshold float64) Option {
+	return func(uc *BookingUseCaseImpl) {
+		uc.highValueThreshold = threshold
+	}
+}
+
+// CreateBooking creates a new booking. Customers may only book for themselves.
 func (uc *BookingUseCaseImpl) CreateBooking(ctx context.Context, req *dto.CreateBookingRequest) (*models.Booking, error) {
+	userID, restricted, err := callerFrom(ctx).restrictedTo()
+	if err != nil {
+		return nil, err
+	}
+	if restricted && req.UserID != userID {
+		return nil, fmt.Errorf("%w: customers can only create bookings for themselves", ErrForbidden)
+	}
+
+	now := time.Now()
 	booking := &models.Booking{
 		UserID:    req.UserID,
 		ServiceID: req.ServiceID,
 		Price:     req.Price,
 		Status:    models.BookingStatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(uc.expiryPolicy.TTL(req.ServiceID)),
+	}
+
+	// A caller-supplied expiry time overrides the service TTL
+	if req.ExpiresAt != nil {
+		booking.ExpiresAt = *req.ExpiresAt
 	}
 
 	// Save booking to repository
@@ -59,189 +128,283 @@ func (uc *BookingUseCaseImpl) CreateBooking(ctx context.Context, req *dto.Create
 	}
 
 	// Store in cache
-	cacheKey := fmt.Sprintf("booking:%d", newBooking.ID)
-	uc.cache.Set(cacheKey, newBooking)
-
-	// For high-value bookings, run credit check in background
-	if newBooking.Price > 50000 {
-		go uc.checkCredit(ctx, newBooking)
+	uc.cache.Set(newBooking.ID, newBooking)
+	uc.metrics.BookingStatus(newBooking.Status)
+
+	// For high-value bookings, queue a credit check to run in background
+	if newBooking.Price > uc.highValueThreshold {
+		if _, err := uc.jobQueue.Enqueue(ctx, JobTypeCreditCheck, creditCheckPayload{BookingID: newBooking.ID}); err != nil {
+			// The booking stays pending and is canceled by the expiry job
+			slog.ErrorContext(ctx, "Error queueing credit check", "booking_id", newBooking.ID, "error", err)
+		}
 	}
 
 	return newBooking, nil
 }
 
-// GetBookingByID retrieves a booking by ID
+// GetBookingByID retrieves a booking by ID. Customers get ErrBookingNotFound
+// for bookings of other users.
 func (uc *BookingUseCaseImpl) GetBookingByID(ctx context.Context, id int64) (*models.Booking, error) {
-	// Try to get from cache first
-	cacheKey := fmt.Sprintf("booking:%d", id)
-	if cachedValue, found := uc.cache.Get(cacheKey); found {
-		log.Println("Booking retrieved from cache")
-		return cachedValue.(*models.Booking), nil
-	}
-
-	// If not in cache, get from repository
-	booking, err := uc.repo.GetByID(ctx, id)
+	// Read through the cache; concurrent misses for the same booking share
+	// one repository read, and IDs that were not found are remembered briefly
+	booking, err := uc.cache.GetOrLoad(id, func() (*models.Booking, error) {
+		slog.DebugContext(ctx, "Booking not in cache, loading from repository", "booking_id", id)
+		return uc.repo.GetByID(ctx, id)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Add to cache for future use
-	uc.cache.Set(cacheKey, booking)
-	log.Println("Booking retrieved from repository and added to cache")
+	if err := callerFrom(ctx).authorizeBooking(booking); err != nil {
+		return nil, err
+	}
 
 	return booking, nil
 }
 
-// GetAllBookings retrieves all bookings with optional sorting and filtering
-func (uc *BookingUseCaseImpl) GetAllBookings(ctx context.Context, params *dto.BookingsQueryParams) ([]*models.Booking, error) {
-	// Get all bookings from repository - this is our source of truth
-	bookings, err := uc.repo.GetAll(ctx)
+// GetAllBookings returns one page of bookings matching the filters, sorted as requested.
+// Customers only see their own bookings; filtering on another user is forbidden.
+func (uc *BookingUseCaseImpl) GetAllBookings(ctx context.Context, params *dto.BookingsQueryParams) (*dto.BookingListResponse, error) {
+	userID := params.UserID
+	ownID, restricted, err := callerFrom(ctx).restrictedTo()
 	if err != nil {
 		return nil, err
 	}
+	if restricted {
+		if userID != 0 && userID != ownID {
+			return nil, fmt.Errorf("%w: customers can only list their own bookings", ErrForbidden)
+		}
+		userID = ownID
+	}
 
-	// Check cache for any new bookings not yet in repository
-	cacheBookings := uc.cache.GetAll()
-	bookingMap := make(map[int64]*models.Booking)
-
-	// First, add all bookings from repository to our map
-	for _, booking := range bookings {
-		bookingMap[booking.ID] = booking
+	query := repository.BookingQuery{
+		Status:     models.BookingStatus(params.Status),
+		UserID:     userID,
+		ServiceID:  params.ServiceID,
+		MinPrice:   params.MinPrice,
+		MaxPrice:   params.MaxPrice,
+		SortBy:     repository.SortByID,
+		Descending: params.Order == "desc",
+		Limit:      params.Limit,
 	}
 
-	// Then, check for any bookings in cache that might not be in repository yet
-	for _, cachedValue := range cacheBookings {
-		cacheBooking := cachedValue.(*models.Booking)
-		// Only add if it doesn't exist in our map (to avoid duplicates)
-		if _, exists := bookingMap[cacheBooking.ID]; !exists {
-			bookingMap[cacheBooking.ID] = cacheBooking
-		}
+	switch params.Sort {
+	case "price":
+		query.SortBy = repository.SortByPrice
+	case "date":
+		query.SortBy = repository.SortByCreatedAt
 	}
 
-	// Convert map back to slice
-	mergedBookings := make([]*models.Booking, 0, len(bookingMap))
-	for _, booking := range bookingMap {
-		mergedBookings = append(mergedBookings, booking)
+	if params.CreatedFrom != nil {
+		query.CreatedFrom = *params.CreatedFrom
+	}
+	if params.CreatedTo != nil {
+		query.CreatedTo = *params.CreatedTo
 	}
 
-	// Filter high-value bookings if requested
+	// High-value bookings are priced strictly above the threshold
 	if params.HighValue {
-		mergedBookings = utils.FilterHighValueBookings(mergedBookings)
+		threshold := math.Nextafter(uc.highValueThreshold, math.Inf(1))
+		if query.MinPrice == nil || *query.MinPrice < threshold {
+			query.MinPrice = &threshold
+		}
 	}
 
-	// Sort bookings if requested
-	switch params.Sort {
-	case "price":
-		utils.SortBookingsByPrice(mergedBookings, true)
-	case "date":
-		utils.SortBookingsByDate(mergedBookings, true)
+	if query.Limit <= 0 {
+		query.Limit = DefaultPageSize
+	}
+	if query.Limit > MaxPageSize {
+		query.Limit = MaxPageSize
 	}
 
-	return mergedBookings, nil
-}
+	if params.Cursor != "" {
+		after, err := decodeCursor(params.Cursor, query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+	}
 
-// CancelBooking cancels a booking
-func (uc *BookingUseCaseImpl) CancelBooking(ctx context.Context, id int64) (*models.Booking, error) {
-	// Get booking
-	booking, err := uc.GetBookingByID(ctx, id)
+	page, err := uc.repo.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	// Cannot cancel a confirmed booking
-	if booking.Status == models.BookingStatusConfirmed {
-		return nil, errors.New("cannot cancel a confirmed booking")
+	response := &dto.BookingListResponse{Data: page.Bookings}
+	if page.Next != nil {
+		response.NextCursor = encodeCursor(query, page.Next)
 	}
 
-	// Update status to canceled
-	booking.Status = models.BookingStatusCanceled
-	booking.UpdatedAt = time.Now()
+	return response, nil
+}
 
-	// Update in repository
-	updatedBooking, err := uc.repo.Update(ctx, booking)
+// CancelBooking cancels a booking.
+// A non-zero expectedVersion makes the cancellation conditional on the booking
+// still being at that version; otherwise a concurrent update is retried once
+// against a fresh read from the repository. Customers can only cancel their own
+// bookings, and only admins can force-cancel a confirmed booking.
+func (uc *BookingUseCaseImpl) CancelBooking(ctx context.Context, id int64, expectedVersion int64) (*models.Booking, error) {
+	// Get booking
+	booking, err := uc.GetBookingByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update in cache
-	cacheKey := fmt.Sprintf("booking:%d", id)
-	uc.cache.Delete(cacheKey)
+	for attempt := 0; ; attempt++ {
+		// The cached copy may be stale, so a mismatch earns one fresh read
+		canRetry := attempt == 0
 
-	return updatedBooking, nil
-}
+		if expectedVersion != 0 && booking.Version != expectedVersion {
+			if canRetry {
+				if booking, err = uc.reloadBooking(ctx, id); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, &repository.VersionConflictError{
+				ID:              id,
+				ExpectedVersion: expectedVersion,
+				CurrentVersion:  booking.Version,
+			}
+		}
 
-// checkCredit simulates a credit check for high-value bookings
-func (uc *BookingUseCaseImpl) checkCredit(ctx context.Context, booking *models.Booking) {
-	// Simulate some processing time
-	time.Sleep(2 * time.Second)
+		// Update status to canceled; the state machine decides which callers may cancel which bookings
+		if err := booking.TransitionTo(models.BookingStatusCanceled, callerFrom(ctx).actor(), time.Now()); err != nil {
+			return nil, err
+		}
 
-	// Random credit check result (70% success rate)
-	rand.Seed(time.Now().UnixNano())
-	status := models.BookingStatusConfirmed
-	if rand.Float64() < 0.3 { // 30% chance of rejection
-		status = models.BookingStatusRejected
+		// Update in repository
+		updatedBooking, err := uc.repo.Update(ctx, booking)
+		if errors.Is(err, ErrVersionConflict) && expectedVersion == 0 && canRetry {
+			if booking, err = uc.reloadBooking(ctx, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Update in cache
+		uc.cache.Delete(id)
+		uc.metrics.BookingStatus(updatedBooking.Status)
+
+		return updatedBooking, nil
 	}
+}
 
-	// Update booking status
-	booking.Status = status
-	booking.UpdatedAt = time.Now()
+// reloadBooking drops the cached copy of a booking and reads it from the repository
+func (uc *BookingUseCaseImpl) reloadBooking(ctx context.Context, id int64) (*models.Booking, error) {
+	uc.cache.Delete(id)
+	return uc.repo.GetByID(ctx, id)
+}
 
-	// Update in repository
-	updatedBooking, err := uc.repo.Update(ctx, booking)
+// runCreditCheckJob runs the credit check for a high-value booking and records the result on it.
+// A check that could not reach a decision is returned as an error so the queue retries it.
+func (uc *BookingUseCaseImpl) runCreditCheckJob(ctx context.Context, job *jobs.Job) error {
+	var payload creditCheckPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode credit check payload: %w", err))
+	}
+	ctx = logging.With(ctx, "booking_id", payload.BookingID)
+
+	booking, err := uc.repo.GetByID(ctx, payload.BookingID)
+	if errors.Is(err, repository.ErrBookingNotFound) {
+		return jobs.Permanent(err)
+	}
 	if err != nil {
-		log.Printf("Error updating booking after credit check: %v", err)
-		return
+		return err
+	}
+	if booking.Status != models.BookingStatusPending {
+		slog.InfoContext(ctx, "Skipping credit check for booking that is no longer pending", "status", booking.Status)
+		return nil
 	}
 
-	// Update in cache
-	cacheKey := fmt.Sprintf("booking:%d", booking.ID)
-	uc.cache.Set(cacheKey, updatedBooking)
+	check := uc.runCreditCheck(ctx, booking)
 
-	log.Printf("Credit check completed for booking %d. Status: %s", booking.ID, status)
-}
+	// The queue is shutting down; the job runs again after restart
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := uc.applyCreditCheck(ctx, booking.ID, check); err != nil {
+		return err
+	}
 
-// checkExpiredBookings runs as a background task to cancel expired bookings
-func (uc *BookingUseCaseImpl) checkExpiredBookings() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+	// runCreditCheck has already retried; retrying the job as well would
+	// multiply the calls to the checker during an outage
+	if check.Outcome == models.CreditCheckFailed {
+		return jobs.Permanent(fmt.Errorf("credit check for booking %d failed: %s", booking.ID, check.Reason))
+	}
 
-	for range ticker.C {
-		log.Println("Running expired bookings check...")
-		ctx := context.Background()
+	return nil
+}
 
-		// Get all bookings from repository
-		bookings, err := uc.repo.GetAll(ctx)
+// creditCheckApplyAttempts bounds how often a credit check result is applied
+// again after the booking changed underneath it
+const creditCheckApplyAttempts = 5
+
+// applyCreditCheck records a credit check result on the latest state of the booking,
+// which may have been canceled or expired while the check was running. A
+// concurrent update that leaves the booking pending is retried on a fresh read.
+func (uc *BookingUseCaseImpl) applyCreditCheck(ctx context.Context, id int64, check *models.CreditCheck) error {
+	var updatedBooking *models.Booking
+	for attempt := 1; ; attempt++ {
+		current, err := uc.repo.GetByID(ctx, id)
 		if err != nil {
-			log.Printf("Error fetching bookings: %v", err)
-			continue
+			return fmt.Errorf("load booking %d after credit check: %w", id, err)
+		}
+		if !recordCreditCheck(ctx, current, check) {
+			return nil
 		}
 
-		now := time.Now()
-		expiredCount := 0
+		updatedBooking, err = uc.repo.Update(ctx, current)
+		if errors.Is(err, ErrVersionConflict) && attempt < creditCheckApplyAttempts {
+			slog.DebugContext(ctx, "Booking changed during credit check, applying result again", "error", err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("update booking %d after credit check: %w", id, err)
+		}
+		break
+	}
 
-		for _, booking := range bookings {
-			// If booking is pending for more than 5 minutes, mark as canceled
-			if booking.Status == models.BookingStatusPending && now.Sub(booking.CreatedAt) > 5*time.Minute {
-				booking.Status = models.BookingStatusCanceled
-				booking.UpdatedAt = now
+	// Drop the cached copy rather than replacing it, so a slower writer cannot cache an older version
+	uc.cache.Delete(id)
+	if updatedBooking.Status != models.BookingStatusPending {
+		uc.metrics.BookingStatus(updatedBooking.Status)
+	}
 
-				// Update in repository
-				updatedBooking, err := uc.repo.Update(ctx, booking)
-				if err != nil {
-					log.Printf("Error updating expired booking %d: %v", booking.ID, err)
-					continue
-				}
+	slog.InfoContext(ctx, "Credit check completed",
+		"outcome", check.Outcome,
+		"reason", check.Reason,
+		"status", updatedBooking.Status,
+		"attempts", check.Attempts)
 
-				// Update in cache
-				cacheKey := fmt.Sprintf("booking:%d", booking.ID)
-				uc.cache.Set(cacheKey, updatedBooking)
+	return nil
+}
 
-				expiredCount++
-			}
+// recordCreditCheck applies a credit check result to booking and reports
+// whether it did. Results for bookings that are no longer pending are discarded.
+func recordCreditCheck(ctx context.Context, booking *models.Booking, check *models.CreditCheck) bool {
+	switch check.Outcome {
+	case models.CreditCheckApproved, models.CreditCheckRejected:
+		status := models.BookingStatusConfirmed
+		if check.Outcome == models.CreditCheckRejected {
+			status = models.BookingStatusRejected
 		}
-
-		if expiredCount > 0 {
-			log.Printf("Auto-canceled %d expired bookings", expiredCount)
+		if err := booking.TransitionTo(status, models.ActorCreditCheck, check.CheckedAt); err != nil {
+			slog.WarnContext(ctx, "Discarding credit check result", "error", err)
+			return false
+		}
+	default:
+		// No decision: keep the booking pending so the expiry job can clean it up
+		if booking.Status != models.BookingStatusPending {
+			slog.WarnContext(ctx, "Discarding failed credit check for booking that is no longer pending", "status", booking.Status)
+			return false
 		}
+		booking.UpdatedAt = check.CheckedAt
 	}
+	booking.CreditCheck = check
+	return true
 }
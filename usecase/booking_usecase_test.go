@@ -4,21 +4,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
 	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// newJobQueue returns a job queue mock that accepts the use case's job handlers
+func newJobQueue() *mocks.JobQueue {
+	jobQueue := new(mocks.JobQueue)
+	jobQueue.On("Register", mock.Anything, mock.Anything).Return()
+	return jobQueue
+}
+
 func TestCreateBooking(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
 	now := time.Now()
@@ -55,13 +67,13 @@ func TestCreateBooking(t *testing.T) {
 			b.Status == models.BookingStatusPending
 	})).Return(createdBooking, nil)
 
-	mockCache.On("Set", "booking:1", createdBooking).Return()
+	mockCache.On("Set", int64(1), createdBooking).Return()
 
 	// Create use case
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.CreateBooking(context.Background(), req)
+	result, err := uc.CreateBooking(internalCtx, req)
 
 	// Assert
 	assert.NoError(t, err)
@@ -74,10 +86,50 @@ func TestCreateBooking(t *testing.T) {
 	mockCache.AssertExpectations(t)
 }
 
+func TestCreateBooking_HighValueQueuesCreditCheck(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.BookingRepository)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
+	mockJobs := newJobQueue()
+
+	// Create test data
+	req := &dto.CreateBookingRequest{
+		UserID:    123,
+		ServiceID: 456,
+		Price:     60000.0,
+	}
+	createdBooking := &models.Booking{
+		ID:        7,
+		UserID:    req.UserID,
+		ServiceID: req.ServiceID,
+		Price:     req.Price,
+		Status:    models.BookingStatusPending,
+	}
+
+	// Setup expectations
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(createdBooking, nil)
+	mockCache.On("Set", int64(7), createdBooking).Return()
+	mockJobs.On("Enqueue", mock.Anything, usecase.JobTypeCreditCheck, mock.MatchedBy(func(payload interface{}) bool {
+		return fmt.Sprintf("%+v", payload) == "{BookingID:7}"
+	})).Return(&jobs.Job{ID: "job-1", Type: usecase.JobTypeCreditCheck}, nil)
+
+	// Create use case
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), mockJobs)
+
+	// Execute
+	result, err := uc.CreateBooking(internalCtx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), result.ID)
+	mockRepo.AssertExpectations(t)
+	mockJobs.AssertExpectations(t)
+}
+
 func TestGetBookingByID_FromCache(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
 	bookingID := int64(1)
@@ -92,13 +144,13 @@ func TestGetBookingByID_FromCache(t *testing.T) {
 	}
 
 	// Setup expectations - booking found in cache
-	mockCache.On("Get", "booking:1").Return(booking, true)
+	mockCache.On("Get", int64(1)).Return(booking, true)
 
 	// Create use case
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.GetBookingByID(context.Background(), bookingID)
+	result, err := uc.GetBookingByID(internalCtx, bookingID)
 
 	// Assert
 	assert.NoError(t, err)
@@ -112,7 +164,7 @@ func TestGetBookingByID_FromCache(t *testing.T) {
 func TestGetBookingByID_FromRepository(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
 	bookingID := int64(1)
@@ -127,15 +179,15 @@ func TestGetBookingByID_FromRepository(t *testing.T) {
 	}
 
 	// Setup expectations - booking not found in cache, but found in repository
-	mockCache.On("Get", "booking:1").Return(nil, false)
+	mockCache.On("Get", int64(1)).Return(nil, false)
 	mockRepo.On("GetByID", mock.Anything, bookingID).Return(booking, nil)
-	mockCache.On("Set", "booking:1", booking).Return()
+	mockCache.On("Set", int64(1), booking).Return()
 
 	// Create use case
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.GetBookingByID(context.Background(), bookingID)
+	result, err := uc.GetBookingByID(internalCtx, bookingID)
 
 	// Assert
 	assert.NoError(t, err)
@@ -145,68 +197,227 @@ func TestGetBookingByID_FromRepository(t *testing.T) {
 	mockCache.AssertExpectations(t)
 }
 
+func TestGetBookingByID_ConcurrentMissesShareOneRead(t *testing.T) {
+	// Setup - the repository read is slow enough for every caller to miss the cache
+	mockRepo := new(mocks.BookingRepository)
+	booking := &models.Booking{ID: 1, UserID: 101, Status: models.BookingStatusPending}
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(booking, nil).After(50 * time.Millisecond).Once()
+	uc := usecase.NewBookingUseCase(mockRepo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+
+	// Execute
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]*models.Booking, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = uc.GetBookingByID(internalCtx, 1)
+		}(i)
+	}
+	wg.Wait()
+
+	// Assert
+	for i := range results {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, booking, results[i])
+	}
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestGetBookingByID_RemembersMissingBooking(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockRepo.On("GetByID", mock.Anything, int64(999)).Return(nil, repository.ErrBookingNotFound)
+	uc := usecase.NewBookingUseCase(mockRepo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+
+	// Execute
+	_, first := uc.GetBookingByID(internalCtx, 999)
+	_, second := uc.GetBookingByID(internalCtx, 999)
+
+	// Assert - the second lookup does not reach the repository
+	assert.ErrorIs(t, first, repository.ErrBookingNotFound)
+	assert.ErrorIs(t, second, repository.ErrBookingNotFound)
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestGetBookingByID_NotFoundTTLZeroAlwaysReads(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockRepo.On("GetByID", mock.Anything, int64(999)).Return(nil, repository.ErrBookingNotFound)
+	uc := usecase.NewBookingUseCase(mockRepo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue(),
+		usecase.WithNotFoundTTL(0))
+
+	// Execute
+	uc.GetBookingByID(internalCtx, 999)
+	uc.GetBookingByID(internalCtx, 999)
+
+	// Assert
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 2)
+}
+
 func TestGetAllBookings(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
+	minPrice := 20000.0
 	params := &dto.BookingsQueryParams{
 		Sort:      "price",
+		Order:     "desc",
+		Status:    "pending",
+		UserID:    123,
+		MinPrice:  &minPrice,
 		HighValue: false,
+		Limit:     2,
 	}
 
 	bookings := []*models.Booking{
 		{
-			ID:        1,
+			ID:        2,
 			UserID:    123,
-			ServiceID: 456,
-			Price:     30000.0,
+			ServiceID: 567,
+			Price:     45000.0,
 			Status:    models.BookingStatusPending,
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
 		{
-			ID:        2,
-			UserID:    234,
-			ServiceID: 567,
-			Price:     45000.0,
-			Status:    models.BookingStatusConfirmed,
+			ID:        1,
+			UserID:    123,
+			ServiceID: 456,
+			Price:     30000.0,
+			Status:    models.BookingStatusPending,
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
 	}
 
-	cacheMap := map[string]interface{}{
-		"booking:1": bookings[0],
-		"booking:2": bookings[1],
-	}
-
-	// Setup expectations
-	mockRepo.On("GetAll", mock.Anything).Return(bookings, nil)
-	mockCache.On("GetAll").Return(cacheMap)
+	// Setup expectations - filtering and ordering are pushed down to the repository
+	mockRepo.On("Query", mock.Anything, mock.MatchedBy(func(q repository.BookingQuery) bool {
+		return q.SortBy == repository.SortByPrice && q.Descending &&
+			q.Status == models.BookingStatusPending && q.UserID == 123 &&
+			*q.MinPrice == minPrice && q.Limit == 2 && q.After == nil
+	})).Return(&repository.BookingPage{
+		Bookings: bookings,
+		Next:     &repository.BookingCursor{ID: 1, Price: 30000.0},
+	}, nil)
 
 	// Create use case
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.GetAllBookings(context.Background(), params)
+	result, err := uc.GetAllBookings(internalCtx, params)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, 2, len(result))
-	// Since we specified sort by price, the first item should be the one with lower price
-	assert.Equal(t, float64(30000.0), result[0].Price)
-	assert.Equal(t, float64(45000.0), result[1].Price)
+	assert.Equal(t, 2, len(result.Data))
+	assert.Equal(t, float64(45000.0), result.Data[0].Price)
+	assert.Equal(t, float64(30000.0), result.Data[1].Price)
+	assert.NotEmpty(t, result.NextCursor)
 
 	mockRepo.AssertExpectations(t)
-	mockCache.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "GetAll")
+}
+
+func TestGetAllBookings_DefaultAndMaximumPageSize(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.BookingRepository)
+	mockRepo.On("Query", mock.Anything, mock.Anything).Return(&repository.BookingPage{}, nil)
+	uc := usecase.NewBookingUseCase(mockRepo, new(mocks.Cache[int64, *models.Booking]), new(mocks.CreditChecker), newJobQueue())
+
+	// Execute
+	_, err := uc.GetAllBookings(internalCtx, &dto.BookingsQueryParams{})
+	assert.NoError(t, err)
+	_, err = uc.GetAllBookings(internalCtx, &dto.BookingsQueryParams{Limit: 1000})
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, usecase.DefaultPageSize, mockRepo.Calls[0].Arguments.Get(1).(repository.BookingQuery).Limit)
+	assert.Equal(t, usecase.MaxPageSize, mockRepo.Calls[1].Arguments.Get(1).(repository.BookingQuery).Limit)
+}
+
+func TestGetAllBookings_PagesThroughAllBookings(t *testing.T) {
+	// Arrange - the seeded repository holds bookings priced 10,000 to 100,000
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+	params := &dto.BookingsQueryParams{Sort: "price", Order: "desc", Limit: 3}
+
+	// Act
+	var prices []float64
+	pages := 0
+	for {
+		page, err := uc.GetAllBookings(internalCtx, params)
+		require.NoError(t, err)
+		pages++
+		for _, booking := range page.Data {
+			prices = append(prices, booking.Price)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+
+	// Assert
+	assert.Equal(t, 4, pages)
+	assert.Equal(t, []float64{100000, 90000, 80000, 70000, 60000, 50000, 40000, 30000, 20000, 10000}, prices)
+}
+
+func TestGetAllBookings_HighValueIsStrictlyAboveThreshold(t *testing.T) {
+	// Arrange
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+
+	// Act
+	page, err := uc.GetAllBookings(internalCtx, &dto.BookingsQueryParams{HighValue: true})
+
+	// Assert - the 50,000 booking is not high-value
+	require.NoError(t, err)
+	assert.Equal(t, 5, len(page.Data))
+	for _, booking := range page.Data {
+		assert.Greater(t, booking.Price, 50000.0)
+	}
+}
+
+func TestGetAllBookings_ConfiguredHighValueThreshold(t *testing.T) {
+	// Arrange
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue(),
+		usecase.WithHighValueThreshold(80000))
+
+	// Act
+	page, err := uc.GetAllBookings(internalCtx, &dto.BookingsQueryParams{HighValue: true})
+
+	// Assert - only the 90,000 and 100,000 bookings
+	require.NoError(t, err)
+	require.Len(t, page.Data, 2)
+	assert.Equal(t, 90000.0, page.Data[0].Price)
+	assert.Equal(t, 100000.0, page.Data[1].Price)
+}
+
+func TestGetAllBookings_CursorFromAnotherSortOrder(t *testing.T) {
+	// Arrange - take a cursor issued for price order
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+	page, err := uc.GetAllBookings(internalCtx, &dto.BookingsQueryParams{Sort: "price", Limit: 2})
+	require.NoError(t, err)
+
+	// Act
+	_, dateErr := uc.GetAllBookings(internalCtx, &dto.BookingsQueryParams{Sort: "date", Limit: 2, Cursor: page.NextCursor})
+	_, garbageErr := uc.GetAllBookings(internalCtx, &dto.BookingsQueryParams{Cursor: "not a cursor"})
+
+	// Assert
+	assert.ErrorIs(t, dateErr, usecase.ErrInvalidCursor)
+	assert.ErrorIs(t, garbageErr, usecase.ErrInvalidCursor)
 }
 
 func TestCancelBooking_Success(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
 	bookingID := int64(1)
@@ -231,10 +442,9 @@ func TestCancelBooking_Success(t *testing.T) {
 	}
 
 	// Setup expectations
-	cacheKey := fmt.Sprintf("booking:%d", bookingID)
 
 	// Expect GetBookingByID to be called and return the booking
-	mockCache.On("Get", cacheKey).Return(booking, true)
+	mockCache.On("Get", bookingID).Return(booking, true)
 
 	// Expect repository Update to be called with a booking object that has:
 	// - Same ID as the original booking
@@ -244,13 +454,13 @@ func TestCancelBooking_Success(t *testing.T) {
 	})).Return(canceledBooking, nil)
 
 	// Expect cache Delete to be called with the correct key
-	mockCache.On("Delete", cacheKey).Return()
+	mockCache.On("Delete", bookingID).Return()
 
 	// Create use case instance
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.CancelBooking(context.Background(), bookingID)
+	result, err := uc.CancelBooking(internalCtx, bookingID, 0)
 
 	// Assert
 	assert.NoError(t, err)
@@ -265,7 +475,7 @@ func TestCancelBooking_Success(t *testing.T) {
 func TestCancelBooking_CannotCancelConfirmed(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
 	bookingID := int64(1)
@@ -280,19 +490,19 @@ func TestCancelBooking_CannotCancelConfirmed(t *testing.T) {
 	}
 
 	// Setup expectations
-	cacheKey := fmt.Sprintf("booking:%d", bookingID)
-	mockCache.On("Get", cacheKey).Return(booking, true)
+	mockCache.On("Get", bookingID).Return(booking, true)
 
 	// Create use case instance
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.CancelBooking(context.Background(), bookingID)
+	result, err := uc.CancelBooking(internalCtx, bookingID, 0)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Equal(t, "cannot cancel a confirmed booking", err.Error())
+	// Only admins may force-cancel a confirmed booking
+	assert.Equal(t, "customer cannot cancel a confirmed booking", err.Error())
 
 	// Verify expectations - The repository update and cache delete should not be called
 	mockRepo.AssertNotCalled(t, "Update")
@@ -303,22 +513,21 @@ func TestCancelBooking_CannotCancelConfirmed(t *testing.T) {
 func TestCancelBooking_BookingNotFound(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
 	bookingID := int64(999) // Non-existent ID
-	cacheKey := fmt.Sprintf("booking:%d", bookingID)
 	notFoundError := errors.New("booking not found")
 
 	// Setup expectations
-	mockCache.On("Get", cacheKey).Return(nil, false)
+	mockCache.On("Get", bookingID).Return(nil, false)
 	mockRepo.On("GetByID", mock.Anything, bookingID).Return(nil, notFoundError)
 
 	// Create use case instance
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.CancelBooking(context.Background(), bookingID)
+	result, err := uc.CancelBooking(internalCtx, bookingID, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -335,7 +544,7 @@ func TestCancelBooking_BookingNotFound(t *testing.T) {
 func TestCancelBooking_RepositoryError(t *testing.T) {
 	// Create mocks
 	mockRepo := new(mocks.BookingRepository)
-	mockCache := new(mocks.Cache)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
 
 	// Create test data
 	bookingID := int64(1)
@@ -352,18 +561,17 @@ func TestCancelBooking_RepositoryError(t *testing.T) {
 	updateError := errors.New("database connection error")
 
 	// Setup expectations
-	cacheKey := fmt.Sprintf("booking:%d", bookingID)
-	mockCache.On("Get", cacheKey).Return(booking, true)
+	mockCache.On("Get", bookingID).Return(booking, true)
 
 	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *models.Booking) bool {
 		return b.ID == bookingID && b.Status == models.BookingStatusCanceled
 	})).Return(nil, updateError)
 
 	// Create use case instance
-	uc := usecase.NewBookingUseCase(mockRepo, mockCache)
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
 
 	// Execute
-	result, err := uc.CancelBooking(context.Background(), bookingID)
+	result, err := uc.CancelBooking(internalCtx, bookingID, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -375,3 +583,144 @@ func TestCancelBooking_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
 }
+
+func TestCancelBooking_ExpectedVersionMismatch(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.BookingRepository)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
+
+	// Create test data - the client read version 1, the booking is now at version 2
+	bookingID := int64(1)
+	booking := &models.Booking{
+		ID:        bookingID,
+		UserID:    123,
+		ServiceID: 456,
+		Price:     30000.0,
+		Status:    models.BookingStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Version:   2,
+	}
+
+	// Setup expectations - the cached copy is re-checked against the repository once
+	mockCache.On("Get", bookingID).Return(booking, true)
+	mockCache.On("Delete", bookingID).Return()
+	mockRepo.On("GetByID", mock.Anything, bookingID).Return(booking, nil)
+
+	// Create use case instance
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
+
+	// Execute
+	result, err := uc.CancelBooking(internalCtx, bookingID, 1)
+
+	// Assert
+	assert.ErrorIs(t, err, usecase.ErrVersionConflict)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_RetriesOnceAfterStaleCache(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.BookingRepository)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
+
+	// Create test data - the cache holds version 1 but the repository has version 2
+	bookingID := int64(1)
+	cachedBooking := &models.Booking{
+		ID:      bookingID,
+		Price:   30000.0,
+		Status:  models.BookingStatusPending,
+		Version: 1,
+	}
+	currentBooking := &models.Booking{
+		ID:      bookingID,
+		Price:   30000.0,
+		Status:  models.BookingStatusPending,
+		Version: 2,
+	}
+	canceledBooking := &models.Booking{
+		ID:      bookingID,
+		Price:   30000.0,
+		Status:  models.BookingStatusCanceled,
+		Version: 3,
+	}
+	conflict := &repository.VersionConflictError{ID: bookingID, ExpectedVersion: 1, CurrentVersion: 2}
+
+	// Setup expectations
+	mockCache.On("Get", bookingID).Return(cachedBooking, true)
+	mockCache.On("Delete", bookingID).Return()
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *models.Booking) bool {
+		return b.Version == 1
+	})).Return(nil, conflict).Once()
+	mockRepo.On("GetByID", mock.Anything, bookingID).Return(currentBooking, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *models.Booking) bool {
+		return b.Version == 2 && b.Status == models.BookingStatusCanceled
+	})).Return(canceledBooking, nil).Once()
+
+	// Create use case instance
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
+
+	// Execute
+	result, err := uc.CancelBooking(internalCtx, bookingID, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.BookingStatusCanceled, result.Status)
+	assert.Equal(t, int64(3), result.Version)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_CannotCancelRejected(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.BookingRepository)
+	mockCache := new(mocks.Cache[int64, *models.Booking])
+
+	// Create test data
+	bookingID := int64(1)
+	booking := &models.Booking{
+		ID:      bookingID,
+		Status:  models.BookingStatusRejected,
+		Version: 2,
+	}
+
+	// Setup expectations
+	mockCache.On("Get", bookingID).Return(booking, true)
+
+	// Create use case instance
+	uc := usecase.NewBookingUseCase(mockRepo, mockCache, new(mocks.CreditChecker), newJobQueue())
+
+	// Execute
+	result, err := uc.CancelBooking(internalCtx, bookingID, 0)
+
+	// Assert
+	assert.ErrorIs(t, err, models.ErrInvalidTransition)
+	assert.Nil(t, result)
+	assert.Equal(t, models.BookingStatusRejected, booking.Status)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestExpireBookingsJob_CancelsStalePendingBookings(t *testing.T) {
+	// Arrange - one stale and one fresh pending booking
+	repo := repository.NewBookingRepositoryMock()
+	queue := startJobQueue(t)
+	usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), queue)
+
+	ctx := context.Background()
+	stale, err := repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 1, Price: 1000, CreatedAt: time.Now().Add(-10 * time.Minute)})
+	require.NoError(t, err)
+	fresh, err := repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 1, Price: 1000, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	// Act
+	_, err = queue.Enqueue(ctx, usecase.JobTypeExpireBookings, struct{}{})
+	require.NoError(t, err)
+
+	// Assert
+	waitForBooking(t, repo, stale.ID, func(b *models.Booking) bool {
+		return b.Status == models.BookingStatusCanceled
+	})
+	current, err := repo.GetByID(ctx, fresh.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusPending, current.Status)
+}
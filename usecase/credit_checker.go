@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CreditChecker decides whether a high-value booking passes the credit check
+type CreditChecker interface {
+	// Name identifies the checker in the credit check recorded on the booking
+	Name() string
+	CheckCredit(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error)
+}
+
+// CreditCheckPolicy controls how the use case calls its CreditChecker
+type CreditCheckPolicy struct {
+	// Timeout bounds a single attempt
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts before the check is recorded as failed
+	MaxAttempts int
+	// RetryBackoff is the pause before the first retry; it doubles on each further retry
+	RetryBackoff time.Duration
+}
+
+// DefaultCreditCheckPolicy returns the policy used when none is configured
+func DefaultCreditCheckPolicy() CreditCheckPolicy {
+	return CreditCheckPolicy{
+		Timeout:      5 * time.Second,
+		MaxAttempts:  3,
+		RetryBackoff: 500 * time.Millisecond,
+	}
+}
+
+// Option configures optional behaviour of BookingUseCaseImpl
+type Option func(*BookingUseCaseImpl)
+
+// WithCreditCheckPolicy sets the timeout and retry policy for credit checks
+func WithCreditCheckPolicy(policy CreditCheckPolicy) Option {
+	return func(uc *BookingUseCaseImpl) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		uc.creditPolicy = policy
+	}
+}
+
+// runCreditCheck calls the credit checker according to the policy and
+// returns the record to store on the booking. Errors are recorded as a
+// failed outcome rather than returned.
+func (uc *BookingUseCaseImpl) runCreditCheck(ctx context.Context, booking *models.Booking) *models.CreditCheck {
+	started := time.Now()
+	check := &models.CreditCheck{
+		Checker: uc.creditChecker.Name(),
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "CreditCheck", trace.WithAttributes(
+		attribute.Int64("booking.id", booking.ID),
+		attribute.String("credit_check.checker", check.Checker),
+	))
+	defer span.End()
+
+	var lastErr error
+	backoff := uc.creditPolicy.RetryBackoff
+
+	for attempt := 1; attempt <= uc.creditPolicy.MaxAttempts; attempt++ {
+		check.Attempts = attempt
+
+		decision, err := uc.checkCreditOnce(ctx, booking)
+		if err == nil {
+			check.Outcome = models.CreditCheckRejected
+			if decision.Approved {
+				check.Outcome = models.CreditCheckApproved
+			}
+			check.Reason = decision.Reason
+			lastErr = nil
+			break
+		}
+
+		lastErr = err
+		if errors.Is(err, context.DeadlineExceeded) {
+			check.TimedOut = true
+		}
+
+		// Stop retrying once the caller has given up
+		if ctx.Err() != nil || attempt == uc.creditPolicy.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+		}
+		backoff *= 2
+	}
+
+	if lastErr != nil {
+		check.Outcome = models.CreditCheckFailed
+		check.Reason = lastErr.Error()
+	}
+
+	check.CheckedAt = time.Now()
+	check.DurationMs = check.CheckedAt.Sub(started).Milliseconds()
+	uc.metrics.CreditCheckCompleted(check.Outcome, check.CheckedAt.Sub(started))
+
+	span.SetAttributes(
+		attribute.String("credit_check.outcome", string(check.Outcome)),
+		attribute.Int("credit_check.attempts", check.Attempts),
+	)
+	if check.Outcome == models.CreditCheckFailed {
+		span.SetStatus(codes.Error, check.Reason)
+	}
+
+	return check
+}
+
+// checkCreditOnce makes a single attempt bounded by the policy timeout
+func (uc *BookingUseCaseImpl) checkCreditOnce(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error) {
+	if uc.creditPolicy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.creditPolicy.Timeout)
+		defer cancel()
+	}
+
+	decision, err := uc.creditChecker.CheckCredit(ctx, booking)
+	if err != nil {
+		return nil, err
+	}
+	if decision == nil {
+		return nil, errors.New("credit checker returned no decision")
+	}
+
+	return decision, nil
+}
@@ -0,0 +1,295 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var highValueRequest = &dto.CreateBookingRequest{
+	UserID:    123,
+	ServiceID: 456,
+	Price:     60000.0,
+}
+
+// startJobQueue runs an in-memory job queue for the duration of the test.
+// Failed jobs are dead-lettered after a single attempt.
+func startJobQueue(t *testing.T) *jobs.Queue {
+	t.Helper()
+
+	queue := jobs.NewQueue(jobs.NewMemoryStore(), jobs.Options{
+		Concurrency:  1,
+		MaxAttempts:  1,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, queue.Start(context.Background()))
+	t.Cleanup(queue.Stop)
+
+	return queue
+}
+
+// waitForBooking polls the repository until the booking satisfies cond
+func waitForBooking(t *testing.T, repo repository.BookingRepository, id int64, cond func(*models.Booking) bool) *models.Booking {
+	t.Helper()
+
+	var booking *models.Booking
+	assert.Eventually(t, func() bool {
+		var err error
+		booking, err = repo.GetByID(context.Background(), id)
+		return err == nil && cond(booking)
+	}, 2*time.Second, 5*time.Millisecond)
+
+	return booking
+}
+
+func TestCreditCheck_ApprovedConfirmsBooking(t *testing.T) {
+	// Arrange
+	repo := repository.NewBookingRepositoryMock()
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(&models.CreditDecision{Approved: true, Reason: "within limit"}, nil)
+
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, startJobQueue(t))
+
+	// Act
+	created, err := uc.CreateBooking(internalCtx, highValueRequest)
+	require.NoError(t, err)
+
+	// Assert
+	booking := waitForBooking(t, repo, created.ID, func(b *models.Booking) bool {
+		return b.CreditCheck != nil
+	})
+	assert.Equal(t, models.BookingStatusConfirmed, booking.Status)
+	assert.Equal(t, models.CreditCheckApproved, booking.CreditCheck.Outcome)
+	assert.Equal(t, "within limit", booking.CreditCheck.Reason)
+	assert.Equal(t, "stub", booking.CreditCheck.Checker)
+	assert.Equal(t, 1, booking.CreditCheck.Attempts)
+}
+
+func TestCreditCheck_RejectedRejectsBooking(t *testing.T) {
+	// Arrange
+	repo := repository.NewBookingRepositoryMock()
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(&models.CreditDecision{Approved: false, Reason: "over limit"}, nil)
+
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, startJobQueue(t))
+
+	// Act
+	created, err := uc.CreateBooking(internalCtx, highValueRequest)
+	require.NoError(t, err)
+
+	// Assert
+	booking := waitForBooking(t, repo, created.ID, func(b *models.Booking) bool {
+		return b.CreditCheck != nil
+	})
+	assert.Equal(t, models.BookingStatusRejected, booking.Status)
+	assert.Equal(t, "over limit", booking.CreditCheck.Reason)
+}
+
+func TestCreditCheck_RetriesThenRecordsTimeout(t *testing.T) {
+	// Arrange - the checker never answers before the timeout
+	repo := repository.NewBookingRepositoryMock()
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	// The queue would allow retries; the check's own attempts must be the only ones
+	queue := jobs.NewQueue(jobs.NewMemoryStore(), jobs.Options{
+		Concurrency:  1,
+		MaxAttempts:  5,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, queue.Start(context.Background()))
+	t.Cleanup(queue.Stop)
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, queue,
+		usecase.WithCreditCheckPolicy(usecase.CreditCheckPolicy{
+			Timeout:      10 * time.Millisecond,
+			MaxAttempts:  3,
+			RetryBackoff: time.Millisecond,
+		}))
+
+	// Act
+	created, err := uc.CreateBooking(internalCtx, highValueRequest)
+	require.NoError(t, err)
+
+	// Assert - the booking stays pending with the failure recorded
+	booking := waitForBooking(t, repo, created.ID, func(b *models.Booking) bool {
+		return b.CreditCheck != nil
+	})
+	assert.Equal(t, models.BookingStatusPending, booking.Status)
+	assert.Equal(t, models.CreditCheckFailed, booking.CreditCheck.Outcome)
+	assert.True(t, booking.CreditCheck.TimedOut)
+	assert.Equal(t, 3, booking.CreditCheck.Attempts)
+	checker.AssertNumberOfCalls(t, "CheckCredit", 3)
+
+	// The job is dead-lettered after its first run instead of calling the checker again
+	var failed []*jobs.Job
+	assert.Eventually(t, func() bool {
+		failed, _ = queue.Failed(context.Background())
+		return len(failed) == 1
+	}, time.Second, 5*time.Millisecond)
+	if len(failed) == 1 {
+		assert.Equal(t, usecase.JobTypeCreditCheck, failed[0].Type)
+		assert.Equal(t, 1, failed[0].Attempts)
+		assert.Contains(t, failed[0].LastError, "context deadline exceeded")
+	}
+	checker.AssertNumberOfCalls(t, "CheckCredit", 3)
+}
+
+func TestCreditCheck_RecoversAfterTransientError(t *testing.T) {
+	// Arrange - the first attempt fails, the second succeeds
+	repo := repository.NewBookingRepositoryMock()
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection refused")).Once()
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(&models.CreditDecision{Approved: true, Reason: "ok"}, nil).Once()
+
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, startJobQueue(t),
+		usecase.WithCreditCheckPolicy(usecase.CreditCheckPolicy{
+			Timeout:      time.Second,
+			MaxAttempts:  3,
+			RetryBackoff: time.Millisecond,
+		}))
+
+	// Act
+	created, err := uc.CreateBooking(internalCtx, highValueRequest)
+	require.NoError(t, err)
+
+	// Assert
+	booking := waitForBooking(t, repo, created.ID, func(b *models.Booking) bool {
+		return b.CreditCheck != nil
+	})
+	assert.Equal(t, models.BookingStatusConfirmed, booking.Status)
+	assert.Equal(t, 2, booking.CreditCheck.Attempts)
+	assert.False(t, booking.CreditCheck.TimedOut)
+}
+
+func TestCreditCheck_ResultDiscardedAfterCancel(t *testing.T) {
+	// Arrange - hold the credit check until the booking has been canceled
+	repo := repository.NewBookingRepositoryMock()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	answered := make(chan struct{})
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, booking *models.Booking) (*models.CreditDecision, error) {
+			close(started)
+			<-release
+			defer close(answered)
+			return &models.CreditDecision{Approved: true, Reason: "late approval"}, nil
+		})
+
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, startJobQueue(t))
+
+	created, err := uc.CreateBooking(internalCtx, highValueRequest)
+	require.NoError(t, err)
+	<-started
+
+	// Act
+	_, err = uc.CancelBooking(internalCtx, created.ID, 0)
+	require.NoError(t, err)
+	close(release)
+
+	// Assert - give the check time to apply its result; the cancel must win
+	<-answered
+	time.Sleep(50 * time.Millisecond)
+
+	booking, err := repo.GetByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusCanceled, booking.Status)
+	assert.Nil(t, booking.CreditCheck)
+}
+
+// touchingRepo updates a booking behind the caller's back before its first
+// conflicts updates, leaving the booking pending but at a newer version
+type touchingRepo struct {
+	repository.BookingRepository
+	conflicts atomic.Int32
+}
+
+func (r *touchingRepo) Update(ctx context.Context, booking *models.Booking) (*models.Booking, error) {
+	if r.conflicts.Add(-1) >= 0 {
+		current, err := r.BookingRepository.GetByID(ctx, booking.ID)
+		if err != nil {
+			return nil, err
+		}
+		current.UpdatedAt = time.Now()
+		if _, err := r.BookingRepository.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return r.BookingRepository.Update(ctx, booking)
+}
+
+func TestCreditCheck_ReappliedAfterConcurrentUpdate(t *testing.T) {
+	// Arrange
+	repo := &touchingRepo{BookingRepository: repository.NewBookingRepositoryMock()}
+	repo.conflicts.Store(2)
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(&models.CreditDecision{Approved: true, Reason: "within limit"}, nil)
+
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, startJobQueue(t))
+
+	// Act
+	created, err := uc.CreateBooking(internalCtx, highValueRequest)
+	require.NoError(t, err)
+
+	// Assert - the result lands on the newer version without running the check again
+	booking := waitForBooking(t, repo, created.ID, func(b *models.Booking) bool {
+		return b.CreditCheck != nil
+	})
+	assert.Equal(t, models.BookingStatusConfirmed, booking.Status)
+	checker.AssertNumberOfCalls(t, "CheckCredit", 1)
+}
+
+func TestCreditCheck_QueuedJobSurvivesRestart(t *testing.T) {
+	// Arrange - the booking is created while no worker is running
+	repo := repository.NewBookingRepositoryMock()
+	store := jobs.NewMemoryStore()
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(&models.CreditDecision{Approved: true, Reason: "within limit"}, nil)
+
+	stopped := jobs.NewQueue(store, jobs.Options{})
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, stopped)
+	created, err := uc.CreateBooking(internalCtx, highValueRequest)
+	require.NoError(t, err)
+
+	// Act - a new process picks up the persisted job
+	restarted := jobs.NewQueue(store, jobs.Options{PollInterval: 10 * time.Millisecond})
+	usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, restarted)
+	require.NoError(t, restarted.Start(context.Background()))
+	defer restarted.Stop()
+
+	// Assert
+	booking := waitForBooking(t, repo, created.ID, func(b *models.Booking) bool {
+		return b.CreditCheck != nil
+	})
+	assert.Equal(t, models.BookingStatusConfirmed, booking.Status)
+}
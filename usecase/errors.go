@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+)
+
+// Error kinds returned by the use cases. More specific errors, such as
+// ErrVersionConflict or a *models.TransitionError, match their kind with
+// errors.Is, so callers can map each kind to a response once.
+var (
+	// ErrNotFound matches errors for bookings and keys that do not exist
+	ErrNotFound = repository.ErrNotFound
+	// ErrConflict matches errors for changes based on stale data
+	ErrConflict = repository.ErrConflict
+	// ErrInvalidTransition matches booking status changes the state machine does not allow
+	ErrInvalidTransition = models.ErrInvalidTransition
+	// ErrValidation matches errors for invalid input
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable matches errors for a dependency that cannot be reached right now
+	ErrUnavailable = repository.ErrUnavailable
+)
+
+// ValidationError reports an invalid input field. It matches ErrValidation with errors.Is.
+type ValidationError struct {
+	Field string
+	// Reason completes a sentence starting with the field name, e.g. "must be positive"
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Field + " " + e.Reason
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/jobs"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+)
+
+// ExpiryPolicy controls how long bookings may stay pending
+type ExpiryPolicy struct {
+	// DefaultTTL applies to services without an entry in ServiceTTLs
+	DefaultTTL time.Duration
+	// ServiceTTLs overrides the TTL per service ID
+	ServiceTTLs map[int64]time.Duration
+}
+
+// DefaultExpiryPolicy returns the policy used when none is configured
+func DefaultExpiryPolicy() ExpiryPolicy {
+	return ExpiryPolicy{
+		DefaultTTL: 5 * time.Minute,
+	}
+}
+
+// TTL returns how long a booking for the given service may stay pending
+func (p ExpiryPolicy) TTL(serviceID int64) time.Duration {
+	if ttl, ok := p.ServiceTTLs[serviceID]; ok {
+		return ttl
+	}
+	return p.DefaultTTL
+}
+
+// WithExpiryPolicy sets the TTLs used to expire pending bookings
+func WithExpiryPolicy(policy ExpiryPolicy) Option {
+	return func(uc *BookingUseCaseImpl) {
+		uc.expiryPolicy = policy
+	}
+}
+
+// expiresAt returns when a booking expires. Bookings stored before expiry
+// times were recorded fall back to their creation time plus the service TTL.
+func (uc *BookingUseCaseImpl) expiresAt(booking *models.Booking) time.Time {
+	if !booking.ExpiresAt.IsZero() {
+		return booking.ExpiresAt
+	}
+	return booking.CreatedAt.Add(uc.expiryPolicy.TTL(booking.ServiceID))
+}
+
+// expiryBatchSize is the number of pending bookings read per query while expiring
+const expiryBatchSize = 100
+
+// ExpireBookings cancels pending bookings whose expiry time has passed
+func (uc *BookingUseCaseImpl) ExpireBookings(ctx context.Context) (int, error) {
+	query := repository.BookingQuery{
+		Status: models.BookingStatusPending,
+		Limit:  expiryBatchSize,
+	}
+
+	now := time.Now()
+	expiredCount := 0
+
+	for {
+		page, err := uc.repo.Query(ctx, query)
+		if err != nil {
+			return expiredCount, fmt.Errorf("fetch pending bookings: %w", err)
+		}
+
+		for _, booking := range page.Bookings {
+			if uc.expireBooking(ctx, booking, now) {
+				expiredCount++
+			}
+		}
+
+		if page.Next == nil {
+			break
+		}
+		query.After = page.Next
+	}
+
+	uc.metrics.ExpiryRun(expiredCount)
+	uc.lastExpiryRun.Store(time.Now().UnixNano())
+	if expiredCount > 0 {
+		slog.InfoContext(ctx, "Auto-canceled expired bookings", "count", expiredCount)
+	}
+
+	return expiredCount, nil
+}
+
+// LastExpiryRun returns when ExpireBookings last completed without error
+func (uc *BookingUseCaseImpl) LastExpiryRun() time.Time {
+	nanos := uc.lastExpiryRun.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// expireBooking cancels a pending booking if its expiry time has passed and reports whether it did
+func (uc *BookingUseCaseImpl) expireBooking(ctx context.Context, booking *models.Booking, now time.Time) bool {
+	if now.Before(uc.expiresAt(booking)) {
+		return false
+	}
+
+	if err := booking.TransitionTo(models.BookingStatusCanceled, models.ActorExpiry, now); err != nil {
+		slog.WarnContext(ctx, "Skipping expired booking", "booking_id", booking.ID, "error", err)
+		return false
+	}
+
+	// Update in repository; a conflicting update is picked up by the next run
+	updatedBooking, err := uc.repo.Update(ctx, booking)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error updating expired booking", "booking_id", booking.ID, "error", err)
+		return false
+	}
+
+	// Drop the cached copy rather than replacing it, so a slower writer cannot cache an older version
+	uc.cache.Delete(booking.ID)
+	uc.metrics.BookingStatus(updatedBooking.Status)
+
+	return true
+}
+
+// QueueExpiryCheck queues an expiry run on the job queue
+func (uc *BookingUseCaseImpl) QueueExpiryCheck(ctx context.Context) error {
+	_, err := uc.jobQueue.Enqueue(ctx, JobTypeExpireBookings, struct{}{})
+	return err
+}
+
+// runExpireBookingsJob runs ExpireBookings for a queued expiry job
+func (uc *BookingUseCaseImpl) runExpireBookingsJob(ctx context.Context, job *jobs.Job) error {
+	slog.DebugContext(ctx, "Running expired bookings check")
+	_, err := uc.ExpireBookings(ctx)
+	return err
+}
@@ -0,0 +1,146 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBooking_ExpiresAtFromServiceTTL(t *testing.T) {
+	// Arrange - service 9 has a short TTL, everything else the default
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue(),
+		usecase.WithExpiryPolicy(usecase.ExpiryPolicy{
+			DefaultTTL:  time.Hour,
+			ServiceTTLs: map[int64]time.Duration{9: 2 * time.Minute},
+		}))
+
+	// Act
+	short, err := uc.CreateBooking(internalCtx, &dto.CreateBookingRequest{UserID: 1, ServiceID: 9, Price: 1000})
+	require.NoError(t, err)
+	long, err := uc.CreateBooking(internalCtx, &dto.CreateBookingRequest{UserID: 1, ServiceID: 1, Price: 1000})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 2*time.Minute, short.ExpiresAt.Sub(short.CreatedAt))
+	assert.Equal(t, time.Hour, long.ExpiresAt.Sub(long.CreatedAt))
+}
+
+func TestCreateBooking_ExpiresAtFromRequest(t *testing.T) {
+	// Arrange
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+	expiresAt := time.Now().Add(48 * time.Hour)
+
+	// Act
+	booking, err := uc.CreateBooking(internalCtx, &dto.CreateBookingRequest{
+		UserID:    1,
+		ServiceID: 1,
+		Price:     1000,
+		ExpiresAt: &expiresAt,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, expiresAt.Equal(booking.ExpiresAt))
+}
+
+func TestExpireBookings(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue(),
+		usecase.WithExpiryPolicy(usecase.ExpiryPolicy{
+			DefaultTTL:  10 * time.Minute,
+			ServiceTTLs: map[int64]time.Duration{9: time.Minute},
+		}))
+
+	now := time.Now()
+	create := func(serviceID int64, createdAt, expiresAt time.Time) *models.Booking {
+		booking, err := repo.Create(ctx, &models.Booking{
+			UserID: 1, ServiceID: serviceID, Price: 1000,
+			CreatedAt: createdAt, UpdatedAt: createdAt, ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+		return booking
+	}
+
+	// Old booking whose own expiry time is still ahead
+	extended := create(1, now.Add(-time.Hour), now.Add(time.Hour))
+	// New booking whose expiry time has already passed
+	overdue := create(1, now, now.Add(-time.Second))
+	// Bookings without an expiry time fall back to the service TTL
+	legacyShortTTL := create(9, now.Add(-2*time.Minute), time.Time{})
+	legacyDefaultTTL := create(1, now.Add(-2*time.Minute), time.Time{})
+
+	// Act
+	_, err := uc.ExpireBookings(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	expectations := map[*models.Booking]models.BookingStatus{
+		extended:         models.BookingStatusPending,
+		overdue:          models.BookingStatusCanceled,
+		legacyShortTTL:   models.BookingStatusCanceled,
+		legacyDefaultTTL: models.BookingStatusPending,
+	}
+	for booking, status := range expectations {
+		current, err := repo.GetByID(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, status, current.Status, "booking %d", booking.ID)
+	}
+}
+
+func TestExpireBookings_SkipsFinishedBookings(t *testing.T) {
+	// Arrange - a confirmed booking long past its expiry time
+	ctx := context.Background()
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+
+	booking, err := repo.Create(ctx, &models.Booking{UserID: 1, ServiceID: 1, Price: 1000, ExpiresAt: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, booking.TransitionTo(models.BookingStatusConfirmed, models.ActorCreditCheck, time.Now()))
+	_, err = repo.Update(ctx, booking)
+	require.NoError(t, err)
+
+	// Act
+	_, err = uc.ExpireBookings(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	current, err := repo.GetByID(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusConfirmed, current.Status)
+}
+
+func TestExpireBookings_RecordsLastCompletedRun(t *testing.T) {
+	// Arrange - the first run cannot read the pending bookings
+	repo := new(mocks.BookingRepository)
+	repo.On("Query", mock.Anything, mock.Anything).Return(nil, errors.New("database unavailable")).Once()
+	repo.On("Query", mock.Anything, mock.Anything).Return(&repository.BookingPage{}, nil)
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue())
+	ctx := context.Background()
+
+	// Act
+	_, failedErr := uc.ExpireBookings(ctx)
+	afterFailure := uc.LastExpiryRun()
+	before := time.Now()
+	_, err := uc.ExpireBookings(ctx)
+
+	// Assert
+	assert.Error(t, failedErr)
+	assert.True(t, afterFailure.IsZero())
+	require.NoError(t, err)
+	assert.False(t, uc.LastExpiryRun().Before(before))
+}
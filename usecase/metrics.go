@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+)
+
+// Metrics records the booking lifecycle
+type Metrics interface {
+	// BookingStatus is called when a booking is created or changes status
+	BookingStatus(status models.BookingStatus)
+	CreditCheckCompleted(outcome models.CreditCheckOutcome, duration time.Duration)
+	// ExpiryRun is called after each expiry run with the number of canceled bookings
+	ExpiryRun(canceled int)
+}
+
+// noopMetrics discards everything; it is used when no Metrics are configured
+type noopMetrics struct{}
+
+func (noopMetrics) BookingStatus(models.BookingStatus)                            {}
+func (noopMetrics) CreditCheckCompleted(models.CreditCheckOutcome, time.Duration) {}
+func (noopMetrics) ExpiryRun(int)                                                 {}
+
+// WithMetrics sets where booking lifecycle metrics are recorded
+func WithMetrics(metrics Metrics) Option {
+	return func(uc *BookingUseCaseImpl) {
+		uc.metrics = metrics
+	}
+}
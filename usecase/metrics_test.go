@@ -0,0 +1,71 @@
+package usecase_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics keeps what the use case reports
+type recordingMetrics struct {
+	mu       sync.Mutex
+	statuses []models.BookingStatus
+	expired  []int
+}
+
+func (m *recordingMetrics) BookingStatus(status models.BookingStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, status)
+}
+
+func (m *recordingMetrics) CreditCheckCompleted(outcome models.CreditCheckOutcome, duration time.Duration) {
+}
+
+func (m *recordingMetrics) ExpiryRun(canceled int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expired = append(m.expired, canceled)
+}
+
+func TestMetrics_RecordBookingLifecycle(t *testing.T) {
+	// Arrange
+	recorder := &recordingMetrics{}
+	repo := repository.NewBookingRepositoryMock()
+	uc := usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), new(mocks.CreditChecker), newJobQueue(),
+		usecase.WithMetrics(recorder))
+	ctx := internalCtx
+
+	// Act - create and cancel one booking, then expire another
+	created, err := uc.CreateBooking(ctx, &dto.CreateBookingRequest{UserID: 1, ServiceID: 1, Price: 1000})
+	require.NoError(t, err)
+	_, err = uc.CancelBooking(ctx, created.ID, 0)
+	require.NoError(t, err)
+
+	past := time.Now().Add(-time.Minute)
+	_, err = uc.CreateBooking(ctx, &dto.CreateBookingRequest{UserID: 1, ServiceID: 1, Price: 1000, ExpiresAt: &past})
+	require.NoError(t, err)
+	_, err = uc.ExpireBookings(ctx)
+	require.NoError(t, err)
+
+	// Assert - the run also expires the seeded pending bookings
+	require.Len(t, recorder.expired, 1)
+	require.Len(t, recorder.statuses, 3+recorder.expired[0])
+	assert.Equal(t, []models.BookingStatus{
+		models.BookingStatusPending,
+		models.BookingStatusCanceled,
+		models.BookingStatusPending,
+	}, recorder.statuses[:3])
+	for _, status := range recorder.statuses[3:] {
+		assert.Equal(t, models.BookingStatusCanceled, status)
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/dto"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedBookingUseCase runs every call of the use case it wraps in a span
+type tracedBookingUseCase struct {
+	uc BookingUseCase
+}
+
+// NewTracedBookingUseCase wraps uc with tracing
+func NewTracedBookingUseCase(uc BookingUseCase) BookingUseCase {
+	return &tracedBookingUseCase{uc: uc}
+}
+
+// startUseCaseSpan starts a span named after the use case method
+func startUseCaseSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "BookingUseCase."+method, trace.WithAttributes(attrs...))
+}
+
+func (t *tracedBookingUseCase) CreateBooking(ctx context.Context, req *dto.CreateBookingRequest) (booking *models.Booking, err error) {
+	ctx, span := startUseCaseSpan(ctx, "CreateBooking",
+		attribute.Int64("booking.user_id", req.UserID),
+		attribute.Int64("booking.service_id", req.ServiceID))
+	defer func() { tracing.End(span, err) }()
+
+	booking, err = t.uc.CreateBooking(ctx, req)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("booking.id", booking.ID))
+	}
+	return booking, err
+}
+
+func (t *tracedBookingUseCase) GetBookingByID(ctx context.Context, id int64) (booking *models.Booking, err error) {
+	ctx, span := startUseCaseSpan(ctx, "GetBookingByID", attribute.Int64("booking.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return t.uc.GetBookingByID(ctx, id)
+}
+
+func (t *tracedBookingUseCase) GetAllBookings(ctx context.Context, params *dto.BookingsQueryParams) (page *dto.BookingListResponse, err error) {
+	ctx, span := startUseCaseSpan(ctx, "GetAllBookings")
+	defer func() { tracing.End(span, err) }()
+
+	return t.uc.GetAllBookings(ctx, params)
+}
+
+func (t *tracedBookingUseCase) CancelBooking(ctx context.Context, id int64, expectedVersion int64) (booking *models.Booking, err error) {
+	ctx, span := startUseCaseSpan(ctx, "CancelBooking", attribute.Int64("booking.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return t.uc.CancelBooking(ctx, id, expectedVersion)
+}
+
+func (t *tracedBookingUseCase) ExpireBookings(ctx context.Context) (expired int, err error) {
+	ctx, span := startUseCaseSpan(ctx, "ExpireBookings")
+	defer func() { tracing.End(span, err) }()
+
+	expired, err = t.uc.ExpireBookings(ctx)
+	span.SetAttributes(attribute.Int("bookings.expired", expired))
+	return expired, err
+}
+
+func (t *tracedBookingUseCase) QueueExpiryCheck(ctx context.Context) (err error) {
+	ctx, span := startUseCaseSpan(ctx, "QueueExpiryCheck")
+	defer func() { tracing.End(span, err) }()
+
+	return t.uc.QueueExpiryCheck(ctx)
+}
+
+// LastExpiryRun only reads a timestamp and is not traced
+func (t *tracedBookingUseCase) LastExpiryRun() time.Time {
+	return t.uc.LastExpiryRun()
+}
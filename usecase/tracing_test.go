@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/mocks"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/models"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/repository"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/tracing"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/usecase"
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spansByName waits until spans with all the given names have ended and returns them by name
+func spansByName(t *testing.T, recorder *tracetest.SpanRecorder, names ...string) map[string]sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	require.Eventually(t, func() bool {
+		for _, span := range recorder.Ended() {
+			byName[span.Name()] = span
+		}
+		for _, name := range names {
+			if _, ok := byName[name]; !ok {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 5*time.Millisecond)
+
+	return byName
+}
+
+func TestTracing_CreditCheckIsLinkedToCreatingRequest(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	tracing.Install(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	checker := new(mocks.CreditChecker)
+	checker.On("Name").Return("stub")
+	checker.On("CheckCredit", mock.Anything, mock.Anything).
+		Return(&models.CreditDecision{Approved: true}, nil)
+	repo := repository.NewTracedBookingRepository(repository.NewBookingRepositoryMock())
+	uc := usecase.NewTracedBookingUseCase(
+		usecase.NewBookingUseCase(repo, utils.NewInMemoryCache[int64, *models.Booking](), checker, startJobQueue(t)))
+
+	ctx, request := tracing.Tracer().Start(internalCtx, "POST /api/bookings")
+
+	// Act
+	_, err := uc.CreateBooking(ctx, highValueRequest)
+	request.End()
+	require.NoError(t, err)
+
+	// Assert - the request's spans nest
+	spans := spansByName(t, recorder,
+		"BookingUseCase.CreateBooking", "BookingRepository.Create", "job credit_check", "CreditCheck")
+	create := spans["BookingUseCase.CreateBooking"]
+	assert.Equal(t, request.SpanContext().SpanID(), create.Parent().SpanID())
+	assert.Equal(t, create.SpanContext().SpanID(), spans["BookingRepository.Create"].Parent().SpanID())
+
+	// Assert - the credit check runs in its own trace, linked to the request's
+	job := spans["job credit_check"]
+	assert.NotEqual(t, request.SpanContext().TraceID(), job.SpanContext().TraceID())
+	require.Len(t, job.Links(), 1)
+	assert.Equal(t, create.SpanContext().TraceID(), job.Links()[0].SpanContext.TraceID())
+	assert.Equal(t, create.SpanContext().SpanID(), job.Links()[0].SpanContext.SpanID())
+	assert.Equal(t, job.SpanContext().SpanID(), spans["CreditCheck"].Parent().SpanID())
+}
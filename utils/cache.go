@@ -1,48 +1,248 @@
 package utils
 
 import (
+	"container/list"
 	"sync"
+	"time"
 )
 
-// Cache defines the interface for cache operations
-type Cache interface {
-	Set(key string, value interface{})
-	Get(key string) (interface{}, bool)
-	Delete(key string)
-	GetAll() map[string]interface{}
+// Cache defines the interface for cache operations on values of type V stored under keys of type K
+type Cache[K comparable, V any] interface {
+	Set(key K, value V)
+	Get(key K) (V, bool)
+	Delete(key K)
+	// GetAll returns a snapshot of the live entries; changing it does not change the cache
+	GetAll() map[K]V
+	Stats() CacheStats
 }
 
-// InMemoryCache implements Cache interface with in-memory storage
-type InMemoryCache struct {
-	data map[string]interface{}
-	mu   sync.RWMutex
+// CacheStats counts what happened to a cache since it was created
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	// Evictions are entries dropped to make room for new ones
+	Evictions uint64 `json:"evictions"`
+	// Expirations are entries dropped because their TTL passed
+	Expirations uint64 `json:"expirations"`
+	Entries     int    `json:"entries"`
 }
 
-func (c *InMemoryCache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	val, exists := c.data[key]
-	return val, exists
+// CacheOption configures an InMemoryCache
+type CacheOption func(*cacheOptions)
+
+// cacheOptions are the settings shared by caches of every type
+type cacheOptions struct {
+	ttl             time.Duration
+	maxEntries      int
+	cleanupInterval time.Duration
+}
+
+// WithCacheTTL sets how long entries stored with Set live; zero keeps them until evicted
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *cacheOptions) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxEntries bounds the number of entries; when full, the least recently
+// used entry is evicted. Zero means unbounded.
+func WithMaxEntries(max int) CacheOption {
+	return func(c *cacheOptions) {
+		c.maxEntries = max
+	}
+}
+
+// WithCleanupInterval starts a janitor that drops expired entries every
+// interval until Close. Without it expired entries are only dropped when
+// they are looked up or evicted.
+func WithCleanupInterval(interval time.Duration) CacheOption {
+	return func(c *cacheOptions) {
+		c.cleanupInterval = interval
+	}
+}
+
+// cacheEntry is an element of the recency list
+type cacheEntry[K comparable, V any] struct {
+	key   K
+	value V
+	// expiresAt is zero for entries that do not expire
+	expiresAt time.Time
+}
+
+func (e *cacheEntry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// InMemoryCache implements Cache interface with in-memory storage.
+// Entries expire after their TTL and, when the cache is bounded, the least
+// recently used entry makes room for a new one.
+type InMemoryCache[K comparable, V any] struct {
+	data map[K]*list.Element
+	// recency orders the entries from most to least recently used
+	recency *list.List
+	stats   CacheStats
+	mu      sync.Mutex
+
+	cacheOptions
+	stop      chan struct{}
+	closeOnce sync.Once
 }
 
-func (c *InMemoryCache) Set(key string, value interface{}) {
+// Get returns the value stored under key unless it has expired
+func (c *InMemoryCache[K, V]) Get(key K) (V, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.data[key] = value
+
+	var zero V
+	element, exists := c.data[key]
+	if !exists {
+		c.stats.Misses++
+		return zero, false
+	}
+	entry := element.Value.(*cacheEntry[K, V])
+	if entry.expired(time.Now()) {
+		c.remove(element)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return zero, false
+	}
+
+	c.recency.MoveToFront(element)
+	c.stats.Hits++
+	return entry.value, true
 }
-func (c *InMemoryCache) Delete(key string) {
+
+// Set stores value under key with the cache's TTL
+func (c *InMemoryCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL stores value under key for ttl; zero keeps it until evicted
+func (c *InMemoryCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.data, key)
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if element, exists := c.data[key]; exists {
+		entry := element.Value.(*cacheEntry[K, V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.recency.MoveToFront(element)
+		return
+	}
+
+	c.data[key] = c.recency.PushFront(&cacheEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.maxEntries > 0 && c.recency.Len() > c.maxEntries {
+		c.evict()
+	}
+}
+
+// evict drops the least recently used entry
+func (c *InMemoryCache[K, V]) evict() {
+	oldest := c.recency.Back()
+	if oldest.Value.(*cacheEntry[K, V]).expired(time.Now()) {
+		c.stats.Expirations++
+	} else {
+		c.stats.Evictions++
+	}
+	c.remove(oldest)
 }
 
-func (c *InMemoryCache) GetAll() map[string]interface{} {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.data
+func (c *InMemoryCache[K, V]) remove(element *list.Element) {
+	c.recency.Remove(element)
+	delete(c.data, element.Value.(*cacheEntry[K, V]).key)
 }
-func NewInMemoryCache() *InMemoryCache {
-	return &InMemoryCache{
-		data: make(map[string]interface{}),
+
+// Delete removes key from the cache
+func (c *InMemoryCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, exists := c.data[key]; exists {
+		c.remove(element)
+	}
+}
+
+// GetAll returns a copy of the entries that have not expired
+func (c *InMemoryCache[K, V]) GetAll() map[K]V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	snapshot := make(map[K]V, len(c.data))
+	for key, element := range c.data {
+		entry := element.Value.(*cacheEntry[K, V])
+		if !entry.expired(now) {
+			snapshot[key] = entry.value
+		}
+	}
+	return snapshot
+}
+
+// Stats returns the cache's counters and current size. Entries may include
+// expired entries the janitor has not dropped yet.
+func (c *InMemoryCache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.data)
+	return stats
+}
+
+// DeleteExpired drops every expired entry. The janitor calls it periodically.
+func (c *InMemoryCache[K, V]) DeleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, element := range c.data {
+		if element.Value.(*cacheEntry[K, V]).expired(now) {
+			c.remove(element)
+			c.stats.Expirations++
+		}
+	}
+}
+
+// janitor drops expired entries every cleanupInterval until Close
+func (c *InMemoryCache[K, V]) janitor() {
+	ticker := time.NewTicker(c.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.DeleteExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Close stops the janitor. The cache remains usable; closing twice is a no-op.
+func (c *InMemoryCache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// NewInMemoryCache creates a cache. Without options it is unbounded and
+// entries never expire.
+func NewInMemoryCache[K comparable, V any](opts ...CacheOption) *InMemoryCache[K, V] {
+	c := &InMemoryCache[K, V]{
+		data:    make(map[K]*list.Element),
+		recency: list.New(),
+		stop:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&c.cacheOptions)
+	}
+
+	if c.cleanupInterval > 0 {
+		go c.janitor()
 	}
+	return c
 }
@@ -1,8 +1,10 @@
 package utils_test
 
 import (
+	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
 	"github.com/stretchr/testify/assert"
@@ -10,7 +12,7 @@ import (
 
 func TestNewInMemoryCache(t *testing.T) {
 	// Act
-	cache := utils.NewInMemoryCache()
+	cache := utils.NewInMemoryCache[string, any]()
 
 	// Assert
 	assert.NotNil(t, cache, "Expected cache instance to be non-nil")
@@ -18,7 +20,7 @@ func TestNewInMemoryCache(t *testing.T) {
 
 func TestSetAndGet(t *testing.T) {
 	// Arrange
-	cache := utils.NewInMemoryCache()
+	cache := utils.NewInMemoryCache[string, any]()
 	key := "test-key"
 	value := "test-value"
 
@@ -33,7 +35,7 @@ func TestSetAndGet(t *testing.T) {
 
 func TestGetNonExistentKey(t *testing.T) {
 	// Arrange
-	cache := utils.NewInMemoryCache()
+	cache := utils.NewInMemoryCache[string, any]()
 	key := "non-existent-key"
 
 	// Act
@@ -46,7 +48,7 @@ func TestGetNonExistentKey(t *testing.T) {
 
 func TestDelete(t *testing.T) {
 	// Arrange
-	cache := utils.NewInMemoryCache()
+	cache := utils.NewInMemoryCache[string, any]()
 	key := "test-key"
 	value := "test-value"
 	cache.Set(key, value)
@@ -66,7 +68,7 @@ func TestDelete(t *testing.T) {
 
 func TestGetAll(t *testing.T) {
 	// Arrange
-	cache := utils.NewInMemoryCache()
+	cache := utils.NewInMemoryCache[string, any]()
 	cache.Set("key1", "value1")
 	cache.Set("key2", "value2")
 	cache.Set("key3", "value3")
@@ -83,7 +85,7 @@ func TestGetAll(t *testing.T) {
 
 func TestConcurrentAccess(t *testing.T) {
 	// Arrange
-	cache := utils.NewInMemoryCache()
+	cache := utils.NewInMemoryCache[string, any]()
 	const goroutines = 10
 	const iterations = 100
 	var wg sync.WaitGroup
@@ -122,7 +124,7 @@ func TestConcurrentAccess(t *testing.T) {
 
 func TestSetOverwritesExistingValue(t *testing.T) {
 	// Arrange
-	cache := utils.NewInMemoryCache()
+	cache := utils.NewInMemoryCache[string, any]()
 	key := "test-key"
 	initialValue := "initial-value"
 	updatedValue := "updated-value"
@@ -138,10 +140,6 @@ func TestSetOverwritesExistingValue(t *testing.T) {
 }
 
 func TestCachePersistsComplexTypes(t *testing.T) {
-	// Arrange
-	cache := utils.NewInMemoryCache()
-	key := "object-key"
-
 	// Complex struct to store
 	type TestStruct struct {
 		ID   int
@@ -149,6 +147,10 @@ func TestCachePersistsComplexTypes(t *testing.T) {
 		Tags []string
 	}
 
+	// Arrange
+	cache := utils.NewInMemoryCache[string, TestStruct]()
+	key := "object-key"
+
 	value := TestStruct{
 		ID:   123,
 		Name: "Test Object",
@@ -157,16 +159,149 @@ func TestCachePersistsComplexTypes(t *testing.T) {
 
 	// Act
 	cache.Set(key, value)
-	retrievedValue, exists := cache.Get(key)
+	retrievedStruct, exists := cache.Get(key)
 
-	// Assert
+	// Assert - the value comes back typed, without a type assertion
 	assert.True(t, exists, "Expected key to exist in cache")
 
-	// Type assertion to convert interface{} back to TestStruct
-	retrievedStruct, ok := retrievedValue.(TestStruct)
-	assert.True(t, ok, "Expected successful type assertion")
-
 	assert.Equal(t, value.ID, retrievedStruct.ID)
 	assert.Equal(t, value.Name, retrievedStruct.Name)
 	assert.Equal(t, value.Tags, retrievedStruct.Tags)
 }
+
+func TestGetAllReturnsSnapshot(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any]()
+	cache.Set("key1", "value1")
+
+	// Act
+	allData := cache.GetAll()
+	allData["key2"] = "value2"
+	delete(allData, "key1")
+
+	// Assert
+	_, exists := cache.Get("key1")
+	assert.True(t, exists, "Changing the snapshot must not change the cache")
+	_, exists = cache.Get("key2")
+	assert.False(t, exists, "Changing the snapshot must not change the cache")
+}
+
+func TestEntriesExpireAfterTTL(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any](utils.WithCacheTTL(20 * time.Millisecond))
+	cache.Set("short", "value")
+	cache.SetWithTTL("long", "value", time.Hour)
+	cache.SetWithTTL("forever", "value", 0)
+
+	// Act
+	time.Sleep(40 * time.Millisecond)
+
+	// Assert
+	_, exists := cache.Get("short")
+	assert.False(t, exists, "Expected entry to expire after the cache TTL")
+	_, exists = cache.Get("long")
+	assert.True(t, exists, "Expected entry with its own TTL to outlive the cache TTL")
+	_, exists = cache.Get("forever")
+	assert.True(t, exists, "Expected entry without TTL to never expire")
+	assert.Equal(t, 2, len(cache.GetAll()))
+	assert.Equal(t, uint64(1), cache.Stats().Expirations)
+}
+
+func TestSetRefreshesTTL(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any](utils.WithCacheTTL(50 * time.Millisecond))
+	cache.Set("key", "initial-value")
+	time.Sleep(30 * time.Millisecond)
+
+	// Act
+	cache.Set("key", "updated-value")
+	time.Sleep(30 * time.Millisecond)
+
+	// Assert
+	value, exists := cache.Get("key")
+	assert.True(t, exists, "Expected overwriting to restart the TTL")
+	assert.Equal(t, "updated-value", value)
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any](utils.WithMaxEntries(2))
+	cache.Set("key1", "value1")
+	cache.Set("key2", "value2")
+	cache.Get("key1") // key2 is now the least recently used
+
+	// Act
+	cache.Set("key3", "value3")
+
+	// Assert
+	_, exists := cache.Get("key2")
+	assert.False(t, exists, "Expected least recently used entry to be evicted")
+	_, exists = cache.Get("key1")
+	assert.True(t, exists)
+	_, exists = cache.Get("key3")
+	assert.True(t, exists)
+	assert.Equal(t, 2, cache.Stats().Entries)
+	assert.Equal(t, uint64(1), cache.Stats().Evictions)
+}
+
+func TestOverwriteDoesNotEvict(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any](utils.WithMaxEntries(2))
+	cache.Set("key1", "value1")
+	cache.Set("key2", "value2")
+
+	// Act
+	cache.Set("key1", "updated-value")
+
+	// Assert
+	assert.Equal(t, 2, len(cache.GetAll()))
+	assert.Equal(t, uint64(0), cache.Stats().Evictions)
+}
+
+func TestJanitorRemovesExpiredEntries(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any](
+		utils.WithCacheTTL(10*time.Millisecond),
+		utils.WithCleanupInterval(10*time.Millisecond))
+	defer cache.Close()
+	for i := 0; i < 5; i++ {
+		cache.Set(fmt.Sprintf("key%d", i), i)
+	}
+
+	// Act & Assert - entries are dropped without being looked up
+	assert.Eventually(t, func() bool {
+		return cache.Stats().Entries == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, uint64(5), cache.Stats().Expirations)
+	assert.Equal(t, uint64(0), cache.Stats().Misses)
+}
+
+func TestCloseIsIdempotent(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any](utils.WithCleanupInterval(time.Millisecond))
+
+	// Act
+	cache.Close()
+	cache.Close()
+	cache.Set("key", "value")
+
+	// Assert - the cache stays usable after Close
+	_, exists := cache.Get("key")
+	assert.True(t, exists)
+}
+
+func TestStats(t *testing.T) {
+	// Arrange
+	cache := utils.NewInMemoryCache[string, any]()
+	cache.Set("key1", "value1")
+	cache.Set("key2", "value2")
+
+	// Act
+	cache.Get("key1")
+	cache.Get("key1")
+	cache.Get("missing")
+	stats := cache.Stats()
+
+	// Assert
+	assert.Equal(t, utils.CacheStats{Hits: 2, Misses: 1, Entries: 2}, stats)
+}
@@ -0,0 +1,51 @@
+package utils
+
+// Cloner is implemented by values that can make a deep copy of themselves
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// CloningCache stores and returns copies of mutable values, so callers
+// that change a value they got from the cache, or stored in it, cannot
+// change what other callers see
+type CloningCache[K comparable, V Cloner[V]] struct {
+	cache Cache[K, V]
+}
+
+// NewCloningCache wraps cache so values are copied on the way in and out
+func NewCloningCache[K comparable, V Cloner[V]](cache Cache[K, V]) *CloningCache[K, V] {
+	return &CloningCache[K, V]{cache: cache}
+}
+
+// Get returns a copy of the value stored under key
+func (c *CloningCache[K, V]) Get(key K) (V, bool) {
+	value, found := c.cache.Get(key)
+	if !found {
+		return value, false
+	}
+	return value.Clone(), true
+}
+
+// Set stores a copy of value under key
+func (c *CloningCache[K, V]) Set(key K, value V) {
+	c.cache.Set(key, value.Clone())
+}
+
+// Delete removes key from the cache
+func (c *CloningCache[K, V]) Delete(key K) {
+	c.cache.Delete(key)
+}
+
+// GetAll returns copies of the live entries
+func (c *CloningCache[K, V]) GetAll() map[K]V {
+	all := c.cache.GetAll()
+	for key, value := range all {
+		all[key] = value.Clone()
+	}
+	return all
+}
+
+// Stats returns the statistics of the wrapped cache
+func (c *CloningCache[K, V]) Stats() CacheStats {
+	return c.cache.Stats()
+}
@@ -0,0 +1,49 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// counter is a mutable value that can copy itself
+type counter struct {
+	n int
+}
+
+func (c *counter) Clone() *counter {
+	clone := *c
+	return &clone
+}
+
+func TestCloningCache_CopiesOnSetAndGet(t *testing.T) {
+	// Arrange
+	cache := utils.NewCloningCache[string, *counter](utils.NewInMemoryCache[string, *counter]())
+	stored := &counter{n: 1}
+	cache.Set("key", stored)
+
+	// Act - change both the value that was stored and one that was read
+	stored.n = 2
+	read, _ := cache.Get("key")
+	read.n = 3
+
+	// Assert
+	value, found := cache.Get("key")
+	assert.True(t, found)
+	assert.Equal(t, 1, value.n)
+	assert.Equal(t, 1, cache.GetAll()["key"].n)
+}
+
+func TestCloningCache_GetAllReturnsCopies(t *testing.T) {
+	// Arrange
+	cache := utils.NewCloningCache[string, *counter](utils.NewInMemoryCache[string, *counter]())
+	cache.Set("key", &counter{n: 1})
+
+	// Act
+	cache.GetAll()["key"].n = 2
+
+	// Assert
+	value, _ := cache.Get("key")
+	assert.Equal(t, 1, value.n)
+}
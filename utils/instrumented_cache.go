@@ -0,0 +1,32 @@
+package utils
+
+// CacheObserver is told the result of every cache lookup
+type CacheObserver interface {
+	CacheHit()
+	CacheMiss()
+}
+
+// InstrumentedCache reports the hits and misses of the cache it wraps
+type InstrumentedCache[K comparable, V any] struct {
+	Cache[K, V]
+	observer CacheObserver
+}
+
+// NewInstrumentedCache wraps cache so every Get is reported to observer
+func NewInstrumentedCache[K comparable, V any](cache Cache[K, V], observer CacheObserver) *InstrumentedCache[K, V] {
+	return &InstrumentedCache[K, V]{
+		Cache:    cache,
+		observer: observer,
+	}
+}
+
+// Get looks up key in the wrapped cache and reports the result
+func (c *InstrumentedCache[K, V]) Get(key K) (V, bool) {
+	value, found := c.Cache.Get(key)
+	if found {
+		c.observer.CacheHit()
+	} else {
+		c.observer.CacheMiss()
+	}
+	return value, found
+}
@@ -0,0 +1,33 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingObserver struct {
+	hits, misses int
+}
+
+func (o *countingObserver) CacheHit()  { o.hits++ }
+func (o *countingObserver) CacheMiss() { o.misses++ }
+
+func TestInstrumentedCache_ReportsHitsAndMisses(t *testing.T) {
+	// Arrange
+	observer := &countingObserver{}
+	cache := utils.NewInstrumentedCache(utils.NewInMemoryCache[string, any](), observer)
+	cache.Set("booking:1", "value")
+
+	// Act
+	value, found := cache.Get("booking:1")
+	_, missing := cache.Get("booking:2")
+
+	// Assert
+	assert.True(t, found)
+	assert.Equal(t, "value", value)
+	assert.False(t, missing)
+	assert.Equal(t, 1, observer.hits)
+	assert.Equal(t, 1, observer.misses)
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// maxMissingEntries bounds how many missing keys a LoadingCache remembers
+const maxMissingEntries = 10000
+
+// errLoadPanicked is returned to callers that waited for a loader that panicked
+var errLoadPanicked = errors.New("cache loader panicked")
+
+// LoadingOption configures a LoadingCache
+type LoadingOption func(*loadingOptions)
+
+type loadingOptions struct {
+	missingTTL time.Duration
+	isMissing  func(error) bool
+}
+
+// WithNegativeCaching remembers for ttl the keys whose load failed with an
+// error isMissing accepts, e.g. not found errors, and answers them with that
+// error without loading again
+func WithNegativeCaching(ttl time.Duration, isMissing func(error) bool) LoadingOption {
+	return func(o *loadingOptions) {
+		o.missingTTL = ttl
+		o.isMissing = isMissing
+	}
+}
+
+// load is a call of a loader that other callers of the same key wait for
+type load[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	// invalidated is set when the key is written during the load, so the
+	// loaded value may already be stale and must not be cached
+	invalidated bool
+}
+
+// LoadingCache is a read-through cache over another cache. Concurrent
+// GetOrLoad calls for the same missing key share a single load.
+type LoadingCache[K comparable, V any] struct {
+	Cache[K, V]
+	loadingOptions
+	// missing remembers the keys that failed to load with a missing error
+	missing *InMemoryCache[K, error]
+	loads   map[K]*load[V]
+	mu      sync.Mutex
+}
+
+// NewLoadingCache wraps cache with GetOrLoad. Writes must go through the
+// LoadingCache so they invalidate loads in flight and missing keys.
+func NewLoadingCache[K comparable, V any](cache Cache[K, V], opts ...LoadingOption) *LoadingCache[K, V] {
+	c := &LoadingCache[K, V]{
+		Cache: cache,
+		loads: make(map[K]*load[V]),
+	}
+	for _, opt := range opts {
+		opt(&c.loadingOptions)
+	}
+	c.missing = NewInMemoryCache[K, error](WithCacheTTL(c.missingTTL), WithMaxEntries(maxMissingEntries))
+	return c
+}
+
+// GetOrLoad returns the value cached under key, or calls loader, caches its
+// result and returns it. While a load is in flight, other callers for the
+// same key wait for it and get its result, including its error; values that
+// implement Cloner are copied for every caller.
+func (c *LoadingCache[K, V]) GetOrLoad(key K, loader func() (V, error)) (V, error) {
+	if value, found := c.Cache.Get(key); found {
+		return value, nil
+	}
+	if err, found := c.missing.Get(key); found {
+		var zero V
+		return zero, err
+	}
+
+	c.mu.Lock()
+	if running, ok := c.loads[key]; ok {
+		c.mu.Unlock()
+		<-running.done
+		return own(running.value), running.err
+	}
+	l := &load[V]{done: make(chan struct{})}
+	c.loads[key] = l
+	c.mu.Unlock()
+
+	c.run(key, l, loader)
+	return own(l.value), l.err
+}
+
+// run calls loader for key and stores its result unless the load was invalidated.
+// The cache is not checked again first: that would count a second miss for
+// every cold lookup, and a load that finished just before this one started
+// only costs one extra read.
+func (c *LoadingCache[K, V]) run(key K, l *load[V], loader func() (V, error)) {
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		switch {
+		case l.invalidated:
+		case l.err == nil:
+			c.Cache.Set(key, l.value)
+		case c.missingTTL > 0 && c.isMissing != nil && c.isMissing(l.err):
+			c.missing.Set(key, l.err)
+		}
+		delete(c.loads, key)
+		close(l.done)
+	}()
+
+	// Waiters see errLoadPanicked if loader does not return
+	l.err = errLoadPanicked
+	l.value, l.err = loader()
+}
+
+// own copies a loaded value for one of the callers sharing the load, when V
+// can be copied, so none of them holds the value the others copy from
+func own[V any](value V) V {
+	if cloner, ok := any(value).(Cloner[V]); ok {
+		return cloner.Clone()
+	}
+	return value
+}
+
+// Set stores value under key; a load of key in flight is not cached
+func (c *LoadingCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(key)
+	c.Cache.Set(key, value)
+}
+
+// Delete removes key; a load of key in flight is not cached
+func (c *LoadingCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(key)
+	c.Cache.Delete(key)
+}
+
+// invalidate forgets that key is missing and keeps a running load of key from being cached
+func (c *LoadingCache[K, V]) invalidate(key K) {
+	c.missing.Delete(key)
+	if running, ok := c.loads[key]; ok {
+		running.invalidated = true
+	}
+}
@@ -0,0 +1,201 @@
+package utils_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errMissing = errors.New("missing")
+
+func isMissing(err error) bool { return errors.Is(err, errMissing) }
+
+func TestGetOrLoad_LoadsOnceAndCaches(t *testing.T) {
+	// Arrange
+	cache := utils.NewLoadingCache[string, int](utils.NewInMemoryCache[string, int]())
+	var loads int
+
+	// Act
+	first, err := cache.GetOrLoad("key", func() (int, error) { loads++; return 42, nil })
+	require.NoError(t, err)
+	second, err := cache.GetOrLoad("key", func() (int, error) { loads++; return 0, nil })
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 42, first)
+	assert.Equal(t, 42, second)
+	assert.Equal(t, 1, loads)
+}
+
+func TestGetOrLoad_CountsOneMissPerColdLookup(t *testing.T) {
+	// Arrange
+	store := utils.NewInMemoryCache[string, int]()
+	cache := utils.NewLoadingCache[string, int](store)
+
+	// Act
+	_, err := cache.GetOrLoad("key", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	_, err = cache.GetOrLoad("key", func() (int, error) { return 0, nil })
+	require.NoError(t, err)
+
+	// Assert
+	stats := store.Stats()
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Hits)
+}
+
+func TestGetOrLoad_SharesConcurrentLoads(t *testing.T) {
+	// Arrange
+	cache := utils.NewLoadingCache[string, int](utils.NewInMemoryCache[string, int]())
+	release := make(chan struct{})
+	var loads atomic.Int32
+	loader := func() (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	// Act - every caller misses while the first load is blocked
+	const callers = 20
+	results := make([]int, callers)
+	var started, done sync.WaitGroup
+	started.Add(callers)
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			results[i], _ = cache.GetOrLoad("key", loader)
+		}(i)
+	}
+	started.Wait()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	// Assert
+	assert.Equal(t, int32(1), loads.Load())
+	for _, result := range results {
+		assert.Equal(t, 42, result)
+	}
+}
+
+func TestGetOrLoad_RemembersMissingKeys(t *testing.T) {
+	// Arrange
+	cache := utils.NewLoadingCache[string, int](utils.NewInMemoryCache[string, int](),
+		utils.WithNegativeCaching(30*time.Millisecond, isMissing))
+	var loads int
+	loader := func() (int, error) { loads++; return 0, errMissing }
+
+	// Act
+	_, first := cache.GetOrLoad("key", loader)
+	_, second := cache.GetOrLoad("key", loader)
+	time.Sleep(50 * time.Millisecond)
+	_, expired := cache.GetOrLoad("key", loader)
+
+	// Assert - the second lookup is answered from the cache until the TTL passes
+	assert.ErrorIs(t, first, errMissing)
+	assert.ErrorIs(t, second, errMissing)
+	assert.ErrorIs(t, expired, errMissing)
+	assert.Equal(t, 2, loads)
+}
+
+func TestGetOrLoad_DoesNotRememberOtherErrors(t *testing.T) {
+	// Arrange
+	cache := utils.NewLoadingCache[string, int](utils.NewInMemoryCache[string, int](),
+		utils.WithNegativeCaching(time.Minute, isMissing))
+	failure := errors.New("connection refused")
+	var loads int
+	loader := func() (int, error) { loads++; return 0, failure }
+
+	// Act
+	_, first := cache.GetOrLoad("key", loader)
+	_, second := cache.GetOrLoad("key", loader)
+
+	// Assert
+	assert.ErrorIs(t, first, failure)
+	assert.ErrorIs(t, second, failure)
+	assert.Equal(t, 2, loads)
+}
+
+func TestGetOrLoad_SetForgetsMissingKey(t *testing.T) {
+	// Arrange
+	cache := utils.NewLoadingCache[string, int](utils.NewInMemoryCache[string, int](),
+		utils.WithNegativeCaching(time.Minute, isMissing))
+	_, err := cache.GetOrLoad("key", func() (int, error) { return 0, errMissing })
+	require.ErrorIs(t, err, errMissing)
+
+	// Act
+	cache.Set("key", 7)
+	value, err := cache.GetOrLoad("key", func() (int, error) { return 0, errMissing })
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 7, value)
+}
+
+func TestGetOrLoad_WriteDuringLoadIsNotOverwritten(t *testing.T) {
+	// Arrange
+	cache := utils.NewLoadingCache[string, int](utils.NewInMemoryCache[string, int]())
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	loaded := make(chan int)
+	go func() {
+		value, _ := cache.GetOrLoad("key", func() (int, error) {
+			close(loading)
+			<-release
+			return 1, nil
+		})
+		loaded <- value
+	}()
+	<-loading
+
+	// Act - the key changes while the old value is being loaded
+	cache.Set("key", 2)
+	close(release)
+
+	// Assert - the loader's caller gets what it loaded, but the cache keeps the newer value
+	assert.Equal(t, 1, <-loaded)
+	value, found := cache.Get("key")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+}
+
+func TestGetOrLoad_LoaderPanicReleasesWaiters(t *testing.T) {
+	// Arrange
+	cache := utils.NewLoadingCache[string, int](utils.NewInMemoryCache[string, int]())
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		defer func() { recover() }()
+		cache.GetOrLoad("key", func() (int, error) {
+			close(loading)
+			<-release
+			panic("loader failed")
+		})
+	}()
+	<-loading
+
+	// Act
+	waiter := make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad("key", func() (int, error) { return 0, nil })
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	// Assert - the waiter gets an error rather than blocking or a zero value
+	select {
+	case err := <-waiter:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not released")
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+// CacheKey is a key in a cache shared by several namespaces
+type CacheKey struct {
+	Namespace string
+	Key       any
+}
+
+// NamespacedCache is a typed view of one namespace of a shared cache.
+// Entries of other namespaces are invisible to it, so it only ever returns
+// values of type V, and namespaces share the shared cache's size bound.
+type NamespacedCache[K comparable, V any] struct {
+	store     Cache[CacheKey, any]
+	namespace string
+}
+
+// NewNamespacedCache returns the namespace of store with the given name.
+// Every view of a namespace must use the same K and V; values of another
+// type stored under the namespace read as missing.
+func NewNamespacedCache[K comparable, V any](store Cache[CacheKey, any], namespace string) *NamespacedCache[K, V] {
+	return &NamespacedCache[K, V]{
+		store:     store,
+		namespace: namespace,
+	}
+}
+
+func (c *NamespacedCache[K, V]) key(key K) CacheKey {
+	return CacheKey{Namespace: c.namespace, Key: key}
+}
+
+// Get returns the value stored under key in the namespace
+func (c *NamespacedCache[K, V]) Get(key K) (V, bool) {
+	value, found := c.store.Get(c.key(key))
+	typed, ok := value.(V)
+	return typed, found && ok
+}
+
+// Set stores value under key in the namespace
+func (c *NamespacedCache[K, V]) Set(key K, value V) {
+	c.store.Set(c.key(key), value)
+}
+
+// Delete removes key from the namespace
+func (c *NamespacedCache[K, V]) Delete(key K) {
+	c.store.Delete(c.key(key))
+}
+
+// GetAll returns a snapshot of the live entries of the namespace
+func (c *NamespacedCache[K, V]) GetAll() map[K]V {
+	snapshot := make(map[K]V)
+	for key, value := range c.store.GetAll() {
+		if key.Namespace != c.namespace {
+			continue
+		}
+		typedKey, keyOK := key.Key.(K)
+		typedValue, valueOK := value.(V)
+		if keyOK && valueOK {
+			snapshot[typedKey] = typedValue
+		}
+	}
+	return snapshot
+}
+
+// Stats returns the statistics of the shared cache, which cover every namespace
+func (c *NamespacedCache[K, V]) Stats() CacheStats {
+	return c.store.Stats()
+}
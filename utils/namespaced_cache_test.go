@@ -0,0 +1,65 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/hydr0g3nz/spd-fiber-booking-system/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacedCache_KeepsNamespacesApart(t *testing.T) {
+	// Arrange
+	store := utils.NewInMemoryCache[utils.CacheKey, any]()
+	prices := utils.NewNamespacedCache[int64, float64](store, "price")
+	names := utils.NewNamespacedCache[int64, string](store, "name")
+
+	// Act
+	prices.Set(1, 1500)
+	names.Set(1, "Deluxe room")
+
+	// Assert - the same key holds a value of each namespace
+	price, found := prices.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, 1500.0, price)
+	name, found := names.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, "Deluxe room", name)
+	assert.Equal(t, map[int64]float64{1: 1500}, prices.GetAll())
+	assert.Equal(t, 2, prices.Stats().Entries, "Stats cover the shared cache")
+}
+
+func TestNamespacedCache_Delete(t *testing.T) {
+	// Arrange
+	store := utils.NewInMemoryCache[utils.CacheKey, any]()
+	prices := utils.NewNamespacedCache[int64, float64](store, "price")
+	names := utils.NewNamespacedCache[int64, string](store, "name")
+	prices.Set(1, 1500)
+	names.Set(1, "Deluxe room")
+
+	// Act
+	prices.Delete(1)
+
+	// Assert
+	_, found := prices.Get(1)
+	assert.False(t, found)
+	_, found = names.Get(1)
+	assert.True(t, found, "Deleting from one namespace must not touch another")
+}
+
+func TestNamespacedCache_IgnoresValuesOfAnotherType(t *testing.T) {
+	// Arrange - a value of the wrong type written to the shared cache directly
+	store := utils.NewInMemoryCache[utils.CacheKey, any]()
+	prices := utils.NewNamespacedCache[int64, float64](store, "price")
+	prices.Set(1, 1500)
+	store.Set(utils.CacheKey{Namespace: "price", Key: int64(2)}, "not a price")
+	store.Set(utils.CacheKey{Namespace: "price", Key: "3"}, 3000.0)
+
+	// Act
+	value, found := prices.Get(2)
+	all := prices.GetAll()
+
+	// Assert
+	assert.False(t, found)
+	assert.Zero(t, value)
+	assert.Equal(t, map[int64]float64{1: 1500}, all)
+}